@@ -5,6 +5,8 @@ import (
 	"github.com/mieubrisse/box-layout-test/components"
 	"github.com/mieubrisse/box-layout-test/components/flexbox"
 	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/link"
+	"github.com/mieubrisse/box-layout-test/theme"
 )
 
 type BubbleBathOption func(*bubbleBathModel)
@@ -21,6 +23,35 @@ func WithQuitSequences(quitSequenceSet map[string]bool) BubbleBathOption {
 	}
 }
 
+// Controls whether links (see the link package) are output as OSC 8 hyperlinks, or as just their plain text (for
+// terminals that don't support hyperlinks)
+func WithHyperlinksEnabled(isEnabled bool) BubbleBathOption {
+	return func(model *bubbleBathModel) {
+		model.isHyperlinksEnabled = isEnabled
+	}
+}
+
+// Sets the theme the app starts with (by default, the adaptive theme that follows the terminal's background)
+// It only becomes the active theme once the program starts, so building a model doesn't change the theme for anything
+// else
+func WithTheme(startingTheme theme.Theme) BubbleBathOption {
+	return func(model *bubbleBathModel) {
+		model.startingTheme = &startingTheme
+	}
+}
+
+// SetTheme gets a command that switches the active theme while the program runs, after which the app gets a
+// theme.ChangedMsg
+func SetTheme(newTheme theme.Theme) tea.Cmd {
+	return func() tea.Msg {
+		return setThemeMsg{theme: newTheme}
+	}
+}
+
+type setThemeMsg struct {
+	theme theme.Theme
+}
+
 var defaultQuitSequenceSet = map[string]bool{
 	"ctrl+c": true,
 	"ctrl+d": true,
@@ -33,15 +64,27 @@ type bubbleBathModel struct {
 	// Sequences matching String() of tea.KeyMsg that will quit the program
 	quitSequenceSet map[string]bool
 
-	appBox components.Component
+	isHyperlinksEnabled bool
+
+	// Nil to leave the active theme as it is
+	startingTheme *theme.Theme
+
+	// Messages get routed into the app through this box
+	appBox *flexbox.Flexbox
 
 	app components.Component
 
+	// The overlays as of the last View, which get the mouse messages that land on them
+	placedOverlays []components.PlacedOverlay
+
 	width  int
 	height int
 }
 
-// NewBubbleBathModel creates a new tea.Model for tea.NewProgram based off the given InteractiveComponent
+// NewBubbleBathModel creates a new tea.Model for tea.NewProgram based off the given component
+// If the app contains interactive components (see components.InteractiveComponent), they receive the messages from the
+// bubbletea runtime and the first of them gets focused to start with
+// NOTE: mouse messages only get sent if the program is started with a mouse option like tea.WithMouseCellMotion
 func NewBubbleBathModel(app components.Component, options ...BubbleBathOption) tea.Model {
 	// We put the user's app in a box here so that we can get their app auto-resizing with the terminal
 	appBox := flexbox.New().SetChildren([]flexbox_item.FlexboxItem{
@@ -53,21 +96,28 @@ func NewBubbleBathModel(app components.Component, options ...BubbleBathOption) t
 			SetMaxHeight(flexbox_item.MaxAvailable),
 	})
 	result := &bubbleBathModel{
-		initCmd:         nil,
-		quitSequenceSet: defaultQuitSequenceSet,
-		appBox:          appBox,
-		app:             app,
-		width:           0,
-		height:          0,
+		initCmd:             nil,
+		quitSequenceSet:     defaultQuitSequenceSet,
+		isHyperlinksEnabled: true,
+		startingTheme:       nil,
+		appBox:              appBox,
+		app:                 app,
+		placedOverlays:      nil,
+		width:               0,
+		height:              0,
 	}
 	for _, opt := range options {
 		opt(result)
 	}
+	appBox.SetFocus(true)
 	return result
 }
 
 func (b bubbleBathModel) Init() tea.Cmd {
-	return b.initCmd
+	if b.startingTheme == nil {
+		return b.initCmd
+	}
+	return tea.Batch(b.switchTheme(*b.startingTheme), b.initCmd)
 }
 
 func (b *bubbleBathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -82,10 +132,18 @@ func (b *bubbleBathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		b.width = msg.Width
 		b.height = msg.Height
 		return b, nil
+	case setThemeMsg:
+		// The switch happens here rather than in the command so that it can't race with the rendering
+		return b, b.switchTheme(msg.theme)
+	case tea.MouseMsg:
+		if placed, found := components.FindOverlayAt(b.placedOverlays, msg.X, msg.Y); found && placed.HandleMouse != nil {
+			msg.X -= placed.X
+			msg.Y -= placed.Y
+			return b, placed.HandleMouse(msg)
+		}
 	}
 
-	// return b, b.appComponent.Update(msg)
-	return b, nil
+	return b, b.appBox.Update(msg)
 }
 
 func (b *bubbleBathModel) View() string {
@@ -94,14 +152,15 @@ func (b *bubbleBathModel) View() string {
 	// 2) some components do caching of the phases, so to kick the cycle off we want to make sure we call them all
 	b.appBox.GetContentMinMax()
 	b.appBox.GetContentHeightForGivenWidth(b.width)
-	return b.appBox.View(b.width, b.height)
-}
+	view := b.appBox.View(b.width, b.height)
+
+	// Overlays get drawn over everything else, so they can only be placed once everything has been laid out
+	b.placedOverlays = components.PlaceOverlays(b.appBox.GetOverlays(), b.width, b.height)
+	view = components.DrawOverlays(view, b.placedOverlays)
 
-/*
-func (b bubbleBathModel) GetAppComponent() InteractiveComponent {
-	return b.appComponent
+	// Links can only be turned into real hyperlinks once everything has been laid out
+	return link.Resolve(view, b.isHyperlinksEnabled)
 }
-*/
 
 func RunBubbleBathProgram[T components.Component](
 	appComponent T,
@@ -115,3 +174,9 @@ func RunBubbleBathProgram[T components.Component](
 	castedAppComponent := castedModel.app.(T)
 	return castedAppComponent, err
 }
+
+// Makes the theme the active one, and lets the app know
+func (b bubbleBathModel) switchTheme(newTheme theme.Theme) tea.Cmd {
+	theme.SetActive(newTheme)
+	return b.appBox.Update(theme.ChangedMsg{Theme: newTheme})
+}
@@ -0,0 +1,271 @@
+package accordion
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/collapsible"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+// NoneExpanded is the expanded index when all the sections are collapsed
+const NoneExpanded = -1
+
+// Accordion stacks collapsible sections vertically, keeping at most one of them expanded: expanding a section collapses
+// the one that was expanded before
+// While a header is focused, Up & Down move the focus between the headers (Down goes into the body of an expanded
+// section first)
+type Accordion interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Container
+
+	AddSection(section collapsible.Collapsible) Accordion
+	GetSections() []collapsible.Collapsible
+
+	// Setting the expanded section programmatically doesn't send a ChangedMsg
+	GetExpandedIndex() int
+	SetExpandedIndex(idx int) Accordion
+}
+
+// ChangedMsg is sent when the user expands or collapses a section
+type ChangedMsg struct {
+	Accordion     Accordion
+	ExpandedIndex int
+}
+
+type accordionImpl struct {
+	sections []collapsible.Collapsible
+
+	// The heights the sections got in the last View, for routing mouse messages
+	sectionHeightsCache []int
+}
+
+func New(sections ...collapsible.Collapsible) Accordion {
+	result := &accordionImpl{
+		sections:            make([]collapsible.Collapsible, 0, len(sections)),
+		sectionHeightsCache: nil,
+	}
+	for _, section := range sections {
+		result.AddSection(section)
+	}
+	return result
+}
+
+// Adding an expanded section collapses the one that was expanded before
+func (a *accordionImpl) AddSection(section collapsible.Collapsible) Accordion {
+	a.sections = append(a.sections, section)
+	if section.IsExpanded() {
+		a.collapseAllExcept(len(a.sections) - 1)
+	}
+	return a
+}
+
+func (a accordionImpl) GetChildComponents() []components.Component {
+	result := make([]components.Component, len(a.sections))
+	for idx, section := range a.sections {
+		result[idx] = section
+	}
+	return result
+}
+
+func (a accordionImpl) GetSections() []collapsible.Collapsible {
+	return a.sections
+}
+
+func (a accordionImpl) GetExpandedIndex() int {
+	for idx, section := range a.sections {
+		if section.IsExpanded() {
+			return idx
+		}
+	}
+	return NoneExpanded
+}
+
+func (a *accordionImpl) SetExpandedIndex(idx int) Accordion {
+	if idx < 0 || idx >= len(a.sections) {
+		idx = NoneExpanded
+	}
+	a.collapseAllExcept(idx)
+	if idx != NoneExpanded {
+		a.sections[idx].SetExpanded(true)
+	}
+	return a
+}
+
+func (a *accordionImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return a.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (a *accordionImpl) GetContentHeightForGivenWidth(width int) int {
+	return a.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (a *accordionImpl) View(width int, height int) string {
+	return a.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (a *accordionImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	for _, section := range a.sections {
+		sectionMinWidth, sectionMaxWidth, sectionMinHeight, sectionMaxHeight := components.GetChildContentMinMax(section, ctx)
+		minWidth = utilities.GetMaxInt(minWidth, sectionMinWidth)
+		maxWidth = utilities.GetMaxInt(maxWidth, sectionMaxWidth)
+		minHeight += sectionMinHeight
+		maxHeight += sectionMaxHeight
+	}
+	return
+}
+
+func (a *accordionImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 {
+		return 0
+	}
+	result := 0
+	for _, section := range a.sections {
+		result += components.GetChildContentHeightForGivenWidth(section, ctx, width)
+	}
+	return result
+}
+
+func (a *accordionImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	a.sectionHeightsCache = make([]int, len(a.sections))
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, height)
+	for idx, section := range a.sections {
+		sectionHeight := utilities.GetMinInt(components.GetChildContentHeightForGivenWidth(section, ctx, width), height-len(lines))
+		if sectionHeight <= 0 {
+			break
+		}
+		a.sectionHeightsCache[idx] = sectionHeight
+		lines = append(lines, strings.Split(components.ViewChild(section, ctx, width, sectionHeight), "\n")...)
+	}
+	for idx, line := range lines {
+		lines[idx] = control.FitToWidth(line, width)
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+func (a *accordionImpl) Update(msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		for idx, section := range a.sections {
+			if !section.IsHeaderFocused() {
+				continue
+			}
+			switch keyMsg.Type {
+			case tea.KeyUp:
+				a.moveHeaderFocus(idx, idx-1)
+				return nil
+			case tea.KeyDown:
+				// Let the section move the focus into its body if it can
+				cmd := section.Update(msg)
+				if section.IsHeaderFocused() {
+					a.moveHeaderFocus(idx, idx+1)
+				}
+				return cmd
+			}
+		}
+	}
+
+	previousExpandedIdx := a.GetExpandedIndex()
+	wasExpanded := make([]bool, len(a.sections))
+	for idx, section := range a.sections {
+		wasExpanded[idx] = section.IsExpanded()
+	}
+
+	cmds := make([]tea.Cmd, 0, len(a.sections)+1)
+	y := 0
+	for idx, section := range a.sections {
+		cmds = append(cmds, components.UpdateChild(section, msg, 0, y))
+		if idx < len(a.sectionHeightsCache) {
+			y += a.sectionHeightsCache[idx]
+		}
+	}
+
+	// A section the user just expanded takes over from the one that was expanded before
+	for idx, section := range a.sections {
+		if section.IsExpanded() && !wasExpanded[idx] {
+			a.collapseAllExcept(idx)
+			break
+		}
+	}
+	if expandedIdx := a.GetExpandedIndex(); expandedIdx != previousExpandedIdx {
+		changedMsg := ChangedMsg{Accordion: a, ExpandedIndex: expandedIdx}
+		cmds = append(cmds, func() tea.Msg {
+			return changedMsg
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (a *accordionImpl) SetFocus(isFocused bool) {
+	if !isFocused {
+		for _, section := range a.sections {
+			section.SetFocus(false)
+		}
+		return
+	}
+	for _, section := range a.sections {
+		section.SetFocus(true)
+		if section.IsFocused() {
+			return
+		}
+	}
+}
+
+func (a accordionImpl) IsFocused() bool {
+	for _, section := range a.sections {
+		if section.IsFocused() {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *accordionImpl) GetOverlays() []components.Overlay {
+	result := make([]components.Overlay, 0)
+	y := 0
+	for idx, section := range a.sections {
+		if idx >= len(a.sectionHeightsCache) {
+			break
+		}
+		result = append(result, components.GetChildOverlays(section, 0, y)...)
+		y += a.sectionHeightsCache[idx]
+	}
+	return result
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (a *accordionImpl) collapseAllExcept(idx int) {
+	for sectionIdx, section := range a.sections {
+		if sectionIdx != idx {
+			section.SetExpanded(false)
+		}
+	}
+}
+
+// Moves the focus to the header of the section at the new index, skipping over sections that don't accept it (e.g.
+// disabled ones)
+func (a *accordionImpl) moveHeaderFocus(fromIdx int, toIdx int) {
+	step := 1
+	if toIdx < fromIdx {
+		step = -1
+	}
+	for idx := toIdx; idx >= 0 && idx < len(a.sections); idx += step {
+		a.sections[idx].SetFocus(true)
+		if a.sections[idx].IsFocused() {
+			a.sections[fromIdx].SetFocus(false)
+			return
+		}
+	}
+}
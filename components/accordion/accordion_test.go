@@ -0,0 +1,122 @@
+package accordion
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/collapsible"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	accordion := getTestAccordion()
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 7, 3, 3),
+		test_assertions.GetRenderedContentAssertion(7, 3, "▸ One  \n▸ Two  \n▸ Three"),
+	)
+	test_assertions.CheckAll(t, assertions, accordion)
+
+	accordion.SetExpandedIndex(0)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 11, 4, 5),
+		test_assertions.GetHeightAtWidthAssertions(
+			7, 5,
+			11, 4,
+		),
+		test_assertions.GetRenderedContentAssertion(11, 4, "▾ One      \nhello world\n▸ Two      \n▸ Three    "),
+	)
+	test_assertions.CheckAll(t, assertions, accordion)
+}
+
+func TestOnlyOneSectionExpanded(t *testing.T) {
+	accordion := getTestAccordion()
+	sections := accordion.GetSections()
+
+	accordion.SetExpandedIndex(0)
+	accordion.SetExpandedIndex(1)
+	require.False(t, sections[0].IsExpanded())
+	require.Equal(t, 1, accordion.GetExpandedIndex())
+
+	// Adding an expanded section takes over too
+	extra := collapsible.New("Four", text.New("bar")).SetExpanded(true)
+	accordion.AddSection(extra)
+	require.Equal(t, 3, accordion.GetExpandedIndex())
+
+	accordion.SetExpandedIndex(NoneExpanded)
+	require.False(t, extra.IsExpanded())
+}
+
+func TestKeyboard(t *testing.T) {
+	accordion := getTestAccordion()
+	sections := accordion.GetSections()
+	accordion.SetExpandedIndex(0)
+	render(accordion, 11)
+
+	accordion.SetFocus(true)
+	require.True(t, sections[0].IsHeaderFocused())
+
+	// Down skips bodies that can't take the focus
+	accordion.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, sections[1].IsHeaderFocused())
+	require.False(t, sections[0].IsFocused())
+
+	msgs := test_assertions.GetMsgs(accordion.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.Contains(t, msgs, ChangedMsg{Accordion: accordion, ExpandedIndex: 1})
+	require.False(t, sections[0].IsExpanded())
+	require.Equal(t, "▸ One      \n▾ Two      \nfoo        \n▸ Three    ", render(accordion, 11))
+
+	accordion.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, sections[2].IsHeaderFocused())
+	accordion.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, 2, accordion.GetExpandedIndex())
+
+	// ...but goes into the ones that can
+	accordion.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, sections[2].GetBody().(button.Button).IsFocused())
+	require.False(t, sections[2].IsHeaderFocused())
+
+	sections[2].SetExpanded(false)
+	for i := 0; i < 3; i++ {
+		accordion.Update(tea.KeyMsg{Type: tea.KeyUp})
+	}
+	require.True(t, sections[0].IsHeaderFocused())
+	require.False(t, sections[2].IsFocused())
+}
+
+func TestMouse(t *testing.T) {
+	accordion := getTestAccordion()
+	accordion.SetExpandedIndex(1)
+	render(accordion, 11)
+
+	accordion.Update(tea.MouseMsg{X: 2, Y: 3, Type: tea.MouseLeft})
+	msgs := test_assertions.GetMsgs(accordion.Update(tea.MouseMsg{X: 2, Y: 3, Type: tea.MouseRelease}))
+	require.Contains(t, msgs, ChangedMsg{Accordion: accordion, ExpandedIndex: 2})
+	require.Equal(t, "▸ One      \n▸ Two      \n▾ Three    \n[ Go ]     ", render(accordion, 11))
+
+	// Collapsing the expanded section leaves them all collapsed
+	accordion.Update(tea.MouseMsg{X: 2, Y: 2, Type: tea.MouseLeft})
+	msgs = test_assertions.GetMsgs(accordion.Update(tea.MouseMsg{X: 2, Y: 2, Type: tea.MouseRelease}))
+	require.Contains(t, msgs, ChangedMsg{Accordion: accordion, ExpandedIndex: NoneExpanded})
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func getTestAccordion() Accordion {
+	return New(
+		collapsible.New("One", text.New("hello world")),
+		collapsible.New("Two", text.New("foo")),
+		collapsible.New("Three", button.New("Go")),
+	)
+}
+
+func render(accordion Accordion, width int) string {
+	accordion.GetContentMinMax()
+	return accordion.View(width, accordion.GetContentHeightForGivenWidth(width))
+}
@@ -0,0 +1,135 @@
+package button
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"strings"
+)
+
+// Button is a label that sends a PressedMsg when activated, by Enter or Space while focused or by clicking it
+// Analogous to the <button> tag in HTML
+type Button interface {
+	components.InteractiveComponent
+
+	GetLabel() string
+	SetLabel(label string) Button
+
+	IsDisabled() bool
+	SetDisabled(isDisabled bool) Button
+
+	IsPressed() bool
+
+	GetStyles() control.Styles
+	SetStyles(styles control.Styles) Button
+}
+
+// PressedMsg is sent when a button gets activated
+type PressedMsg struct {
+	Button Button
+}
+
+type buttonImpl struct {
+	label string
+
+	// Nil for the default styles
+	styles *control.Styles
+
+	state *control.State
+}
+
+func New(label string) Button {
+	return &buttonImpl{
+		label:  sanitizeLabel(label),
+		styles: nil,
+		state:  control.NewState(),
+	}
+}
+
+func (b buttonImpl) GetLabel() string {
+	return b.label
+}
+
+func (b *buttonImpl) SetLabel(label string) Button {
+	b.label = sanitizeLabel(label)
+	return b
+}
+
+func (b buttonImpl) IsDisabled() bool {
+	return b.state.IsDisabled()
+}
+
+func (b *buttonImpl) SetDisabled(isDisabled bool) Button {
+	b.state.SetDisabled(isDisabled)
+	return b
+}
+
+func (b buttonImpl) IsPressed() bool {
+	return b.state.IsPressed()
+}
+
+func (b buttonImpl) GetStyles() control.Styles {
+	if b.styles == nil {
+		return control.DefaultStyles()
+	}
+	return *b.styles
+}
+
+func (b *buttonImpl) SetStyles(styles control.Styles) Button {
+	b.styles = &styles
+	return b
+}
+
+func (b *buttonImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	width := lipgloss.Width(b.getContent())
+	return width, width, 1, 1
+}
+
+func (b *buttonImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (b *buttonImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	b.state.RecordViewSize(width, 1)
+	return control.FitToWidth(b.GetStyles().Get(b.state).Render(b.getContent()), width)
+}
+
+func (b *buttonImpl) Update(msg tea.Msg) tea.Cmd {
+	activation, cmd := b.state.Update(msg)
+	if !activation.IsActivated {
+		return cmd
+	}
+	pressedMsg := PressedMsg{Button: b}
+	return tea.Batch(cmd, func() tea.Msg {
+		return pressedMsg
+	})
+}
+
+func (b *buttonImpl) SetFocus(isFocused bool) {
+	b.state.SetFocus(isFocused)
+}
+
+func (b buttonImpl) IsFocused() bool {
+	return b.state.IsFocused()
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// The brackets show that it's a button even without any styling
+func (b buttonImpl) getContent() string {
+	return "[ " + b.label + " ]"
+}
+
+// Buttons are a single line
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
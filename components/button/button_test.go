@@ -0,0 +1,99 @@
+package button
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/theme"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSizeIsExact(t *testing.T) {
+	button := New("Save")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(8, 8, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(
+			4, 1,
+			8, 1,
+			20, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(8, 1, "[ Save ]"),
+		test_assertions.GetRenderedContentAssertion(10, 1, "[ Save ]  "),
+		test_assertions.GetRenderedContentAssertion(4, 1, "[ Sa"),
+	)
+
+	test_assertions.CheckAll(t, assertions, button)
+}
+
+func TestKeysActivateWhenFocused(t *testing.T) {
+	button := New("Save")
+	require.Nil(t, button.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+
+	button.SetFocus(true)
+	require.Nil(t, button.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}))
+
+	for _, keyType := range []tea.KeyType{tea.KeyEnter, tea.KeySpace} {
+		msgs := test_assertions.GetMsgs(button.Update(tea.KeyMsg{Type: keyType}))
+		require.True(t, button.IsPressed())
+		require.Contains(t, msgs, PressedMsg{Button: button})
+
+		// Feeding back the other message releases the press
+		for _, msg := range msgs {
+			button.Update(msg)
+		}
+		require.False(t, button.IsPressed())
+	}
+}
+
+func TestMouseClickActivates(t *testing.T) {
+	button := New("Save")
+	box := flexbox.NewWithContents(flexbox_item.New(button)).SetHorizontalAlignment(flexbox.AlignEnd)
+	box.GetContentMinMax()
+	box.GetContentHeightForGivenWidth(20)
+	box.View(20, 1)
+
+	// The button is right-aligned, so it takes up columns 12 to 19
+	require.Nil(t, box.Update(tea.MouseMsg{X: 5, Y: 0, Type: tea.MouseLeft}))
+	require.False(t, button.IsPressed())
+
+	require.Nil(t, box.Update(tea.MouseMsg{X: 12, Y: 0, Type: tea.MouseLeft}))
+	require.True(t, button.IsPressed())
+	require.Equal(t, []tea.Msg{PressedMsg{Button: button}}, test_assertions.GetMsgs(box.Update(tea.MouseMsg{X: 19, Y: 0, Type: tea.MouseRelease})))
+	require.False(t, button.IsPressed())
+
+	// Releasing off the button cancels the click
+	box.Update(tea.MouseMsg{X: 12, Y: 0, Type: tea.MouseLeft})
+	require.Nil(t, test_assertions.GetMsgs(box.Update(tea.MouseMsg{X: 2, Y: 0, Type: tea.MouseRelease})))
+	require.False(t, button.IsPressed())
+}
+
+func TestDisabled(t *testing.T) {
+	button := New("Save").SetDisabled(true)
+	button.SetFocus(true)
+	require.False(t, button.IsFocused())
+
+	button.View(8, 1)
+	require.Nil(t, button.Update(tea.MouseMsg{X: 1, Y: 0, Type: tea.MouseLeft}))
+	require.Nil(t, button.Update(tea.MouseMsg{X: 1, Y: 0, Type: tea.MouseRelease}))
+	require.False(t, button.IsPressed())
+}
+
+func TestDefaultStylesFollowTheme(t *testing.T) {
+	defer theme.SetActive(theme.GetActive())
+	button := New("Save")
+
+	theme.SetActive(theme.Light())
+	require.Equal(t, theme.Light().Color(theme.Primary), button.GetStyles().Focused.GetForeground())
+	theme.SetActive(theme.Dark())
+	require.Equal(t, theme.Dark().Color(theme.Primary), button.GetStyles().Focused.GetForeground())
+
+	// Styles that were set stay as they are
+	button.SetStyles(control.DefaultStyles())
+	theme.SetActive(theme.Light())
+	require.Equal(t, theme.Dark().Color(theme.Primary), button.GetStyles().Focused.GetForeground())
+}
@@ -0,0 +1,166 @@
+package checkbox
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"strings"
+)
+
+const (
+	checkedMarker   = "[x]"
+	uncheckedMarker = "[ ]"
+)
+
+// Checkbox is a labelled box that gets checked & unchecked when activated, by Enter or Space while focused or by
+// clicking it
+// Analogous to the <input type="checkbox"> tag in HTML
+type Checkbox interface {
+	components.InteractiveComponent
+
+	GetLabel() string
+	SetLabel(label string) Checkbox
+
+	// Setting the checked state programmatically doesn't send a ChangedMsg
+	IsChecked() bool
+	SetChecked(isChecked bool) Checkbox
+
+	IsDisabled() bool
+	SetDisabled(isDisabled bool) Checkbox
+
+	IsPressed() bool
+
+	GetStyles() control.Styles
+	SetStyles(styles control.Styles) Checkbox
+}
+
+// ChangedMsg is sent when the user checks or unchecks a checkbox
+type ChangedMsg struct {
+	Checkbox  Checkbox
+	IsChecked bool
+}
+
+type checkboxImpl struct {
+	label string
+
+	isChecked bool
+
+	// Nil for the default styles
+	styles *control.Styles
+
+	state *control.State
+}
+
+func New(label string) Checkbox {
+	return &checkboxImpl{
+		label:     sanitizeLabel(label),
+		isChecked: false,
+		styles:    nil,
+		state:     control.NewState(),
+	}
+}
+
+func (c checkboxImpl) GetLabel() string {
+	return c.label
+}
+
+func (c *checkboxImpl) SetLabel(label string) Checkbox {
+	c.label = sanitizeLabel(label)
+	return c
+}
+
+func (c checkboxImpl) IsChecked() bool {
+	return c.isChecked
+}
+
+func (c *checkboxImpl) SetChecked(isChecked bool) Checkbox {
+	c.isChecked = isChecked
+	return c
+}
+
+func (c checkboxImpl) IsDisabled() bool {
+	return c.state.IsDisabled()
+}
+
+func (c *checkboxImpl) SetDisabled(isDisabled bool) Checkbox {
+	c.state.SetDisabled(isDisabled)
+	return c
+}
+
+func (c checkboxImpl) IsPressed() bool {
+	return c.state.IsPressed()
+}
+
+func (c checkboxImpl) GetStyles() control.Styles {
+	if c.styles == nil {
+		return control.DefaultStyles()
+	}
+	return *c.styles
+}
+
+func (c *checkboxImpl) SetStyles(styles control.Styles) Checkbox {
+	c.styles = &styles
+	return c
+}
+
+func (c *checkboxImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	// Both markers are the same width, so checking doesn't change the size
+	width := lipgloss.Width(c.getContent())
+	return width, width, 1, 1
+}
+
+func (c *checkboxImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (c *checkboxImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	c.state.RecordViewSize(width, 1)
+	return control.FitToWidth(c.GetStyles().Get(c.state).Render(c.getContent()), width)
+}
+
+func (c *checkboxImpl) Update(msg tea.Msg) tea.Cmd {
+	activation, cmd := c.state.Update(msg)
+	if !activation.IsActivated {
+		return cmd
+	}
+	c.isChecked = !c.isChecked
+	changedMsg := ChangedMsg{Checkbox: c, IsChecked: c.isChecked}
+	return tea.Batch(cmd, func() tea.Msg {
+		return changedMsg
+	})
+}
+
+func (c *checkboxImpl) SetFocus(isFocused bool) {
+	c.state.SetFocus(isFocused)
+}
+
+func (c checkboxImpl) IsFocused() bool {
+	return c.state.IsFocused()
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (c checkboxImpl) getContent() string {
+	marker := uncheckedMarker
+	if c.isChecked {
+		marker = checkedMarker
+	}
+	if c.label == "" {
+		return marker
+	}
+	return marker + " " + c.label
+}
+
+// Checkboxes are a single line
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
@@ -0,0 +1,51 @@
+package checkbox
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSizeIsExact(t *testing.T) {
+	checkbox := New("Remember me")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(15, 15, 1, 1),
+		test_assertions.GetRenderedContentAssertion(15, 1, "[ ] Remember me"),
+	)
+
+	test_assertions.CheckAll(t, assertions, checkbox)
+
+	// Checking doesn't change the size
+	checkbox.SetChecked(true)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(15, 15, 1, 1),
+		test_assertions.GetRenderedContentAssertion(15, 1, "[x] Remember me"),
+	)
+	test_assertions.CheckAll(t, assertions, checkbox)
+}
+
+func TestActivationToggles(t *testing.T) {
+	checkbox := New("Remember me")
+	checkbox.SetFocus(true)
+
+	require.Contains(t, test_assertions.GetMsgs(checkbox.Update(tea.KeyMsg{Type: tea.KeySpace})), ChangedMsg{Checkbox: checkbox, IsChecked: true})
+	require.True(t, checkbox.IsChecked())
+
+	checkbox.View(15, 1)
+	checkbox.Update(tea.MouseMsg{X: 14, Y: 0, Type: tea.MouseLeft})
+	require.Equal(t, []tea.Msg{ChangedMsg{Checkbox: checkbox, IsChecked: false}}, test_assertions.GetMsgs(checkbox.Update(tea.MouseMsg{X: 14, Y: 0, Type: tea.MouseRelease})))
+	require.False(t, checkbox.IsChecked())
+}
+
+func TestDisabledIgnoresActivation(t *testing.T) {
+	checkbox := New("Remember me")
+	checkbox.SetFocus(true)
+	checkbox.SetDisabled(true)
+	require.False(t, checkbox.IsFocused())
+
+	require.Nil(t, checkbox.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.False(t, checkbox.IsChecked())
+}
@@ -0,0 +1,418 @@
+package code_block
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+// What happens to lines that are wider than the space available
+type WrapMode int
+
+const (
+	// Long lines continue onto the next row (with a blank gutter)
+	SoftWrap WrapMode = iota
+
+	// Long lines get cut off at the edge
+	Clip
+)
+
+const (
+	defaultTabSize = 4
+
+	// Enough that the gutter doesn't change width until the code has more than 999 lines
+	defaultMinGutterDigits = 3
+
+	gutterSeparator = " │ "
+)
+
+// An inclusive range of line numbers (as displayed in the gutter)
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// CodeBlock displays syntax-highlighted code
+// Analogous to the <pre><code> tags in HTML
+type CodeBlock interface {
+	components.Component
+
+	GetCode() string
+	SetCode(code string) CodeBlock
+
+	GetTokenizer() Tokenizer
+	SetTokenizer(tokenizer Tokenizer) CodeBlock
+
+	GetTheme() Theme
+	SetTheme(theme Theme) CodeBlock
+
+	GetWrapMode() WrapMode
+	SetWrapMode(mode WrapMode) CodeBlock
+
+	// The number of columns between tab stops; tabs are expanded to the next tab stop
+	GetTabSize() int
+	SetTabSize(size int) CodeBlock
+
+	GetShowLineNumbers() bool
+	SetShowLineNumbers(show bool) CodeBlock
+
+	// The number shown in the gutter for the first line, which is useful for showing an excerpt of a larger file
+	GetFirstLineNumber() int
+	SetFirstLineNumber(number int) CodeBlock
+
+	// The gutter is always at least wide enough for this many digits, so that it doesn't jump around in width as
+	// the number of lines changes
+	GetMinGutterDigits() int
+	SetMinGutterDigits(digits int) CodeBlock
+
+	GetHighlightedLines() []LineRange
+	SetHighlightedLines(ranges ...LineRange) CodeBlock
+}
+
+type codeBlockImpl struct {
+	code string
+
+	tokenizer Tokenizer
+
+	// Nil for the default theme
+	theme *Theme
+
+	wrapMode WrapMode
+
+	tabSize int
+
+	showLineNumbers bool
+
+	firstLineNumber int
+
+	minGutterDigits int
+
+	highlightedLines []LineRange
+
+	// The tokenized code, split into lines (so none of the tokens contain newlines)
+	// Recalculated whenever any of the inputs to it change
+	lines [][]Token
+}
+
+func New(code string) CodeBlock {
+	result := &codeBlockImpl{
+		code:             code,
+		tokenizer:        PlainTokenizer,
+		theme:            nil,
+		wrapMode:         SoftWrap,
+		tabSize:          defaultTabSize,
+		showLineNumbers:  false,
+		firstLineNumber:  1,
+		minGutterDigits:  defaultMinGutterDigits,
+		highlightedLines: nil,
+		lines:            nil,
+	}
+	result.updateLines()
+	return result
+}
+
+func (c codeBlockImpl) GetCode() string {
+	return c.code
+}
+
+func (c *codeBlockImpl) SetCode(code string) CodeBlock {
+	c.code = code
+	c.updateLines()
+	return c
+}
+
+func (c codeBlockImpl) GetTokenizer() Tokenizer {
+	return c.tokenizer
+}
+
+func (c *codeBlockImpl) SetTokenizer(tokenizer Tokenizer) CodeBlock {
+	c.tokenizer = tokenizer
+	c.updateLines()
+	return c
+}
+
+func (c codeBlockImpl) GetTheme() Theme {
+	if c.theme == nil {
+		return DefaultTheme()
+	}
+	return *c.theme
+}
+
+func (c *codeBlockImpl) SetTheme(theme Theme) CodeBlock {
+	c.theme = &theme
+	return c
+}
+
+func (c codeBlockImpl) GetWrapMode() WrapMode {
+	return c.wrapMode
+}
+
+func (c *codeBlockImpl) SetWrapMode(mode WrapMode) CodeBlock {
+	c.wrapMode = mode
+	return c
+}
+
+func (c codeBlockImpl) GetTabSize() int {
+	return c.tabSize
+}
+
+func (c *codeBlockImpl) SetTabSize(size int) CodeBlock {
+	c.tabSize = size
+	c.updateLines()
+	return c
+}
+
+func (c codeBlockImpl) GetShowLineNumbers() bool {
+	return c.showLineNumbers
+}
+
+func (c *codeBlockImpl) SetShowLineNumbers(show bool) CodeBlock {
+	c.showLineNumbers = show
+	return c
+}
+
+func (c codeBlockImpl) GetFirstLineNumber() int {
+	return c.firstLineNumber
+}
+
+func (c *codeBlockImpl) SetFirstLineNumber(number int) CodeBlock {
+	c.firstLineNumber = number
+	return c
+}
+
+func (c codeBlockImpl) GetMinGutterDigits() int {
+	return c.minGutterDigits
+}
+
+func (c *codeBlockImpl) SetMinGutterDigits(digits int) CodeBlock {
+	c.minGutterDigits = digits
+	return c
+}
+
+func (c codeBlockImpl) GetHighlightedLines() []LineRange {
+	return c.highlightedLines
+}
+
+func (c *codeBlockImpl) SetHighlightedLines(ranges ...LineRange) CodeBlock {
+	c.highlightedLines = ranges
+	return c
+}
+
+func (c *codeBlockImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	gutterWidth := c.getGutterWidth()
+
+	// Lines can be broken (or clipped) anywhere, so the narrowest we can go is the widest single character
+	minContentWidth := 0
+	maxContentWidth := 0
+	for _, line := range c.lines {
+		maxContentWidth = utilities.GetMaxInt(maxContentWidth, getTokensWidth(line))
+		for _, token := range line {
+			for _, r := range token.Text {
+				minContentWidth = utilities.GetMaxInt(minContentWidth, runewidth.RuneWidth(r))
+			}
+		}
+	}
+
+	minWidth = gutterWidth + minContentWidth
+	maxWidth = gutterWidth + maxContentWidth
+
+	minHeight = len(c.lines)
+	maxHeight = len(c.getRows(minContentWidth))
+	return
+}
+
+func (c *codeBlockImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return len(c.getRows(width - c.getGutterWidth()))
+}
+
+func (c *codeBlockImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	contentWidth := utilities.GetMaxInt(0, width-c.getGutterWidth())
+	rows := c.getRows(contentWidth)
+	if len(rows) > height {
+		rows = rows[:height]
+	}
+
+	renderedRows := make([]string, len(rows))
+	for idx, row := range rows {
+		renderedRows[idx] = c.renderRow(row, contentWidth)
+	}
+
+	// Truncate, in case the gutter alone is too wide
+	return lipgloss.NewStyle().MaxWidth(width).Render(strings.Join(renderedRows, "\n"))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// A single row of output, which is either a whole line or a piece of one
+type row struct {
+	// Index into the lines
+	lineIdx int
+
+	// True if this row is the wrapped-around remainder of a line (so doesn't get a line number)
+	isContinuation bool
+
+	tokens []Token
+}
+
+func (c *codeBlockImpl) updateLines() {
+	expanded := text.ExpandTabs(c.code, c.tabSize)
+	tokens := c.tokenizer.Tokenize(expanded)
+
+	lines := [][]Token{{}}
+	for _, token := range tokens {
+		for idx, piece := range strings.Split(token.Text, "\n") {
+			if idx > 0 {
+				lines = append(lines, []Token{})
+			}
+			if piece == "" {
+				continue
+			}
+			lastLineIdx := len(lines) - 1
+			lines[lastLineIdx] = append(lines[lastLineIdx], Token{Kind: token.Kind, Text: piece})
+		}
+	}
+	c.lines = lines
+}
+
+func (c *codeBlockImpl) getGutterDigits() int {
+	lastLineNumber := c.firstLineNumber + len(c.lines) - 1
+	return utilities.GetMaxInt(c.minGutterDigits, len(fmt.Sprint(lastLineNumber)))
+}
+
+func (c *codeBlockImpl) getGutterWidth() int {
+	if !c.showLineNumbers {
+		return 0
+	}
+	return c.getGutterDigits() + lipgloss.Width(gutterSeparator)
+}
+
+// Lays the lines out into rows, given the width available for the code
+func (c *codeBlockImpl) getRows(contentWidth int) []row {
+	result := make([]row, 0, len(c.lines))
+	for lineIdx, line := range c.lines {
+		if c.wrapMode == Clip || contentWidth <= 0 {
+			result = append(result, row{lineIdx: lineIdx, isContinuation: false, tokens: clipTokens(line, contentWidth)})
+			continue
+		}
+
+		for pieceIdx, piece := range wrapTokens(line, contentWidth) {
+			result = append(result, row{lineIdx: lineIdx, isContinuation: pieceIdx > 0, tokens: piece})
+		}
+	}
+	return result
+}
+
+func (c *codeBlockImpl) isLineHighlighted(lineNumber int) bool {
+	for _, lineRange := range c.highlightedLines {
+		if lineNumber >= lineRange.Start && lineNumber <= lineRange.End {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *codeBlockImpl) renderRow(row row, contentWidth int) string {
+	lineNumber := c.firstLineNumber + row.lineIdx
+	codeTheme := c.GetTheme()
+
+	lineStyle := lipgloss.NewStyle()
+	if c.isLineHighlighted(lineNumber) {
+		lineStyle = codeTheme.Highlight
+	}
+
+	var builder strings.Builder
+	if c.showLineNumbers {
+		numberStr := ""
+		if !row.isContinuation {
+			numberStr = fmt.Sprint(lineNumber)
+		}
+		gutter := fmt.Sprintf("%*s%s", c.getGutterDigits(), numberStr, gutterSeparator)
+		builder.WriteString(codeTheme.Gutter.Render(gutter))
+	}
+
+	for _, token := range row.tokens {
+		tokenStyle, found := codeTheme.Tokens[token.Kind]
+		if !found {
+			tokenStyle = lipgloss.NewStyle()
+		}
+		builder.WriteString(tokenStyle.Copy().Inherit(lineStyle).Render(token.Text))
+	}
+
+	// Fill out the rest of the row, so that highlighting covers the full width
+	paddingWidth := contentWidth - getTokensWidth(row.tokens)
+	if paddingWidth > 0 {
+		builder.WriteString(lineStyle.Render(strings.Repeat(" ", paddingWidth)))
+	}
+	return builder.String()
+}
+
+func getTokensWidth(tokens []Token) int {
+	result := 0
+	for _, token := range tokens {
+		result += runewidth.StringWidth(token.Text)
+	}
+	return result
+}
+
+// Breaks a line into pieces that are each at most the given width, splitting tokens as necessary
+// Always returns at least one piece, so that empty lines still take up a row
+func wrapTokens(line []Token, width int) [][]Token {
+	result := [][]Token{{}}
+	currentPieceWidth := 0
+	for _, token := range line {
+		var tokenPiece strings.Builder
+		for _, r := range token.Text {
+			runeWidth := runewidth.RuneWidth(r)
+			if currentPieceWidth+runeWidth > width && currentPieceWidth > 0 {
+				lastIdx := len(result) - 1
+				if tokenPiece.Len() > 0 {
+					result[lastIdx] = append(result[lastIdx], Token{Kind: token.Kind, Text: tokenPiece.String()})
+					tokenPiece.Reset()
+				}
+				result = append(result, []Token{})
+				currentPieceWidth = 0
+			}
+			tokenPiece.WriteRune(r)
+			currentPieceWidth += runeWidth
+		}
+		if tokenPiece.Len() > 0 {
+			lastIdx := len(result) - 1
+			result[lastIdx] = append(result[lastIdx], Token{Kind: token.Kind, Text: tokenPiece.String()})
+		}
+	}
+	return result
+}
+
+// Cuts off the tokens of a line beyond the given width
+func clipTokens(line []Token, width int) []Token {
+	result := make([]Token, 0, len(line))
+	remainingWidth := width
+	for _, token := range line {
+		tokenWidth := runewidth.StringWidth(token.Text)
+		if tokenWidth <= remainingWidth {
+			result = append(result, token)
+			remainingWidth -= tokenWidth
+			continue
+		}
+
+		clipped := runewidth.Truncate(token.Text, remainingWidth, "")
+		if clipped != "" {
+			result = append(result, Token{Kind: token.Kind, Text: clipped})
+		}
+		break
+	}
+	return result
+}
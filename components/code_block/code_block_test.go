@@ -0,0 +1,137 @@
+package code_block
+
+import (
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const goCode = "package main\nfunc main() {\n\treturn 12345678\n}"
+
+func TestSoftWrap(t *testing.T) {
+	component := New(goCode).SetTokenizer(GoTokenizer)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(1, 19, 4, 45),
+		test_assertions.GetHeightAtWidthAssertions(
+			8, 8,
+			19, 4,
+		),
+		test_assertions.GetRenderedContentAssertion(
+			8,
+			8,
+			"package \nmain    \nfunc mai\nn() {   \n    retu\nrn 12345\n678     \n}       ",
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestLineNumbers(t *testing.T) {
+	component := New(goCode).SetTokenizer(GoTokenizer).SetShowLineNumbers(true)
+
+	// The gutter is 3 digits plus the separator
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 25, 4, 45),
+		test_assertions.GetHeightAtWidthAssertions(
+			14, 8,
+			25, 4,
+		),
+		test_assertions.GetRenderedContentAssertion(
+			14,
+			8,
+			"  1 │ package \n    │ main    \n  2 │ func mai\n    │ n() {   \n  3 │     retu\n    │ rn 12345\n    │ 678     \n  4 │ }       ",
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestGutterWidthIsStable(t *testing.T) {
+	component := New("one line").SetShowLineNumbers(true)
+	minWidthBefore, _, _, _ := component.GetContentMinMax()
+
+	component.SetCode(strings.Repeat("x\n", 98) + "x")
+	minWidthAfter, _, _, _ := component.GetContentMinMax()
+	require.Equal(t, minWidthBefore, minWidthAfter)
+
+	// Only once the line numbers no longer fit in the min digits does the gutter grow
+	component.SetFirstLineNumber(1000)
+	minWidthAfterGrowth, _, _, _ := component.GetContentMinMax()
+	require.Equal(t, minWidthBefore+1, minWidthAfterGrowth)
+}
+
+func TestClip(t *testing.T) {
+	component := New(goCode).SetTokenizer(GoTokenizer).SetShowLineNumbers(true).SetWrapMode(Clip)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 25, 4, 4),
+		test_assertions.GetHeightAtWidthAssertions(
+			7, 4,
+			14, 4,
+			100, 4,
+		),
+		test_assertions.GetRenderedContentAssertion(
+			14,
+			4,
+			"  1 │ package \n  2 │ func mai\n  3 │     retu\n  4 │ }       ",
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestHighlightedLines(t *testing.T) {
+	component := New(goCode).SetHighlightedLines(LineRange{Start: 2, End: 3}).(*codeBlockImpl)
+	require.False(t, component.isLineHighlighted(1))
+	require.True(t, component.isLineHighlighted(2))
+	require.True(t, component.isLineHighlighted(3))
+	require.False(t, component.isLineHighlighted(4))
+}
+
+func TestTokenizersRoundTrip(t *testing.T) {
+	samples := map[string]string{
+		"go":    "package main\n\n/* block\ncomment */\nfunc main() {\n\tx := `raw\nstring`\n}",
+		"json":  "{\"a\": [1, -2.5e3, true, null], \"b\" : \"str\"}",
+		"yaml":  "---\nkey: value # comment\nlist:\n  - item: 3\nflow: [a, b]\nanchor: &x !tag foo\n",
+		"shell": "# comment\nFOO=bar echo \"$HOME\" ${X} | grep -v 12 > out.txt && if true; then ls; fi\n",
+	}
+
+	for language, code := range samples {
+		tokenizer, found := GetTokenizerForLanguage(language)
+		require.True(t, found)
+
+		var builder strings.Builder
+		for _, token := range tokenizer.Tokenize(code) {
+			builder.WriteString(token.Text)
+		}
+		require.Equal(t, code, builder.String(), "Tokenizing %v code didn't preserve the text", language)
+	}
+}
+
+func TestTokenKinds(t *testing.T) {
+	requireTokenKind(t, GoTokenizer, "func main() {}", "func", TokenKeyword)
+	requireTokenKind(t, GoTokenizer, "func main() {}", "main", TokenFunction)
+	requireTokenKind(t, GoTokenizer, "var x string // note", "// note", TokenComment)
+	requireTokenKind(t, JSONTokenizer, "{\"key\": \"value\"}", "\"key\"", TokenKey)
+	requireTokenKind(t, JSONTokenizer, "{\"key\": \"value\"}", "\"value\"", TokenString)
+	requireTokenKind(t, YAMLTokenizer, "- name: 42", "name", TokenKey)
+	requireTokenKind(t, YAMLTokenizer, "- name: 42", "42", TokenNumber)
+	requireTokenKind(t, YAMLTokenizer, "url: http://host:80", "http://host:80", TokenString)
+	requireTokenKind(t, ShellTokenizer, "ls -la $HOME | wc", "wc", TokenFunction)
+	requireTokenKind(t, ShellTokenizer, "ls -la $HOME | wc", "$HOME", TokenVariable)
+}
+
+func requireTokenKind(t *testing.T, tokenizer Tokenizer, code string, tokenText string, expectedKind TokenKind) {
+	for _, token := range tokenizer.Tokenize(code) {
+		if token.Text == tokenText {
+			require.Equal(t, expectedKind, token.Kind, "Token '%v' in code '%v' was the wrong kind", tokenText, code)
+			return
+		}
+	}
+	require.Fail(t, "Token not found", "Didn't find token '%v' in code '%v'", tokenText, code)
+}
@@ -0,0 +1,61 @@
+package code_block
+
+var goKeywords = makeWordSet(
+	"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go",
+	"goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type",
+	"var",
+)
+
+var goTypes = makeWordSet(
+	"any", "bool", "byte", "comparable", "complex64", "complex128", "error", "float32", "float64", "int", "int8",
+	"int16", "int32", "int64", "rune", "string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+)
+
+var goLiterals = makeWordSet("true", "false", "nil", "iota")
+
+// Tokenizes Go source code
+var GoTokenizer Tokenizer = TokenizerFunc(func(code string) []Token {
+	s := newScanner(code)
+	for !s.isDone() {
+		r := s.peek(0)
+		switch {
+		case isSpace(r):
+			s.advanceWhile(isSpace)
+			s.emit(TokenPlain)
+		case s.hasPrefix("//"):
+			s.advanceWhile(func(r rune) bool { return r != '\n' })
+			s.emit(TokenComment)
+		case s.hasPrefix("/*"):
+			s.advancePast("*/")
+			s.emit(TokenComment)
+		case r == '"' || r == '\'':
+			s.advancePastQuoted(true, false)
+			s.emit(TokenString)
+		case r == '`':
+			s.advancePastQuoted(false, true)
+			s.emit(TokenString)
+		case isDigit(r) || (r == '.' && isDigit(s.peek(1))):
+			s.advanceWhile(isNumberPart)
+			s.emit(TokenNumber)
+		case isIdentifierStart(r):
+			s.advanceWhile(isIdentifierPart)
+			word := s.getCurrentTokenText()
+			switch {
+			case goKeywords[word]:
+				s.emit(TokenKeyword)
+			case goTypes[word]:
+				s.emit(TokenType)
+			case goLiterals[word]:
+				s.emit(TokenLiteral)
+			case s.peek(0) == '(':
+				s.emit(TokenFunction)
+			default:
+				s.emit(TokenPlain)
+			}
+		default:
+			s.advance(1)
+			s.emit(TokenPunctuation)
+		}
+	}
+	return s.tokens
+})
@@ -0,0 +1,48 @@
+package code_block
+
+var jsonLiterals = makeWordSet("true", "false", "null")
+
+// Tokenizes JSON, highlighting object keys differently from string values
+var JSONTokenizer Tokenizer = TokenizerFunc(func(code string) []Token {
+	s := newScanner(code)
+	for !s.isDone() {
+		r := s.peek(0)
+		switch {
+		case isSpace(r):
+			s.advanceWhile(isSpace)
+			s.emit(TokenPlain)
+		case r == '"':
+			s.advancePastQuoted(true, false)
+			if isFollowedByColon(s) {
+				s.emit(TokenKey)
+			} else {
+				s.emit(TokenString)
+			}
+		case isDigit(r) || r == '-':
+			s.advance(1)
+			s.advanceWhile(func(r rune) bool { return isNumberPart(r) || r == '+' || r == '-' })
+			s.emit(TokenNumber)
+		case isIdentifierStart(r):
+			s.advanceWhile(isIdentifierPart)
+			if jsonLiterals[s.getCurrentTokenText()] {
+				s.emit(TokenLiteral)
+			} else {
+				s.emit(TokenPlain)
+			}
+		default:
+			s.advance(1)
+			s.emit(TokenPunctuation)
+		}
+	}
+	return s.tokens
+})
+
+// Whether the next non-whitespace rune is a colon (meaning the string just scanned is an object key)
+func isFollowedByColon(s *scanner) bool {
+	for offset := 0; ; offset++ {
+		r := s.peek(offset)
+		if r == 0 || !isSpace(r) {
+			return r == ':'
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package code_block
+
+import "strings"
+
+var shellKeywords = makeWordSet(
+	"if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done", "case", "esac", "in", "function",
+	"select", "return", "export", "local", "readonly",
+)
+
+// Keywords after which a command is expected
+var shellCommandStartingKeywords = makeWordSet("if", "then", "else", "elif", "while", "until", "do")
+
+// Tokenizes POSIX-style shell, highlighting the command names
+var ShellTokenizer Tokenizer = TokenizerFunc(func(code string) []Token {
+	s := newScanner(code)
+
+	// Whether the next word will be a command (as opposed to an argument)
+	isExpectingCommand := true
+
+	for !s.isDone() {
+		r := s.peek(0)
+		isAtWordStart := s.position == 0 || isSpace(s.peek(-1)) || strings.ContainsRune("|&;()", s.peek(-1))
+		switch {
+		case r == '\n':
+			s.advance(1)
+			s.emit(TokenPlain)
+			isExpectingCommand = true
+		case isSpace(r):
+			s.advanceWhile(func(r rune) bool { return isSpace(r) && r != '\n' })
+			s.emit(TokenPlain)
+		case r == '\\':
+			// Line continuations & escaped characters
+			s.advance(2)
+			s.emit(TokenPlain)
+		case r == '#' && isAtWordStart:
+			s.advanceWhile(func(r rune) bool { return r != '\n' })
+			s.emit(TokenComment)
+		case r == '"':
+			s.advancePastQuoted(true, true)
+			s.emit(TokenString)
+			isExpectingCommand = false
+		case r == '\'':
+			s.advancePastQuoted(false, true)
+			s.emit(TokenString)
+			isExpectingCommand = false
+		case r == '$':
+			advancePastShellVariable(s)
+			s.emit(TokenVariable)
+			isExpectingCommand = false
+		case strings.ContainsRune("|&;()<>", r):
+			s.advanceWhile(func(r rune) bool { return strings.ContainsRune("|&;<>", r) })
+			if s.position == s.tokenStart {
+				s.advance(1)
+			}
+			operator := s.getCurrentTokenText()
+			s.emit(TokenPunctuation)
+			// Redirections are followed by a filename, but everything else starts a new command
+			isExpectingCommand = !strings.ContainsAny(operator, "<>")
+		default:
+			s.advanceWhile(func(r rune) bool { return !isSpace(r) && !strings.ContainsRune("|&;()<>\"'$", r) })
+			word := s.getCurrentTokenText()
+			switch {
+			case shellKeywords[word]:
+				s.emit(TokenKeyword)
+				isExpectingCommand = shellCommandStartingKeywords[word]
+			case isExpectingCommand && strings.Contains(word, "="):
+				// An environment variable assignment before the command
+				s.emit(TokenVariable)
+			case isExpectingCommand:
+				s.emit(TokenFunction)
+				isExpectingCommand = false
+			case isShellNumber(word):
+				s.emit(TokenNumber)
+			default:
+				s.emit(TokenPlain)
+			}
+		}
+	}
+	return s.tokens
+})
+
+// Advances past a variable reference, where the '$' is at the current position
+func advancePastShellVariable(s *scanner) {
+	s.advance(1)
+	switch r := s.peek(0); {
+	case r == '{':
+		s.advancePast("}")
+	case r == '(':
+		// Command substitution, which we don't try to tokenize the inside of
+		s.advancePast(")")
+	case isIdentifierStart(r):
+		s.advanceWhile(isIdentifierPart)
+	case r != 0 && !isSpace(r):
+		// Special parameters like $1, $@, and $?
+		s.advance(1)
+	}
+}
+
+func isShellNumber(word string) bool {
+	for _, r := range word {
+		if !isDigit(r) {
+			return false
+		}
+	}
+	return word != ""
+}
@@ -0,0 +1,38 @@
+package code_block
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Theme controls the colors of a CodeBlock
+type Theme struct {
+	// Token kinds without an entry here are left unstyled
+	Tokens map[TokenKind]lipgloss.Style
+
+	// Used for the line numbers and the line separating them from the code
+	Gutter lipgloss.Style
+
+	// Layered underneath the token styles on highlighted lines, so this will usually just be a background color
+	Highlight lipgloss.Style
+}
+
+// DefaultTheme is built from the active theme's colors
+func DefaultTheme() Theme {
+	return Theme{
+		Tokens: map[TokenKind]lipgloss.Style{
+			TokenKeyword:     lipgloss.NewStyle().Foreground(theme.Color(theme.Secondary)),
+			TokenType:        lipgloss.NewStyle().Foreground(theme.Color(theme.Primary)),
+			TokenFunction:    lipgloss.NewStyle().Foreground(theme.Color(theme.Primary)),
+			TokenString:      lipgloss.NewStyle().Foreground(theme.Color(theme.Success)),
+			TokenNumber:      lipgloss.NewStyle().Foreground(theme.Color(theme.Warning)),
+			TokenLiteral:     lipgloss.NewStyle().Foreground(theme.Color(theme.Warning)),
+			TokenComment:     lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)).Italic(true),
+			TokenPunctuation: lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)),
+			TokenKey:         lipgloss.NewStyle().Foreground(theme.Color(theme.Primary)),
+			TokenVariable:    lipgloss.NewStyle().Foreground(theme.Color(theme.Danger)),
+		},
+		Gutter:    lipgloss.NewStyle().Foreground(theme.Color(theme.Border)),
+		Highlight: lipgloss.NewStyle().Background(theme.Color(theme.Selection)),
+	}
+}
@@ -0,0 +1,217 @@
+package code_block
+
+import "strings"
+
+// The kind of a token, which determines how it gets highlighted
+type TokenKind int
+
+const (
+	TokenPlain TokenKind = iota
+	TokenKeyword
+	// Built-in types, and things that act like them (e.g. YAML tags)
+	TokenType
+	// Function names in Go, and commands in shell
+	TokenFunction
+	TokenString
+	TokenNumber
+	// Built-in constants (e.g. true, false, nil)
+	TokenLiteral
+	TokenComment
+	TokenPunctuation
+	// Object keys in JSON & YAML
+	TokenKey
+	// Shell variables, and YAML anchors & aliases
+	TokenVariable
+)
+
+// A piece of code that gets highlighted as one unit
+type Token struct {
+	Kind TokenKind
+
+	// The token's text, which may contain newlines (e.g. a multiline comment)
+	Text string
+}
+
+// A Tokenizer splits code into tokens for highlighting
+// Concatenating the text of the returned tokens must give back the input code exactly
+type Tokenizer interface {
+	Tokenize(code string) []Token
+}
+
+// Adapter to allow a plain function to be used as a Tokenizer
+type TokenizerFunc func(code string) []Token
+
+func (f TokenizerFunc) Tokenize(code string) []Token {
+	return f(code)
+}
+
+// Doesn't do any highlighting
+var PlainTokenizer Tokenizer = TokenizerFunc(func(code string) []Token {
+	return []Token{{Kind: TokenPlain, Text: code}}
+})
+
+// Gets the built-in tokenizer for the given language name (as used on a Markdown code fence, e.g. "go" or "yml")
+func GetTokenizerForLanguage(language string) (Tokenizer, bool) {
+	switch strings.ToLower(language) {
+	case "go", "golang":
+		return GoTokenizer, true
+	case "json":
+		return JSONTokenizer, true
+	case "yaml", "yml":
+		return YAMLTokenizer, true
+	case "sh", "shell", "bash", "zsh", "console":
+		return ShellTokenizer, true
+	default:
+		return nil, false
+	}
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Helper for writing tokenizers, which walks over the code and collects tokens
+type scanner struct {
+	runes []rune
+
+	// Index of the next rune to be consumed
+	position int
+
+	// Index of the first rune of the token currently being scanned
+	tokenStart int
+
+	tokens []Token
+}
+
+func newScanner(code string) *scanner {
+	return &scanner{
+		runes:      []rune(code),
+		position:   0,
+		tokenStart: 0,
+		tokens:     make([]Token, 0),
+	}
+}
+
+func (s *scanner) isDone() bool {
+	return s.position >= len(s.runes)
+}
+
+// Returns the rune at the given offset from the current position, or 0 if it's out of bounds
+func (s *scanner) peek(offset int) rune {
+	idx := s.position + offset
+	if idx < 0 || idx >= len(s.runes) {
+		return 0
+	}
+	return s.runes[idx]
+}
+
+func (s *scanner) hasPrefix(prefix string) bool {
+	for idx, r := range []rune(prefix) {
+		if s.peek(idx) != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *scanner) advance(numRunes int) {
+	s.position += numRunes
+	if s.position > len(s.runes) {
+		s.position = len(s.runes)
+	}
+}
+
+func (s *scanner) advanceWhile(predicate func(r rune) bool) {
+	for !s.isDone() && predicate(s.peek(0)) {
+		s.position++
+	}
+}
+
+// Advances until just past the given terminator (or to the end, if it's never found)
+func (s *scanner) advancePast(terminator string) {
+	for !s.isDone() && !s.hasPrefix(terminator) {
+		s.position++
+	}
+	s.advance(len([]rune(terminator)))
+}
+
+// Advances past a quoted string, where the opening quote is at the current position
+// Backslash escapes are honored if requested, and the string ends at a newline if it can't span lines
+func (s *scanner) advancePastQuoted(allowEscapes bool, allowNewlines bool) {
+	quote := s.peek(0)
+	s.position++
+	for !s.isDone() {
+		r := s.peek(0)
+		switch {
+		case allowEscapes && r == '\\':
+			s.advance(2)
+		case r == '\n' && !allowNewlines:
+			return
+		case r == quote:
+			s.position++
+			return
+		default:
+			s.position++
+		}
+	}
+}
+
+func (s *scanner) getCurrentTokenText() string {
+	return string(s.runes[s.tokenStart:s.position])
+}
+
+// Emits everything scanned since the last emit as a token of the given kind
+func (s *scanner) emit(kind TokenKind) {
+	if s.position == s.tokenStart {
+		return
+	}
+
+	text := s.getCurrentTokenText()
+	s.tokenStart = s.position
+
+	// Merge with the previous token where possible, to keep the number of tokens down
+	if numTokens := len(s.tokens); numTokens > 0 && s.tokens[numTokens-1].Kind == kind {
+		s.tokens[numTokens-1].Text += text
+		return
+	}
+	s.tokens = append(s.tokens, Token{Kind: kind, Text: text})
+}
+
+// Gets the previous non-whitespace rune before the current token, or 0 if there is none
+func (s *scanner) getPreviousNonSpaceRune() rune {
+	for idx := s.tokenStart - 1; idx >= 0; idx-- {
+		if !isSpace(s.runes[idx]) {
+			return s.runes[idx]
+		}
+	}
+	return 0
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 0x7f
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || isDigit(r)
+}
+
+// Loose matcher for the body of a number, covering things like hex, floats, and exponents
+func isNumberPart(r rune) bool {
+	return isIdentifierPart(r) || r == '.'
+}
+
+func makeWordSet(words ...string) map[string]bool {
+	result := make(map[string]bool, len(words))
+	for _, word := range words {
+		result[word] = true
+	}
+	return result
+}
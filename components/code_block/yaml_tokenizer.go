@@ -0,0 +1,130 @@
+package code_block
+
+import "strings"
+
+var yamlLiterals = makeWordSet("true", "false", "yes", "no", "on", "off", "null", "~")
+
+// Tokenizes YAML, highlighting mapping keys differently from their values
+var YAMLTokenizer Tokenizer = TokenizerFunc(func(code string) []Token {
+	s := newScanner(code)
+
+	// Whether we've only seen indentation on the current line so far
+	isAtLineStart := true
+
+	// How deeply nested in flow collections (e.g. "[a, b]") we are, where commas & brackets end scalars
+	flowDepth := 0
+
+	for !s.isDone() {
+		r := s.peek(0)
+		switch {
+		case r == '\n':
+			s.advance(1)
+			s.emit(TokenPlain)
+			isAtLineStart = true
+			continue
+		case isSpace(r):
+			s.advanceWhile(func(r rune) bool { return isSpace(r) && r != '\n' })
+			s.emit(TokenPlain)
+			continue
+		case r == '#' && (s.position == 0 || isSpace(s.peek(-1))):
+			s.advanceWhile(func(r rune) bool { return r != '\n' })
+			s.emit(TokenComment)
+		case isAtLineStart && (s.hasPrefix("---") || s.hasPrefix("...")) && isYamlSeparator(s.peek(3)):
+			s.advance(3)
+			s.emit(TokenPunctuation)
+		case r == '-' && isYamlSeparator(s.peek(1)):
+			// A sequence item, after which there can still be a key on the same line
+			s.advance(1)
+			s.emit(TokenPunctuation)
+			continue
+		case r == ':' && isYamlSeparator(s.peek(1)):
+			s.advance(1)
+			s.emit(TokenPunctuation)
+		case strings.ContainsRune("[{", r):
+			flowDepth++
+			s.advance(1)
+			s.emit(TokenPunctuation)
+		case strings.ContainsRune("]}", r):
+			flowDepth--
+			s.advance(1)
+			s.emit(TokenPunctuation)
+		case strings.ContainsRune(",|>", r):
+			s.advance(1)
+			s.emit(TokenPunctuation)
+		case r == '&' || r == '*':
+			s.advanceWhile(func(r rune) bool { return !isSpace(r) && !strings.ContainsRune(",[]{}", r) })
+			s.emit(TokenVariable)
+		case r == '!':
+			s.advanceWhile(func(r rune) bool { return !isSpace(r) })
+			s.emit(TokenType)
+		case r == '"' || r == '\'':
+			s.advancePastQuoted(r == '"', true)
+			if s.peek(0) == ':' && isYamlSeparator(s.peek(1)) {
+				s.emit(TokenKey)
+			} else {
+				s.emit(TokenString)
+			}
+		default:
+			advancePastYamlPlainScalar(s, flowDepth > 0)
+			if s.position == s.tokenStart {
+				// Guarantees progress on input that doesn't match anything (e.g. a stray closing bracket)
+				s.advance(1)
+			}
+			if s.peek(0) == ':' && isYamlSeparator(s.peek(1)) {
+				s.emit(TokenKey)
+			} else {
+				s.emit(getYamlScalarKind(s.getCurrentTokenText()))
+			}
+		}
+		isAtLineStart = false
+	}
+	return s.tokens
+})
+
+// Whether the rune can come after a YAML indicator (e.g. the ':' after a key)
+func isYamlSeparator(r rune) bool {
+	return r == 0 || isSpace(r)
+}
+
+// Advances past an unquoted scalar, which ends at a key's colon, a comment, or the end of the line
+// Trailing whitespace is left behind
+func advancePastYamlPlainScalar(s *scanner, isInFlowCollection bool) {
+	lastNonSpacePosition := s.position
+	for !s.isDone() {
+		r := s.peek(0)
+		isEnd := r == '\n' ||
+			(r == ':' && isYamlSeparator(s.peek(1))) ||
+			(r == '#' && isSpace(s.peek(-1))) ||
+			(isInFlowCollection && strings.ContainsRune(",[]{}", r))
+		if isEnd {
+			break
+		}
+		s.advance(1)
+		if !isSpace(r) {
+			lastNonSpacePosition = s.position
+		}
+	}
+	s.position = lastNonSpacePosition
+}
+
+func getYamlScalarKind(scalar string) TokenKind {
+	if yamlLiterals[strings.ToLower(scalar)] {
+		return TokenLiteral
+	}
+
+	hasDigit := false
+	for idx, r := range scalar {
+		switch {
+		case isDigit(r):
+			hasDigit = true
+		case r == '.' || r == 'e' || r == 'E' || r == '_':
+		case (r == '-' || r == '+') && (idx == 0 || scalar[idx-1] == 'e' || scalar[idx-1] == 'E'):
+		default:
+			return TokenString
+		}
+	}
+	if hasDigit {
+		return TokenNumber
+	}
+	return TokenString
+}
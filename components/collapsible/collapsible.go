@@ -0,0 +1,261 @@
+package collapsible
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+const (
+	expandedMarker  = "▾ "
+	collapsedMarker = "▸ "
+
+	// The header is a single line above the body
+	headerHeight = 1
+)
+
+// Collapsible is a section whose header shows & hides its body when activated, by Enter or Space while the header is
+// focused or by clicking it
+// While collapsed, the body takes no part in the layout
+// While the header is focused, Down moves the focus into the body
+// Analogous to the <details> tag in HTML
+type Collapsible interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Container
+
+	GetTitle() string
+	SetTitle(title string) Collapsible
+
+	GetBody() components.Component
+	SetBody(body components.Component) Collapsible
+
+	// Setting the expanded state programmatically doesn't send a ToggledMsg
+	IsExpanded() bool
+	SetExpanded(isExpanded bool) Collapsible
+
+	// Whether the focus is on the header, as opposed to somewhere in the body
+	IsHeaderFocused() bool
+
+	IsDisabled() bool
+	SetDisabled(isDisabled bool) Collapsible
+
+	// Used for the header
+	GetStyles() control.Styles
+	SetStyles(styles control.Styles) Collapsible
+}
+
+// ToggledMsg is sent when the user expands or collapses a collapsible
+type ToggledMsg struct {
+	Collapsible Collapsible
+	IsExpanded  bool
+}
+
+type collapsibleImpl struct {
+	title string
+
+	body components.Component
+
+	isExpanded bool
+
+	// Nil for the default styles
+	styles *control.Styles
+
+	// The state of the header
+	state *control.State
+}
+
+func New(title string, body components.Component) Collapsible {
+	return &collapsibleImpl{
+		title:      sanitizeTitle(title),
+		body:       body,
+		isExpanded: false,
+		styles:     nil,
+		state:      control.NewState(),
+	}
+}
+
+func (c collapsibleImpl) GetTitle() string {
+	return c.title
+}
+
+func (c *collapsibleImpl) SetTitle(title string) Collapsible {
+	c.title = sanitizeTitle(title)
+	return c
+}
+
+func (c collapsibleImpl) GetBody() components.Component {
+	return c.body
+}
+
+func (c *collapsibleImpl) SetBody(body components.Component) Collapsible {
+	if components.IsChildFocused(c.body) {
+		components.SetChildFocus(c.body, false)
+		c.state.SetFocus(true)
+	}
+	c.body = body
+	return c
+}
+
+// The body, even while collapsed
+func (c collapsibleImpl) GetChildComponents() []components.Component {
+	return []components.Component{c.body}
+}
+
+func (c collapsibleImpl) IsExpanded() bool {
+	return c.isExpanded
+}
+
+func (c *collapsibleImpl) SetExpanded(isExpanded bool) Collapsible {
+	// The focus can't stay in a body that's no longer shown
+	if !isExpanded && components.IsChildFocused(c.body) {
+		components.SetChildFocus(c.body, false)
+		c.state.SetFocus(true)
+	}
+	c.isExpanded = isExpanded
+	return c
+}
+
+func (c collapsibleImpl) IsHeaderFocused() bool {
+	return c.state.IsFocused()
+}
+
+func (c collapsibleImpl) IsDisabled() bool {
+	return c.state.IsDisabled()
+}
+
+func (c *collapsibleImpl) SetDisabled(isDisabled bool) Collapsible {
+	c.state.SetDisabled(isDisabled)
+	return c
+}
+
+func (c collapsibleImpl) GetStyles() control.Styles {
+	if c.styles == nil {
+		return control.DefaultStyles()
+	}
+	return *c.styles
+}
+
+func (c *collapsibleImpl) SetStyles(styles control.Styles) Collapsible {
+	c.styles = &styles
+	return c
+}
+
+func (c *collapsibleImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return c.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (c *collapsibleImpl) GetContentHeightForGivenWidth(width int) int {
+	return c.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (c *collapsibleImpl) View(width int, height int) string {
+	return c.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (c *collapsibleImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	// Both markers are the same width, so toggling doesn't change the header's size
+	headerWidth := lipgloss.Width(c.getHeader())
+	minWidth, maxWidth, minHeight, maxHeight = headerWidth, headerWidth, headerHeight, headerHeight
+	if !c.isExpanded {
+		return
+	}
+
+	bodyMinWidth, bodyMaxWidth, bodyMinHeight, bodyMaxHeight := components.GetChildContentMinMax(c.body, ctx)
+	minWidth = utilities.GetMaxInt(minWidth, bodyMinWidth)
+	maxWidth = utilities.GetMaxInt(maxWidth, bodyMaxWidth)
+	minHeight += bodyMinHeight
+	maxHeight += bodyMaxHeight
+	return
+}
+
+func (c *collapsibleImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 {
+		return 0
+	}
+	if !c.isExpanded {
+		return headerHeight
+	}
+	return headerHeight + components.GetChildContentHeightForGivenWidth(c.body, ctx, width)
+}
+
+func (c *collapsibleImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	c.state.RecordViewSize(width, headerHeight)
+
+	lines := []string{control.FitToWidth(c.GetStyles().Get(c.state).Render(c.getHeader()), width)}
+	if c.isExpanded && height > headerHeight {
+		bodyView := components.ViewChild(c.body, ctx, width, height-headerHeight)
+		lines = append(lines, strings.Split(bodyView, "\n")...)
+	}
+	for idx, line := range lines {
+		lines[idx] = control.FitToWidth(line, width)
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+func (c *collapsibleImpl) Update(msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyDown && c.state.IsFocused() {
+		if c.isExpanded && components.SetChildFocus(c.body, true) {
+			c.state.SetFocus(false)
+		}
+		return nil
+	}
+
+	activation, cmd := c.state.Update(msg)
+	if activation.IsActivated {
+		c.SetExpanded(!c.isExpanded)
+		toggledMsg := ToggledMsg{Collapsible: c, IsExpanded: c.isExpanded}
+		return tea.Batch(cmd, func() tea.Msg {
+			return toggledMsg
+		})
+	}
+
+	if !c.isExpanded && !components.IsBroadcastMsg(msg) {
+		return cmd
+	}
+	return tea.Batch(cmd, components.UpdateChild(c.body, msg, 0, headerHeight))
+}
+
+func (c *collapsibleImpl) SetFocus(isFocused bool) {
+	c.state.SetFocus(isFocused)
+	if !isFocused {
+		components.SetChildFocus(c.body, false)
+	}
+}
+
+func (c collapsibleImpl) IsFocused() bool {
+	return c.state.IsFocused() || (c.isExpanded && components.IsChildFocused(c.body))
+}
+
+func (c *collapsibleImpl) GetOverlays() []components.Overlay {
+	if !c.isExpanded {
+		return nil
+	}
+	return components.GetChildOverlays(c.body, 0, headerHeight)
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (c collapsibleImpl) getHeader() string {
+	if c.isExpanded {
+		return expandedMarker + c.title
+	}
+	return collapsedMarker + c.title
+}
+
+// Headers are a single line
+func sanitizeTitle(title string) string {
+	return strings.Join(strings.Fields(title), " ")
+}
@@ -0,0 +1,97 @@
+package collapsible
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCollapsedBodyIsExcluded(t *testing.T) {
+	section := New("Details", text.New("hello world"))
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(9, 9, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(
+			9, 1,
+			20, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(11, 2, "▸ Details  \n           "),
+	)
+	test_assertions.CheckAll(t, assertions, section)
+}
+
+func TestExpanded(t *testing.T) {
+	section := New("Details", text.New("hello world")).SetExpanded(true)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(9, 11, 2, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			9, 3,
+			11, 2,
+		),
+		test_assertions.GetRenderedContentAssertion(11, 2, "▾ Details  \nhello world"),
+		test_assertions.GetRenderedContentAssertion(9, 3, "▾ Details\nhello    \nworld    "),
+	)
+	test_assertions.CheckAll(t, assertions, section)
+}
+
+func TestToggling(t *testing.T) {
+	submit := button.New("Go")
+	section := New("Details", submit)
+	render(section, 9)
+
+	// Keys do nothing until the header is focused
+	require.Nil(t, section.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+
+	section.SetFocus(true)
+	require.True(t, section.IsHeaderFocused())
+
+	// The body can't take the focus while it's hidden
+	section.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, section.IsHeaderFocused())
+
+	msgs := test_assertions.GetMsgs(section.Update(tea.KeyMsg{Type: tea.KeySpace}))
+	require.Contains(t, msgs, ToggledMsg{Collapsible: section, IsExpanded: true})
+	require.Equal(t, "▾ Details\n[ Go ]   ", render(section, 9))
+
+	section.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.False(t, section.IsHeaderFocused())
+	require.True(t, submit.IsFocused())
+	require.True(t, section.IsFocused())
+
+	// Collapsing moves the focus out of the body, back to the header
+	section.SetExpanded(false)
+	require.False(t, submit.IsFocused())
+	require.True(t, section.IsHeaderFocused())
+
+	// Clicking the header toggles it, but clicking below it doesn't
+	section.SetFocus(false)
+	render(section, 9)
+	section.Update(tea.MouseMsg{X: 3, Y: 0, Type: tea.MouseLeft})
+	msgs = test_assertions.GetMsgs(section.Update(tea.MouseMsg{X: 3, Y: 0, Type: tea.MouseRelease}))
+	require.Equal(t, []tea.Msg{ToggledMsg{Collapsible: section, IsExpanded: true}}, msgs)
+	render(section, 9)
+	section.Update(tea.MouseMsg{X: 3, Y: 1, Type: tea.MouseLeft})
+	section.Update(tea.MouseMsg{X: 3, Y: 1, Type: tea.MouseRelease})
+	require.True(t, section.IsExpanded())
+
+	// Disabled sections can't be toggled
+	section.SetDisabled(true)
+	section.Update(tea.MouseMsg{X: 3, Y: 0, Type: tea.MouseLeft})
+	require.Nil(t, section.Update(tea.MouseMsg{X: 3, Y: 0, Type: tea.MouseRelease}))
+	require.True(t, section.IsExpanded())
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func render(section Collapsible, width int) string {
+	section.GetContentMinMax()
+	return section.View(width, section.GetContentHeightForGivenWidth(width))
+}
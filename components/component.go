@@ -15,3 +15,11 @@ type Component interface {
 	// TODO maybe return Optional[string], so that we can indicate "there is no content at all"?
 	View(width int, height int) string
 }
+
+// WidthFiller is a component with no natural width that's meant to use all the width it's given (e.g. a progress bar),
+// so flexbox items let it grow into the free space by default, as if its max width were MaxAvailable
+type WidthFiller interface {
+	Component
+
+	FillsWidth() bool
+}
@@ -0,0 +1,135 @@
+package control
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"time"
+)
+
+// How long a control shows as pressed after being activated with the keyboard (terminals don't report key releases)
+const keyPressDuration = 150 * time.Millisecond
+
+// State tracks the focused, disabled & pressed states shared by all the clickable controls (buttons, checkboxes, etc.),
+// along with activating them
+type State struct {
+	isFocused bool
+
+	isDisabled bool
+
+	// Set while the mouse is held down on the control, and briefly after the control gets activated by a key
+	isPressed bool
+
+	// Counts the presses, so that the release from an earlier key press doesn't cut a later press short
+	numPresses int
+
+	// The size given to the control's last View, which mouse clicks get checked against
+	lastViewWidth  int
+	lastViewHeight int
+}
+
+// Activation describes what, if anything, activated the control
+type Activation struct {
+	IsActivated bool
+
+	// Set if the activation was a mouse click, along with where the click was (relative to the control)
+	IsMouse bool
+	MouseX  int
+	MouseY  int
+}
+
+// Sent to release a key press after keyPressDuration
+type releaseMsg struct {
+	state    *State
+	pressIdx int
+}
+
+func NewState() *State {
+	return &State{
+		isFocused:      false,
+		isDisabled:     false,
+		isPressed:      false,
+		numPresses:     0,
+		lastViewWidth:  0,
+		lastViewHeight: 0,
+	}
+}
+
+// Disabled controls can't be focused, so focusing one leaves it unfocused
+func (s *State) SetFocus(isFocused bool) {
+	s.isFocused = isFocused && !s.isDisabled
+}
+
+func (s State) IsFocused() bool {
+	return s.isFocused
+}
+
+// Disabling a control also unfocuses it
+func (s *State) SetDisabled(isDisabled bool) {
+	s.isDisabled = isDisabled
+	if isDisabled {
+		s.isFocused = false
+		s.isPressed = false
+	}
+}
+
+func (s State) IsDisabled() bool {
+	return s.isDisabled
+}
+
+func (s State) IsPressed() bool {
+	return s.isPressed
+}
+
+// RecordViewSize should be called from the control's View, so that mouse clicks can be checked against its bounds
+func (s *State) RecordViewSize(width int, height int) {
+	s.lastViewWidth = width
+	s.lastViewHeight = height
+}
+
+// Update handles the messages that activate the control, which are Enter or Space while it's focused, or a click on it
+// (the mouse being pressed & released inside it)
+func (s *State) Update(msg tea.Msg) (Activation, tea.Cmd) {
+	if release, ok := msg.(releaseMsg); ok {
+		if release.state == s && release.pressIdx == s.numPresses {
+			s.isPressed = false
+		}
+		return Activation{}, nil
+	}
+
+	if s.isDisabled {
+		return Activation{}, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !s.isFocused || (msg.Type != tea.KeyEnter && msg.Type != tea.KeySpace) {
+			return Activation{}, nil
+		}
+		s.isPressed = true
+		s.numPresses++
+		release := releaseMsg{state: s, pressIdx: s.numPresses}
+		cmd := tea.Tick(keyPressDuration, func(time.Time) tea.Msg {
+			return release
+		})
+		return Activation{IsActivated: true}, cmd
+	case tea.MouseMsg:
+		isInBounds := components.IsMouseMsgInBounds(msg, s.lastViewWidth, s.lastViewHeight)
+		switch msg.Type {
+		case tea.MouseLeft:
+			if isInBounds {
+				s.isPressed = true
+				s.numPresses++
+			}
+		case tea.MouseRelease:
+			if !s.isPressed {
+				break
+			}
+			s.isPressed = false
+			// Like most UIs, dragging off the control before releasing cancels the click
+			if isInBounds {
+				return Activation{IsActivated: true, IsMouse: true, MouseX: msg.X, MouseY: msg.Y}, nil
+			}
+		}
+	}
+	return Activation{}, nil
+}
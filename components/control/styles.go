@@ -0,0 +1,44 @@
+package control
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles holds the style for each visual state of a control
+type Styles struct {
+	Normal   lipgloss.Style
+	Focused  lipgloss.Style
+	Pressed  lipgloss.Style
+	Disabled lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Normal:   lipgloss.NewStyle(),
+		Focused:  lipgloss.NewStyle().Bold(true).Foreground(theme.Color(theme.Primary)),
+		Pressed:  lipgloss.NewStyle().Bold(true).Foreground(theme.Color(theme.Primary)).Background(theme.Color(theme.Selection)),
+		Disabled: lipgloss.NewStyle().Foreground(theme.Color(theme.Disabled)),
+	}
+}
+
+// Get gets the style for the state, where disabled takes precedence over pressed, which takes precedence over focused
+func (s Styles) Get(state *State) lipgloss.Style {
+	switch {
+	case state.IsDisabled():
+		return s.Disabled
+	case state.IsPressed():
+		return s.Pressed
+	case state.IsFocused():
+		return s.Focused
+	default:
+		return s.Normal
+	}
+}
+
+// FitToWidth truncates or pads the single line of (possibly styled) text to be exactly the width
+func FitToWidth(line string, width int) string {
+	truncated := lipgloss.NewStyle().MaxWidth(width).Render(line)
+	return lipgloss.PlaceHorizontal(width, lipgloss.Left, truncated)
+}
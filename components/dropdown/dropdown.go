@@ -0,0 +1,500 @@
+package dropdown
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/components/stylebox"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+const (
+	closedArrow = "▾"
+	openArrow   = "▴"
+
+	scrollUpIndicator   = "↑"
+	scrollDownIndicator = "↓"
+
+	noMatchesText = "No matches"
+
+	defaultMaxVisibleOptions = 5
+
+	// Indicates that no option is selected
+	NoSelection = -1
+)
+
+// Dropdown is a single line showing the selected option, which opens a list of the options to pick from when activated
+// The list gets drawn as an overlay on top of the other components (see components.Overlay), so opening it doesn't
+// change the layout
+// While open, typing filters the options, the arrow keys move through them, Enter picks one, and Escape closes the list
+// Analogous to the <select> tag in HTML
+type Dropdown interface {
+	components.InteractiveComponent
+	components.OverlayProvider
+
+	GetOptions() []string
+	// The selection is kept if it's still a valid index
+	SetOptions(options []string) Dropdown
+
+	// Setting the selection programmatically doesn't send a ChangedMsg
+	GetSelectedIndex() int
+	SetSelectedIndex(idx int) Dropdown
+	// Returns false if nothing is selected
+	GetSelectedOption() (string, bool)
+
+	// Shown when nothing is selected
+	GetPlaceholder() string
+	SetPlaceholder(placeholder string) Dropdown
+
+	// The list scrolls when there are more options than this
+	GetMaxVisibleOptions() int
+	SetMaxVisibleOptions(maxVisibleOptions int) Dropdown
+
+	IsOpen() bool
+	SetOpen(isOpen bool) Dropdown
+
+	IsDisabled() bool
+	SetDisabled(isDisabled bool) Dropdown
+
+	GetStyles() Styles
+	SetStyles(styles Styles) Dropdown
+}
+
+// ChangedMsg is sent when the user picks a different option
+type ChangedMsg struct {
+	Dropdown       Dropdown
+	SelectedIndex  int
+	SelectedOption string
+}
+
+type dropdownImpl struct {
+	options []string
+
+	selectedIdx int
+
+	placeholder string
+
+	maxVisibleOptions int
+
+	isOpen bool
+
+	// What's been typed since the list opened, which the options get filtered by
+	filter []rune
+
+	// The indexes of the options that match the filter
+	filteredIdxs []int
+
+	// The position within the filtered options that the arrow keys are on
+	highlightedPosition int
+
+	// The position within the filtered options of the first one shown in the list
+	scrollPosition int
+
+	// Nil for the default styles
+	styles *Styles
+
+	state *control.State
+
+	// The width given to the last View, which the list is at least as wide as
+	lastViewWidth int
+}
+
+func New(options ...string) Dropdown {
+	result := &dropdownImpl{
+		options:             nil,
+		selectedIdx:         NoSelection,
+		placeholder:         "",
+		maxVisibleOptions:   defaultMaxVisibleOptions,
+		isOpen:              false,
+		filter:              make([]rune, 0),
+		filteredIdxs:        make([]int, 0),
+		highlightedPosition: 0,
+		scrollPosition:      0,
+		styles:              nil,
+		state:               control.NewState(),
+		lastViewWidth:       0,
+	}
+	result.SetOptions(options)
+	return result
+}
+
+func (d dropdownImpl) GetOptions() []string {
+	return d.options
+}
+
+func (d *dropdownImpl) SetOptions(options []string) Dropdown {
+	d.options = make([]string, len(options))
+	for idx, option := range options {
+		d.options[idx] = sanitizeLabel(option)
+	}
+	if d.selectedIdx >= len(d.options) {
+		d.selectedIdx = NoSelection
+	}
+	d.updateFilteredIdxs()
+	return d
+}
+
+func (d dropdownImpl) GetSelectedIndex() int {
+	return d.selectedIdx
+}
+
+func (d *dropdownImpl) SetSelectedIndex(idx int) Dropdown {
+	if idx < 0 || idx >= len(d.options) {
+		d.selectedIdx = NoSelection
+		return d
+	}
+	d.selectedIdx = idx
+	return d
+}
+
+func (d dropdownImpl) GetSelectedOption() (string, bool) {
+	if d.selectedIdx == NoSelection {
+		return "", false
+	}
+	return d.options[d.selectedIdx], true
+}
+
+func (d dropdownImpl) GetPlaceholder() string {
+	return d.placeholder
+}
+
+func (d *dropdownImpl) SetPlaceholder(placeholder string) Dropdown {
+	d.placeholder = sanitizeLabel(placeholder)
+	return d
+}
+
+func (d dropdownImpl) GetMaxVisibleOptions() int {
+	return d.maxVisibleOptions
+}
+
+func (d *dropdownImpl) SetMaxVisibleOptions(maxVisibleOptions int) Dropdown {
+	d.maxVisibleOptions = utilities.GetMaxInt(1, maxVisibleOptions)
+	d.moveHighlight(0)
+	return d
+}
+
+func (d dropdownImpl) IsOpen() bool {
+	return d.isOpen
+}
+
+func (d *dropdownImpl) SetOpen(isOpen bool) Dropdown {
+	if isOpen {
+		d.open()
+	} else {
+		d.close()
+	}
+	return d
+}
+
+func (d dropdownImpl) IsDisabled() bool {
+	return d.state.IsDisabled()
+}
+
+func (d *dropdownImpl) SetDisabled(isDisabled bool) Dropdown {
+	d.state.SetDisabled(isDisabled)
+	if isDisabled {
+		d.close()
+	}
+	return d
+}
+
+func (d dropdownImpl) GetStyles() Styles {
+	if d.styles == nil {
+		return DefaultStyles()
+	}
+	return *d.styles
+}
+
+func (d *dropdownImpl) SetStyles(styles Styles) Dropdown {
+	d.styles = &styles
+	return d
+}
+
+func (d *dropdownImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	// Wide enough for any option, so picking one doesn't change the size
+	labelWidth := lipgloss.Width(d.placeholder)
+	for _, option := range d.options {
+		labelWidth = utilities.GetMaxInt(labelWidth, lipgloss.Width(option))
+	}
+	width := labelWidth + 1 + lipgloss.Width(closedArrow)
+	return width, width, 1, 1
+}
+
+func (d *dropdownImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (d *dropdownImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	d.state.RecordViewSize(width, 1)
+	d.lastViewWidth = width
+
+	var label string
+	switch {
+	case d.isOpen && len(d.filter) > 0:
+		label = d.GetStyles().Filter.Render(string(d.filter))
+	case d.selectedIdx != NoSelection:
+		label = d.options[d.selectedIdx]
+	default:
+		label = d.GetStyles().Placeholder.Render(d.placeholder)
+	}
+
+	arrow := closedArrow
+	if d.isOpen {
+		arrow = openArrow
+	}
+	labelWidth := utilities.GetMaxInt(0, width-1-lipgloss.Width(arrow))
+	content := control.FitToWidth(label, labelWidth) + " " + arrow
+	return control.FitToWidth(d.GetStyles().Field.Get(d.state).Render(content), width)
+}
+
+func (d *dropdownImpl) Update(msg tea.Msg) tea.Cmd {
+	if d.state.IsDisabled() {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !d.state.IsFocused() {
+			return nil
+		}
+		if d.isOpen {
+			return d.handleOpenKey(msg)
+		}
+		switch msg.Type {
+		case tea.KeyDown, tea.KeyUp:
+			d.open()
+			return nil
+		case tea.KeyRunes:
+			// Typing into a closed dropdown starts filtering straight away
+			if !msg.Alt {
+				d.open()
+				d.setFilter(msg.Runes)
+				return nil
+			}
+		}
+	case tea.MouseMsg:
+		// Clicking anywhere outside the dropdown closes it (clicks on the list get sent to the list's overlay instead)
+		if d.isOpen && msg.Type == tea.MouseLeft && !components.IsMouseMsgInBounds(msg, d.lastViewWidth, 1) {
+			d.close()
+			return nil
+		}
+	}
+
+	activation, cmd := d.state.Update(msg)
+	if activation.IsActivated {
+		d.SetOpen(!d.isOpen)
+	}
+	return cmd
+}
+
+func (d *dropdownImpl) SetFocus(isFocused bool) {
+	d.state.SetFocus(isFocused)
+	if !d.state.IsFocused() {
+		d.close()
+	}
+}
+
+func (d dropdownImpl) IsFocused() bool {
+	return d.state.IsFocused()
+}
+
+func (d *dropdownImpl) GetOverlays() []components.Overlay {
+	if !d.isOpen || d.lastViewWidth == 0 {
+		return nil
+	}
+	return []components.Overlay{
+		{
+			AnchorX:      0,
+			AnchorY:      0,
+			AnchorWidth:  d.lastViewWidth,
+			AnchorHeight: 1,
+			Content:      d.renderList(),
+			HandleMouse:  d.handleListMouse,
+		},
+	}
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (d *dropdownImpl) handleOpenKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return d.selectHighlighted()
+	case tea.KeyEsc:
+		d.close()
+	case tea.KeyUp, tea.KeyCtrlP:
+		d.moveHighlight(-1)
+	case tea.KeyDown, tea.KeyCtrlN:
+		d.moveHighlight(1)
+	case tea.KeyPgUp:
+		d.moveHighlight(-d.maxVisibleOptions)
+	case tea.KeyPgDown:
+		d.moveHighlight(d.maxVisibleOptions)
+	case tea.KeyHome:
+		d.moveHighlight(-len(d.filteredIdxs))
+	case tea.KeyEnd:
+		d.moveHighlight(len(d.filteredIdxs))
+	case tea.KeyBackspace:
+		if len(d.filter) > 0 {
+			d.setFilter(d.filter[:len(d.filter)-1])
+		}
+	case tea.KeySpace:
+		d.setFilter(append(d.filter, ' '))
+	case tea.KeyRunes:
+		if !msg.Alt {
+			d.setFilter(append(d.filter, msg.Runes...))
+		}
+	}
+	return nil
+}
+
+func (d *dropdownImpl) handleListMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		d.moveHighlight(-1)
+	case tea.MouseWheelDown:
+		d.moveHighlight(1)
+	case tea.MouseLeft, tea.MouseMotion:
+		_, yOffset := stylebox.GetContentOffset(d.GetStyles().List)
+		position := d.scrollPosition + msg.Y - yOffset
+		if msg.Y < yOffset || position >= utilities.GetMinInt(len(d.filteredIdxs), d.scrollPosition+d.maxVisibleOptions) {
+			return nil
+		}
+		d.highlightedPosition = position
+		if msg.Type == tea.MouseLeft {
+			return d.selectHighlighted()
+		}
+	}
+	return nil
+}
+
+func (d *dropdownImpl) open() {
+	d.isOpen = true
+	d.setFilter(nil)
+
+	// Start from the selected option, so it's easy to move to its neighbors
+	for position, idx := range d.filteredIdxs {
+		if idx == d.selectedIdx {
+			d.highlightedPosition = position
+		}
+	}
+	d.moveHighlight(0)
+}
+
+func (d *dropdownImpl) close() {
+	d.isOpen = false
+	d.setFilter(nil)
+}
+
+// Closes the list, sending a ChangedMsg if the highlighted option wasn't already the selected one
+func (d *dropdownImpl) selectHighlighted() tea.Cmd {
+	if len(d.filteredIdxs) == 0 {
+		return nil
+	}
+	idx := d.filteredIdxs[d.highlightedPosition]
+	d.close()
+	if idx == d.selectedIdx {
+		return nil
+	}
+
+	d.selectedIdx = idx
+	changedMsg := ChangedMsg{
+		Dropdown:       d,
+		SelectedIndex:  idx,
+		SelectedOption: d.options[idx],
+	}
+	return func() tea.Msg {
+		return changedMsg
+	}
+}
+
+func (d *dropdownImpl) setFilter(filter []rune) {
+	d.filter = append([]rune(nil), filter...)
+	d.updateFilteredIdxs()
+
+	// The best match is the first one, so that's where the highlight goes
+	d.highlightedPosition = 0
+	d.scrollPosition = 0
+}
+
+// Options match if they contain the filter, ignoring case
+func (d *dropdownImpl) updateFilteredIdxs() {
+	lowercaseFilter := strings.ToLower(string(d.filter))
+	d.filteredIdxs = d.filteredIdxs[:0]
+	for idx, option := range d.options {
+		if strings.Contains(strings.ToLower(option), lowercaseFilter) {
+			d.filteredIdxs = append(d.filteredIdxs, idx)
+		}
+	}
+	d.moveHighlight(0)
+}
+
+// Moves the highlight by the number of options, scrolling the list just enough to keep it visible
+func (d *dropdownImpl) moveHighlight(delta int) {
+	d.highlightedPosition = utilities.Clamp(d.highlightedPosition+delta, 0, utilities.GetMaxInt(0, len(d.filteredIdxs)-1))
+	if d.highlightedPosition < d.scrollPosition {
+		d.scrollPosition = d.highlightedPosition
+	}
+	if d.highlightedPosition >= d.scrollPosition+d.maxVisibleOptions {
+		d.scrollPosition = d.highlightedPosition - d.maxVisibleOptions + 1
+	}
+	d.scrollPosition = utilities.Clamp(d.scrollPosition, 0, utilities.GetMaxInt(0, len(d.filteredIdxs)-d.maxVisibleOptions))
+}
+
+func (d dropdownImpl) renderList() string {
+	listFrameWidth := lipgloss.Width(d.GetStyles().List.Render(""))
+	hasScrollIndicators := len(d.filteredIdxs) > d.maxVisibleOptions
+
+	indicatorWidth := 0
+	if hasScrollIndicators {
+		indicatorWidth = 1 + lipgloss.Width(scrollUpIndicator)
+	}
+	optionWidth := 0
+	for _, option := range d.options {
+		optionWidth = utilities.GetMaxInt(optionWidth, lipgloss.Width(option))
+	}
+	// The list is at least as wide as the dropdown itself
+	optionWidth = utilities.GetMaxInt(optionWidth, d.lastViewWidth-listFrameWidth-indicatorWidth)
+
+	if len(d.filteredIdxs) == 0 {
+		optionWidth = utilities.GetMaxInt(optionWidth, lipgloss.Width(noMatchesText))
+		return d.GetStyles().List.Render(d.GetStyles().NoMatches.Render(control.FitToWidth(noMatchesText, optionWidth)))
+	}
+
+	lastVisiblePosition := utilities.GetMinInt(len(d.filteredIdxs), d.scrollPosition+d.maxVisibleOptions)
+	lines := make([]string, 0, lastVisiblePosition-d.scrollPosition)
+	for position := d.scrollPosition; position < lastVisiblePosition; position++ {
+		style := d.GetStyles().Option
+		if position == d.highlightedPosition {
+			style = d.GetStyles().HighlightedOption
+		}
+		line := style.Render(control.FitToWidth(d.options[d.filteredIdxs[position]], optionWidth))
+
+		if hasScrollIndicators {
+			indicator := strings.Repeat(" ", lipgloss.Width(scrollUpIndicator))
+			if position == d.scrollPosition && d.scrollPosition > 0 {
+				indicator = scrollUpIndicator
+			} else if position == lastVisiblePosition-1 && lastVisiblePosition < len(d.filteredIdxs) {
+				indicator = scrollDownIndicator
+			}
+			line += " " + d.GetStyles().ScrollIndicator.Render(indicator)
+		}
+		lines = append(lines, line)
+	}
+	return d.GetStyles().List.Render(strings.Join(lines, "\n"))
+}
+
+// Options are a single line
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
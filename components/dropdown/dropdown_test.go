@@ -0,0 +1,170 @@
+package dropdown
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestClosedSize(t *testing.T) {
+	dropdown := New("Red", "Green", "Blue").SetPlaceholder("Color")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 7, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(
+			3, 1,
+			7, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(7, 1, "Color ▾"),
+		test_assertions.GetRenderedContentAssertion(10, 1, "Color    ▾"),
+	)
+	test_assertions.CheckAll(t, assertions, dropdown)
+
+	dropdown.SetSelectedIndex(1)
+	test_assertions.CheckAll(t, test_assertions.GetRenderedContentAssertion(7, 1, "Green ▾"), dropdown)
+}
+
+func TestKeyboardNavigationScrolls(t *testing.T) {
+	dropdown := New("One", "Two", "Three", "Four", "Five").SetMaxVisibleOptions(3)
+	dropdown.View(6, 1)
+
+	// Keys do nothing until the dropdown is focused
+	require.Nil(t, dropdown.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.False(t, dropdown.IsOpen())
+	require.Nil(t, dropdown.GetOverlays())
+
+	dropdown.SetFocus(true)
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, dropdown.IsOpen())
+	require.Equal(
+		t,
+		"┌───────┐\n│One    │\n│Two    │\n│Three ↓│\n└───────┘",
+		getOverlayContent(t, dropdown),
+	)
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, dropdown.Update(tea.KeyMsg{Type: tea.KeyDown}))
+	}
+	require.Equal(
+		t,
+		"┌───────┐\n│Two   ↑│\n│Three  │\n│Four  ↓│\n└───────┘",
+		getOverlayContent(t, dropdown),
+	)
+
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	require.Equal(
+		t,
+		"┌───────┐\n│Three ↑│\n│Four   │\n│Five   │\n└───────┘",
+		getOverlayContent(t, dropdown),
+	)
+
+	msgs := test_assertions.GetMsgs(dropdown.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.Equal(t, []tea.Msg{ChangedMsg{Dropdown: dropdown, SelectedIndex: 4, SelectedOption: "Five"}}, msgs)
+	require.False(t, dropdown.IsOpen())
+	require.Nil(t, dropdown.GetOverlays())
+
+	// Reopening starts from the selection, and Escape closes without changing it
+	dropdown.Update(tea.KeyMsg{Type: tea.KeySpace})
+	require.True(t, dropdown.IsOpen())
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyUp})
+	require.Nil(t, dropdown.Update(tea.KeyMsg{Type: tea.KeyEsc}))
+	require.False(t, dropdown.IsOpen())
+	require.Equal(t, 4, dropdown.GetSelectedIndex())
+}
+
+func TestTypeAheadFiltering(t *testing.T) {
+	dropdown := New("Apple", "Banana", "Cherry", "Grape")
+	dropdown.SetFocus(true)
+	dropdown.View(8, 1)
+
+	// Typing into a closed dropdown opens it
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("AP")})
+	require.True(t, dropdown.IsOpen())
+	require.Equal(t, "AP     ▴", dropdown.View(8, 1))
+	require.Equal(t, "┌──────┐\n│Apple │\n│Grape │\n└──────┘", getOverlayContent(t, dropdown))
+
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	require.Equal(t, "┌──────────┐\n│No matches│\n└──────────┘", getOverlayContent(t, dropdown))
+	require.Nil(t, dropdown.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.True(t, dropdown.IsOpen())
+
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	dropdown.Update(tea.KeyMsg{Type: tea.KeyDown})
+	msgs := test_assertions.GetMsgs(dropdown.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.Equal(t, []tea.Msg{ChangedMsg{Dropdown: dropdown, SelectedIndex: 3, SelectedOption: "Grape"}}, msgs)
+
+	// The filter gets cleared on close
+	require.Equal(t, "Grape  ▾", dropdown.View(8, 1))
+}
+
+func TestMouse(t *testing.T) {
+	dropdown := New("Small", "Medium", "Large")
+	dropdown.View(8, 1)
+
+	dropdown.Update(tea.MouseMsg{X: 2, Y: 0, Type: tea.MouseLeft})
+	dropdown.Update(tea.MouseMsg{X: 2, Y: 0, Type: tea.MouseRelease})
+	require.True(t, dropdown.IsOpen())
+
+	// Clicks on the list land inside its border
+	overlays := dropdown.GetOverlays()
+	require.Len(t, overlays, 1)
+	require.Nil(t, overlays[0].HandleMouse(tea.MouseMsg{X: 3, Y: 0, Type: tea.MouseLeft}))
+	require.True(t, dropdown.IsOpen())
+	msgs := test_assertions.GetMsgs(overlays[0].HandleMouse(tea.MouseMsg{X: 3, Y: 2, Type: tea.MouseLeft}))
+	require.Equal(t, []tea.Msg{ChangedMsg{Dropdown: dropdown, SelectedIndex: 1, SelectedOption: "Medium"}}, msgs)
+	require.False(t, dropdown.IsOpen())
+
+	// Clicking elsewhere closes the list
+	dropdown.SetOpen(true)
+	require.Nil(t, dropdown.Update(tea.MouseMsg{X: 20, Y: 5, Type: tea.MouseLeft}))
+	require.False(t, dropdown.IsOpen())
+}
+
+func TestPlaceOverlays(t *testing.T) {
+	overlay := components.Overlay{
+		AnchorX:      6,
+		AnchorY:      1,
+		AnchorWidth:  4,
+		AnchorHeight: 1,
+		Content:      "abc\ndef",
+	}
+
+	// Below the anchor when it fits, shifted left to stay on the screen
+	placed := components.PlaceOverlays([]components.Overlay{overlay}, 8, 5)
+	require.Len(t, placed, 1)
+	require.Equal(t, 5, placed[0].X)
+	require.Equal(t, 2, placed[0].Y)
+	require.Equal(t, "abc\ndef", placed[0].PlacedContent)
+
+	// Above the anchor when there's more room there
+	overlay.AnchorY = 3
+	placed = components.PlaceOverlays([]components.Overlay{overlay}, 8, 5)
+	require.Equal(t, 1, placed[0].Y)
+
+	view := strings.Join([]string{"........", "........", "........", "........", "........"}, "\n")
+	require.Equal(
+		t,
+		"........\n.....abc\n.....def\n........\n........",
+		components.DrawOverlays(view, placed),
+	)
+
+	found, isFound := components.FindOverlayAt(placed, 6, 2)
+	require.True(t, isFound)
+	require.Equal(t, placed[0].Content, found.Content)
+	_, isFound = components.FindOverlayAt(placed, 4, 2)
+	require.False(t, isFound)
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func getOverlayContent(t *testing.T, dropdown Dropdown) string {
+	overlays := dropdown.GetOverlays()
+	require.Len(t, overlays, 1)
+	return overlays[0].Content
+}
@@ -0,0 +1,43 @@
+package dropdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles controls how each part of the dropdown gets styled
+type Styles struct {
+	// The closed dropdown that sits in the layout, for each of its states
+	Field control.Styles
+
+	// Used in the field for the placeholder, and for the filter while it's being typed
+	Placeholder lipgloss.Style
+	Filter      lipgloss.Style
+
+	// Put around the list of options, so borders & padding are allowed
+	List lipgloss.Style
+
+	Option            lipgloss.Style
+	HighlightedOption lipgloss.Style
+
+	// Used for the scroll arrows shown when there are more options than fit in the list
+	ScrollIndicator lipgloss.Style
+
+	// Shown in the list when the filter doesn't match any options
+	NoMatches lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Field:             control.DefaultStyles(),
+		Placeholder:       lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)),
+		Filter:            lipgloss.NewStyle().Underline(true),
+		List:              lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(theme.Color(theme.Border)),
+		Option:            lipgloss.NewStyle(),
+		HighlightedOption: lipgloss.NewStyle().Reverse(true),
+		ScrollIndicator:   lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)),
+		NoMatches:         lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)).Italic(true),
+	}
+}
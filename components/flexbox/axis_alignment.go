@@ -1,5 +1,7 @@
 package flexbox
 
+import "math"
+
 // The percentage from the start that alignment should be done
 // See lipgloss.Position for more
 type AxisAlignment float64
@@ -17,3 +19,31 @@ const (
 	// Corresponds to "flex-justify: flex-end"
 	AlignEnd = 1.0
 )
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Gets the space that lipgloss.JoinHorizontal/JoinVertical put before a block that's 'gap' smaller than the joined result
+func getJoinedLeadingSpace(gap int, alignment AxisAlignment) int {
+	if gap <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(gap) * float64(alignment)))
+}
+
+// Gets the space that lipgloss.PlaceHorizontal/PlaceVertical put before a block that's 'gap' smaller than the space
+// it's placed in
+// NOTE: this doesn't always agree with getJoinedLeadingSpace, because lipgloss rounds the other way when placing
+func getPlacedLeadingSpace(gap int, alignment AxisAlignment) int {
+	switch {
+	case gap <= 0:
+		return 0
+	case alignment <= AlignStart:
+		return 0
+	case alignment >= AlignEnd:
+		return gap
+	default:
+		return gap - int(math.Round(float64(gap)*float64(alignment)))
+	}
+}
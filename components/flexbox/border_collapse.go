@@ -0,0 +1,185 @@
+package flexbox
+
+import (
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/muesli/reflow/ansi"
+	"strings"
+)
+
+// The directions a box-drawing glyph's lines run out of its center in
+const (
+	armUp = 1 << iota
+	armRight
+	armDown
+	armLeft
+)
+
+// Glyphs only get joined with glyphs of the same weight, as there aren't junctions for every mix of weights
+type glyphWeight int
+
+const (
+	lightWeight glyphWeight = iota
+	heavyWeight
+	doubleWeight
+)
+
+type borderGlyph struct {
+	weight glyphWeight
+	arms   int
+}
+
+type glyphEntry struct {
+	glyph rune
+	borderGlyph
+}
+
+// Rounded corners come last, so that joining them produces the square junctions (there are no rounded ones)
+var glyphEntries = []glyphEntry{
+	{'─', borderGlyph{lightWeight, armLeft | armRight}},
+	{'│', borderGlyph{lightWeight, armUp | armDown}},
+	{'┌', borderGlyph{lightWeight, armRight | armDown}},
+	{'┐', borderGlyph{lightWeight, armDown | armLeft}},
+	{'└', borderGlyph{lightWeight, armUp | armRight}},
+	{'┘', borderGlyph{lightWeight, armUp | armLeft}},
+	{'├', borderGlyph{lightWeight, armUp | armRight | armDown}},
+	{'┤', borderGlyph{lightWeight, armUp | armDown | armLeft}},
+	{'┬', borderGlyph{lightWeight, armRight | armDown | armLeft}},
+	{'┴', borderGlyph{lightWeight, armUp | armRight | armLeft}},
+	{'┼', borderGlyph{lightWeight, armUp | armRight | armDown | armLeft}},
+	{'━', borderGlyph{heavyWeight, armLeft | armRight}},
+	{'┃', borderGlyph{heavyWeight, armUp | armDown}},
+	{'┏', borderGlyph{heavyWeight, armRight | armDown}},
+	{'┓', borderGlyph{heavyWeight, armDown | armLeft}},
+	{'┗', borderGlyph{heavyWeight, armUp | armRight}},
+	{'┛', borderGlyph{heavyWeight, armUp | armLeft}},
+	{'┣', borderGlyph{heavyWeight, armUp | armRight | armDown}},
+	{'┫', borderGlyph{heavyWeight, armUp | armDown | armLeft}},
+	{'┳', borderGlyph{heavyWeight, armRight | armDown | armLeft}},
+	{'┻', borderGlyph{heavyWeight, armUp | armRight | armLeft}},
+	{'╋', borderGlyph{heavyWeight, armUp | armRight | armDown | armLeft}},
+	{'═', borderGlyph{doubleWeight, armLeft | armRight}},
+	{'║', borderGlyph{doubleWeight, armUp | armDown}},
+	{'╔', borderGlyph{doubleWeight, armRight | armDown}},
+	{'╗', borderGlyph{doubleWeight, armDown | armLeft}},
+	{'╚', borderGlyph{doubleWeight, armUp | armRight}},
+	{'╝', borderGlyph{doubleWeight, armUp | armLeft}},
+	{'╠', borderGlyph{doubleWeight, armUp | armRight | armDown}},
+	{'╣', borderGlyph{doubleWeight, armUp | armDown | armLeft}},
+	{'╦', borderGlyph{doubleWeight, armRight | armDown | armLeft}},
+	{'╩', borderGlyph{doubleWeight, armUp | armRight | armLeft}},
+	{'╬', borderGlyph{doubleWeight, armUp | armRight | armDown | armLeft}},
+	{'╭', borderGlyph{lightWeight, armRight | armDown}},
+	{'╮', borderGlyph{lightWeight, armDown | armLeft}},
+	{'╰', borderGlyph{lightWeight, armUp | armRight}},
+	{'╯', borderGlyph{lightWeight, armUp | armLeft}},
+}
+
+var borderGlyphsByRune, runesByBorderGlyph = func() (map[rune]borderGlyph, map[borderGlyph]rune) {
+	byRune := make(map[rune]borderGlyph, len(glyphEntries))
+	byGlyph := make(map[borderGlyph]rune, len(glyphEntries))
+	for _, entry := range glyphEntries {
+		byRune[entry.glyph] = entry.borderGlyph
+		if _, found := byGlyph[entry.borderGlyph]; !found {
+			byGlyph[entry.borderGlyph] = entry.glyph
+		}
+	}
+	return byRune, byGlyph
+}()
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Gets the rune to draw where a cell of a later child lands on a cell that's already drawn, joining border glyphs into
+// the junction with the lines of both (e.g. ┐ and ┌ make ┬)
+// Blank cells of the later child leave what's underneath in place, and anything else is drawn over it
+func mergeBorderGlyphs(underneath rune, over rune) rune {
+	if over == ' ' {
+		return underneath
+	}
+	underneathGlyph, isUnderneathGlyph := borderGlyphsByRune[underneath]
+	overGlyph, isOverGlyph := borderGlyphsByRune[over]
+	if !isUnderneathGlyph || !isOverGlyph || underneathGlyph.weight != overGlyph.weight {
+		return over
+	}
+	return runesByBorderGlyph[borderGlyph{
+		weight: overGlyph.weight,
+		arms:   underneathGlyph.arms | overGlyph.arms,
+	}]
+}
+
+// Maps each printable rune of the (possibly styled) line, leaving the escape sequences alone
+func mapPrintableRunes(line string, mapper func(column int, r rune) rune) string {
+	var result strings.Builder
+	isInEscape := false
+	column := 0
+	for _, r := range line {
+		switch {
+		case r == ansi.Marker:
+			isInEscape = true
+			result.WriteRune(r)
+		case isInEscape:
+			result.WriteRune(r)
+			isInEscape = !ansi.IsTerminator(r)
+		default:
+			result.WriteRune(mapper(column, r))
+			column += ansi.PrintableRuneWidth(string(r))
+		}
+	}
+	return result.String()
+}
+
+// Gets the printable rune at the column of the (possibly styled) line, or a space if the line doesn't reach it
+func getRuneAtColumn(line string, column int) rune {
+	result := ' '
+	mapPrintableRunes(line, func(runeColumn int, r rune) rune {
+		if runeColumn == column {
+			result = r
+		}
+		return r
+	})
+	return result
+}
+
+// Draws the fragments onto a blank canvas at their offsets, with each fragment's leading edge along the main axis
+// (which lands on the trailing edge of the fragment before it) merged with what's underneath
+func drawCollapsedFragments(
+	fragments []string,
+	xOffsets []int,
+	yOffsets []int,
+	width int,
+	height int,
+	isMainAxisHorizontal bool,
+) string {
+	canvasLines := make([]string, height)
+	for idx := range canvasLines {
+		canvasLines[idx] = strings.Repeat(" ", width)
+	}
+	canvas := strings.Join(canvasLines, "\n")
+
+	for idx, fragment := range fragments {
+		if fragment == "" {
+			continue
+		}
+		canvasLines = strings.Split(canvas, "\n")
+		fragmentLines := strings.Split(fragment, "\n")
+		for lineIdx, line := range fragmentLines {
+			canvasY := yOffsets[idx] + lineIdx
+			if canvasY < 0 || canvasY >= len(canvasLines) {
+				continue
+			}
+			if !isMainAxisHorizontal && lineIdx > 0 {
+				break
+			}
+			canvasLine := canvasLines[canvasY]
+			fragmentLines[lineIdx] = mapPrintableRunes(line, func(column int, r rune) rune {
+				if isMainAxisHorizontal && column > 0 {
+					return r
+				}
+				return mergeBorderGlyphs(getRuneAtColumn(canvasLine, xOffsets[idx]+column), r)
+			})
+		}
+		canvas = components.DrawOver(canvas, strings.Join(fragmentLines, "\n"), xOffsets[idx], yOffsets[idx])
+	}
+	return canvas
+}
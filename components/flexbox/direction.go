@@ -2,7 +2,9 @@ package flexbox
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
 	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/utilities"
 )
 
 // The direction that the flexbox ought to be layed out in
@@ -16,19 +18,31 @@ type Direction interface {
 
 	*/
 
-	getContentSizes(items []flexbox_item.FlexboxItem) (minWidth, maxWidth, minHeight, maxHeight int)
+	getContentSizes(ctx components.LayoutContext, items []flexbox_item.FlexboxItem) (minWidth, maxWidth, minHeight, maxHeight int)
 
 	getActualWidths(desiredWidths []int, shouldGrow []bool, widthAvailable int) axisSizeCalculationResults
 
 	getActualHeights(desiredHeights []int, shouldGrow []bool, heightAvailable int) axisSizeCalculationResults
 
+	// Combines the desired heights of the children into the desired height of the flexbox, the same way the min & max
+	// heights get combined: a column stacks its children so it wants the sum of their heights, while a row wants the
+	// height of its tallest child
+	getTotalDesiredHeight(desiredHeights []int) int
+
 	renderContentFragments(contentFragments []string, width int, height int, horizontalAlignment AxisAlignment, verticalAlignment AxisAlignment) string
+
+	// Gets where the top-left corner of each child ends up when the children are rendered with renderContentFragments
+	getChildOffsets(childWidths []int, childHeights []int, width int, height int, horizontalAlignment AxisAlignment, verticalAlignment AxisAlignment) (xOffsets []int, yOffsets []int)
+
+	// Whether the children get laid out left to right (as opposed to top to bottom)
+	isMainAxisHorizontal() bool
 }
 
 // Row lays out the flexbox items in a row, left to right
 // The flex direction will be horizontal
 // Corresponds to "flex-direction: row" in CSS
 var Row = &directionImpl{
+	mainAxisIsHorizontal:   true,
 	actualWidthCalculator:  calculateActualMainAxisSizes,
 	actualHeightCalculator: calculateActualCrossAxisSizes,
 	minMaxWidthCombiner:    mainAxisDimensionMinMaxCombiner,
@@ -38,12 +52,16 @@ var Row = &directionImpl{
 		horizontallyPlaced := lipgloss.PlaceHorizontal(width, lipgloss.Position(horizontalAlign), joined)
 		return lipgloss.PlaceVertical(height, lipgloss.Position(verticalAlign), horizontallyPlaced)
 	},
+	childOffsetsCalculator: func(childWidths []int, childHeights []int, width int, height int, horizontalAlign AxisAlignment, verticalAlign AxisAlignment) ([]int, []int) {
+		return calculateChildOffsets(childWidths, childHeights, width, height, horizontalAlign, verticalAlign)
+	},
 }
 
 // Column lays out the flexbox items in a column, top to bottom
 // The flex direction will be vertical
 // Corresponds to "flex-direction: column" in CSS
 var Column = &directionImpl{
+	mainAxisIsHorizontal:   false,
 	actualWidthCalculator:  calculateActualCrossAxisSizes,
 	actualHeightCalculator: calculateActualMainAxisSizes,
 	minMaxWidthCombiner:    crossAxisDimensionMinMaxCombiner,
@@ -53,6 +71,10 @@ var Column = &directionImpl{
 		horizontallyPlaced := lipgloss.PlaceHorizontal(width, lipgloss.Position(horizontalAlign), joined)
 		return lipgloss.PlaceVertical(height, lipgloss.Position(verticalAlign), horizontallyPlaced)
 	},
+	childOffsetsCalculator: func(childWidths []int, childHeights []int, width int, height int, horizontalAlign AxisAlignment, verticalAlign AxisAlignment) ([]int, []int) {
+		mainAxisOffsets, crossAxisOffsets := calculateChildOffsets(childHeights, childWidths, height, width, verticalAlign, horizontalAlign)
+		return crossAxisOffsets, mainAxisOffsets
+	},
 }
 
 // ====================================================================================================
@@ -61,20 +83,22 @@ var Column = &directionImpl{
 //
 // ====================================================================================================
 type directionImpl struct {
+	mainAxisIsHorizontal    bool
 	actualWidthCalculator   axisSizeCalculator
 	actualHeightCalculator  axisSizeCalculator
 	minMaxWidthCombiner     axisDimensionMinMaxCombiner
 	minMaxHeightCombiner    axisDimensionMinMaxCombiner
 	contentFragmentRenderer func(contentFragments []string, width int, height int, horizontalAlign AxisAlignment, verticalAlign AxisAlignment) string
+	childOffsetsCalculator  func(childWidths []int, childHeights []int, width int, height int, horizontalAlign AxisAlignment, verticalAlign AxisAlignment) (xOffsets []int, yOffsets []int)
 }
 
-func (a directionImpl) getContentSizes(items []flexbox_item.FlexboxItem) (int, int, int, int) {
+func (a directionImpl) getContentSizes(ctx components.LayoutContext, items []flexbox_item.FlexboxItem) (int, int, int, int) {
 	childMinWidths := make([]int, len(items))
 	childMaxWidths := make([]int, len(items))
 	childMinHeights := make([]int, len(items))
 	childMaxHeights := make([]int, len(items))
 	for idx, item := range items {
-		childMinWidths[idx], childMaxWidths[idx], childMinHeights[idx], childMaxHeights[idx] = item.GetContentMinMax()
+		childMinWidths[idx], childMaxWidths[idx], childMinHeights[idx], childMaxHeights[idx] = item.GetContentMinMaxInContext(ctx)
 	}
 
 	minWidth, maxWidth := a.minMaxWidthCombiner(childMinWidths, childMaxWidths)
@@ -99,6 +123,47 @@ func (r directionImpl) getActualHeights(desiredHeights []int, shouldGrow []bool,
 	)
 }
 
+func (r directionImpl) getTotalDesiredHeight(desiredHeights []int) int {
+	result, _ := r.minMaxHeightCombiner(desiredHeights, desiredHeights)
+	return result
+}
+
 func (r directionImpl) renderContentFragments(contentFragments []string, width int, height int, horizontalAlign AxisAlignment, verticalAlign AxisAlignment) string {
 	return r.contentFragmentRenderer(contentFragments, width, height, horizontalAlign, verticalAlign)
 }
+
+func (r directionImpl) getChildOffsets(childWidths []int, childHeights []int, width int, height int, horizontalAlign AxisAlignment, verticalAlign AxisAlignment) ([]int, []int) {
+	return r.childOffsetsCalculator(childWidths, childHeights, width, height, horizontalAlign, verticalAlign)
+}
+
+func (r directionImpl) isMainAxisHorizontal() bool {
+	return r.mainAxisIsHorizontal
+}
+
+// Calculates the child offsets along both axes, where the children are laid out one after another along the main axis
+// This mirrors what the lipgloss joining & placing in the content fragment renderers do
+func calculateChildOffsets(
+	mainAxisSizes []int,
+	crossAxisSizes []int,
+	mainAxisSpace int,
+	crossAxisSpace int,
+	mainAxisAlign AxisAlignment,
+	crossAxisAlign AxisAlignment,
+) (mainAxisOffsets []int, crossAxisOffsets []int) {
+	totalMainAxisSize, maxCrossAxisSize := 0, 0
+	for idx := range mainAxisSizes {
+		totalMainAxisSize += mainAxisSizes[idx]
+		maxCrossAxisSize = utilities.GetMaxInt(maxCrossAxisSize, crossAxisSizes[idx])
+	}
+
+	mainAxisOffsets = make([]int, len(mainAxisSizes))
+	crossAxisOffsets = make([]int, len(mainAxisSizes))
+	mainAxisPosition := getPlacedLeadingSpace(mainAxisSpace-totalMainAxisSize, mainAxisAlign)
+	crossAxisPosition := getPlacedLeadingSpace(crossAxisSpace-maxCrossAxisSize, crossAxisAlign)
+	for idx := range mainAxisSizes {
+		mainAxisOffsets[idx] = mainAxisPosition
+		crossAxisOffsets[idx] = crossAxisPosition + getJoinedLeadingSpace(maxCrossAxisSize-crossAxisSizes[idx], crossAxisAlign)
+		mainAxisPosition += mainAxisSizes[idx]
+	}
+	return mainAxisOffsets, crossAxisOffsets
+}
@@ -1,6 +1,7 @@
 package flexbox
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mieubrisse/box-layout-test/components"
 	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
 	"github.com/mieubrisse/box-layout-test/utilities"
@@ -13,17 +14,33 @@ import (
 type Flexbox struct {
 	children []flexbox_item.FlexboxItem
 
+	// For finding the flexbox in the tree (see components.FindByID)
+	id      string
+	classes []string
+
 	direction Direction
 
 	horizontalAlignment AxisAlignment
 	verticalAlignment   AxisAlignment
 
+	// When set, neighboring children overlap by a cell along the main axis so that their borders share one line, with
+	// the border glyphs where they meet joined into junctions (e.g. ┬ ┼ ┤)
+	isBorderCollapsed bool
+
 	// -------------------- Calculation Caching -----------------------
+	// The children taking part in the layout (i.e. not collapsed), as of GetContentHeightForGivenWidth, which the other
+	// caches line up with
+	laidOutChildrenCache []flexbox_item.FlexboxItem
+
 	// The actual widths each child will get (cached between GetContentHeightForGivenWidth and View)
 	actualChildWidthsCache axisSizeCalculationResults
 
 	// The desired height each child wants given its width (cached between GetContentHeightForGivenWidth and View)
 	desiredChildHeightsGivenWidthCache []int
+
+	// Where each child's top-left corner was during the last View, used for routing mouse messages
+	childXOffsetsCache []int
+	childYOffsetsCache []int
 }
 
 // Convenience constructor for a box with a single element
@@ -43,19 +60,122 @@ func NewWithContents(items ...flexbox_item.FlexboxItem) *Flexbox {
 func New() *Flexbox {
 	return &Flexbox{
 		children:                           make([]flexbox_item.FlexboxItem, 0),
+		id:                                 "",
+		classes:                            nil,
 		direction:                          Row,
 		horizontalAlignment:                AlignStart,
 		verticalAlignment:                  AlignStart,
+		isBorderCollapsed:                  false,
+		laidOutChildrenCache:               nil,
 		actualChildWidthsCache:             axisSizeCalculationResults{},
 		desiredChildHeightsGivenWidthCache: nil,
+		childXOffsetsCache:                 nil,
+		childYOffsetsCache:                 nil,
 	}
 }
 
+func (b *Flexbox) GetChildren() []flexbox_item.FlexboxItem {
+	return b.children
+}
+
 func (b *Flexbox) SetChildren(children []flexbox_item.FlexboxItem) *Flexbox {
 	b.children = children
+	b.invalidateCaches()
+	return b
+}
+
+// Inserts the child before the one at the index, with indexes past the end (or before the start) being clamped
+func (b *Flexbox) InsertChild(idx int, child flexbox_item.FlexboxItem) *Flexbox {
+	idx = utilities.GetMaxInt(0, utilities.GetMinInt(idx, len(b.children)))
+	newChildren := make([]flexbox_item.FlexboxItem, 0, len(b.children)+1)
+	newChildren = append(newChildren, b.children[:idx]...)
+	newChildren = append(newChildren, child)
+	newChildren = append(newChildren, b.children[idx:]...)
+	b.children = newChildren
+	b.invalidateCaches()
+	return b
+}
+
+// Removes the child at the index, which loses the focus if it had it; invalid indexes are ignored
+func (b *Flexbox) RemoveChild(idx int) *Flexbox {
+	if idx < 0 || idx >= len(b.children) {
+		return b
+	}
+	b.children[idx].SetFocus(false)
+	newChildren := make([]flexbox_item.FlexboxItem, 0, len(b.children)-1)
+	newChildren = append(newChildren, b.children[:idx]...)
+	newChildren = append(newChildren, b.children[idx+1:]...)
+	b.children = newChildren
+	b.invalidateCaches()
+	return b
+}
+
+// Moves the child at the first index so that it ends up at the second, with the children in between shifting over
+// Invalid indexes are ignored
+func (b *Flexbox) MoveChild(fromIdx int, toIdx int) *Flexbox {
+	if fromIdx < 0 || fromIdx >= len(b.children) || toIdx < 0 || toIdx >= len(b.children) || fromIdx == toIdx {
+		return b
+	}
+	child := b.children[fromIdx]
+	newChildren := make([]flexbox_item.FlexboxItem, 0, len(b.children))
+	for idx, existingChild := range b.children {
+		if idx != fromIdx {
+			newChildren = append(newChildren, existingChild)
+		}
+	}
+	newChildren = append(newChildren[:toIdx], append([]flexbox_item.FlexboxItem{child}, newChildren[toIdx:]...)...)
+	b.children = newChildren
+	b.invalidateCaches()
+	return b
+}
+
+// Replaces the child at the index, moving the focus to the new child if the old one had it; invalid indexes are ignored
+func (b *Flexbox) ReplaceChild(idx int, child flexbox_item.FlexboxItem) *Flexbox {
+	if idx < 0 || idx >= len(b.children) {
+		return b
+	}
+	wasFocused := b.children[idx].IsFocused()
+	b.children[idx].SetFocus(false)
+
+	// Copied so that a slice given to SetChildren doesn't get changed out from under the caller
+	newChildren := make([]flexbox_item.FlexboxItem, len(b.children))
+	copy(newChildren, b.children)
+	newChildren[idx] = child
+	b.children = newChildren
+	if wasFocused {
+		child.SetFocus(true)
+	}
+	b.invalidateCaches()
+	return b
+}
+
+func (b *Flexbox) GetID() string {
+	return b.id
+}
+
+func (b *Flexbox) SetID(id string) *Flexbox {
+	b.id = id
 	return b
 }
 
+func (b *Flexbox) GetClasses() []string {
+	return b.classes
+}
+
+func (b *Flexbox) SetClasses(classes ...string) *Flexbox {
+	b.classes = classes
+	return b
+}
+
+// The children's items, which hold the components
+func (b *Flexbox) GetChildComponents() []components.Component {
+	result := make([]components.Component, len(b.children))
+	for idx, item := range b.children {
+		result[idx] = item
+	}
+	return result
+}
+
 func (b *Flexbox) SetDirection(direction Direction) *Flexbox {
 	b.direction = direction
 	return b
@@ -71,72 +191,138 @@ func (b *Flexbox) SetVerticalAlignment(alignment AxisAlignment) *Flexbox {
 	return b
 }
 
+// Collapsing borders is meant for children that are all bordered (e.g. Styleboxes), as a cell of each child gets drawn
+// over by its neighbor
+// Nested flexboxes with collapsed borders make grids that look like a single drawn table
+func (b *Flexbox) SetBorderCollapse(isCollapsed bool) *Flexbox {
+	b.isBorderCollapsed = isCollapsed
+	return b
+}
+
 func (b *Flexbox) GetContentMinMax() (minWidth int, maxWidth int, minHeight int, maxHeight int) {
-	return b.direction.getContentSizes(b.children)
+	return b.GetContentMinMaxInContext(components.NewLayoutContext())
 }
 
 func (b *Flexbox) GetContentHeightForGivenWidth(width int) int {
+	return b.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (b *Flexbox) View(width int, height int) string {
+	return b.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+// The context gets passed on to the children unchanged
+func (b *Flexbox) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth int, maxWidth int, minHeight int, maxHeight int) {
+	laidOutChildren := b.getLaidOutChildren()
+	minWidth, maxWidth, minHeight, maxHeight = b.direction.getContentSizes(ctx, laidOutChildren)
+
+	overlap := b.getBorderCollapseOverlap(laidOutChildren)
+	if b.direction.isMainAxisHorizontal() {
+		minWidth = utilities.GetMaxInt(0, minWidth-overlap)
+		maxWidth = utilities.GetMaxInt(0, maxWidth-overlap)
+	} else {
+		minHeight = utilities.GetMaxInt(0, minHeight-overlap)
+		maxHeight = utilities.GetMaxInt(0, maxHeight-overlap)
+	}
+	return
+}
+
+func (b *Flexbox) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
 	if width == 0 {
 		return 0
 	}
 
+	laidOutChildren := b.getLaidOutChildren()
+	b.laidOutChildrenCache = laidOutChildren
+
 	// Width
-	desiredChildWidths := make([]int, len(b.children)) // NOTE: we actually already calculated this above, with GetContentMinMax. Maybe cache?
-	shouldGrowWidths := make([]bool, len(b.children))
-	for idx, item := range b.children {
-		_, desiredChildWidths[idx], _, _ = item.GetComponent().GetContentMinMax()
+	desiredChildWidths := make([]int, len(laidOutChildren)) // NOTE: we actually already calculated this above, with GetContentMinMax. Maybe cache?
+	shouldGrowWidths := make([]bool, len(laidOutChildren))
+	for idx, item := range laidOutChildren {
+		_, desiredChildWidths[idx], _, _ = components.GetChildContentMinMax(item.GetComponent(), ctx)
 		shouldGrowWidths[idx] = item.GetMaxWidth().ShouldGrow()
 	}
-	actualWidthsCalcResults := b.direction.getActualWidths(desiredChildWidths, shouldGrowWidths, width)
+	// Overlapping children have the overlap to share out on top of the width
+	widthAvailable := width
+	if b.direction.isMainAxisHorizontal() {
+		widthAvailable += b.getBorderCollapseOverlap(laidOutChildren)
+	}
+	actualWidthsCalcResults := b.direction.getActualWidths(desiredChildWidths, shouldGrowWidths, widthAvailable)
 
 	// Cache the result, so we don't have to recalculate it in View
 	b.actualChildWidthsCache = actualWidthsCalcResults
 
-	result := 0
-	desiredHeights := make([]int, len(b.children))
-	for idx, item := range b.children {
+	desiredHeights := make([]int, len(laidOutChildren))
+	for idx, item := range laidOutChildren {
 		actualWidth := actualWidthsCalcResults.actualSizes[idx]
-		desiredHeight := item.GetContentHeightForGivenWidth(actualWidth)
-
-		desiredHeights[idx] = desiredHeight
-		result = utilities.GetMaxInt(result, desiredHeight)
+		desiredHeights[idx] = item.GetContentHeightForGivenWidthInContext(ctx, actualWidth)
 	}
 
 	// Cache the result, so we don't have to recalculate it in View
 	b.desiredChildHeightsGivenWidthCache = desiredHeights
 
-	return result
+	totalDesiredHeight := b.direction.getTotalDesiredHeight(desiredHeights)
+	if !b.direction.isMainAxisHorizontal() {
+		totalDesiredHeight = utilities.GetMaxInt(0, totalDesiredHeight-b.getBorderCollapseOverlap(laidOutChildren))
+	}
+	return totalDesiredHeight
 }
 
-func (b *Flexbox) View(width int, height int) string {
+func (b *Flexbox) ViewInContext(ctx components.LayoutContext, width int, height int) string {
 	if width == 0 || height == 0 {
 		return ""
 	}
 
+	// The caches are stale if the children changed (or were shown or hidden) since GetContentHeightForGivenWidth, or if
+	// it never got called, so the widths get worked out again rather than indexing past the end of the caches
+	if !b.areCachesValid() {
+		b.GetContentHeightForGivenWidthInContext(ctx, width)
+	}
+
+	laidOutChildren := b.laidOutChildrenCache
 	actualWidths := b.actualChildWidthsCache.actualSizes
 	// widthNotUsedByChildren := utilities.GetMaxInt(0, width-b.actualChildWidthsCache.spaceUsedByChildren)
 
-	shouldGrowHeights := make([]bool, len(b.children))
-	for idx, item := range b.children {
+	shouldGrowHeights := make([]bool, len(laidOutChildren))
+	for idx, item := range laidOutChildren {
 		shouldGrowHeights[idx] = item.GetMaxHeight().ShouldGrow()
 	}
-	actualHeightsCalcResult := b.direction.getActualHeights(b.desiredChildHeightsGivenWidthCache, shouldGrowHeights, height)
+	heightAvailable := height
+	if !b.direction.isMainAxisHorizontal() {
+		heightAvailable += b.getBorderCollapseOverlap(laidOutChildren)
+	}
+	actualHeightsCalcResult := b.direction.getActualHeights(b.desiredChildHeightsGivenWidthCache, shouldGrowHeights, heightAvailable)
 
 	actualHeights := actualHeightsCalcResult.actualSizes
 	// heightNotUsedByChildren := utilities.GetMaxInt(0, height-actualHeightsCalcResult.spaceUsedByChildren)
 
 	// Now render each child
-	allContentFragments := make([]string, len(b.children))
-	for idx, item := range b.children {
+	allContentFragments := make([]string, len(laidOutChildren))
+	for idx, item := range laidOutChildren {
 		childWidth := actualWidths[idx]
 		childHeight := actualHeights[idx]
-		childStr := item.View(childWidth, childHeight)
+		childStr := item.ViewInContext(ctx, childWidth, childHeight)
 
 		allContentFragments[idx] = childStr
 	}
 
+	if b.isBorderCollapsed {
+		return b.renderCollapsedBorders(laidOutChildren, allContentFragments, actualWidths, actualHeights, width, height)
+	}
+
 	content := b.direction.renderContentFragments(allContentFragments, width, height, b.horizontalAlignment, b.verticalAlignment)
 
+	// Cache where the children ended up, so we can route mouse messages to them
+	xOffsets, yOffsets := b.direction.getChildOffsets(
+		actualWidths,
+		actualHeights,
+		width,
+		height,
+		b.horizontalAlignment,
+		b.verticalAlignment,
+	)
+	b.cacheChildOffsets(laidOutChildren, xOffsets, yOffsets)
+
 	/*
 		// Justify main axis
 		switch b.horizontalAlignment {
@@ -180,8 +366,152 @@ func (b *Flexbox) View(width int, height int) string {
 	return content
 }
 
+// Every child gets the message, with mouse messages translated to the position the child had during the last View
+func (b *Flexbox) Update(msg tea.Msg) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(b.children))
+	for idx, item := range b.children {
+		xOffset, yOffset := b.getChildOffset(idx)
+		cmds = append(cmds, components.UpdateChild(item, msg, xOffset, yOffset))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Focusing the flexbox focuses its first child that accepts the focus
+func (b *Flexbox) SetFocus(isFocused bool) {
+	hasFocusedChild := false
+	for _, item := range b.children {
+		if !isFocused || hasFocusedChild {
+			item.SetFocus(false)
+			continue
+		}
+		item.SetFocus(true)
+		hasFocusedChild = item.IsFocused()
+	}
+}
+
+// Collects the children's overlays, positioned where the children were during the last View
+func (b *Flexbox) GetOverlays() []components.Overlay {
+	result := make([]components.Overlay, 0)
+	for idx, item := range b.children {
+		xOffset, yOffset := b.getChildOffset(idx)
+		result = append(result, components.GetChildOverlays(item, xOffset, yOffset)...)
+	}
+	return result
+}
+
+func (b *Flexbox) IsFocused() bool {
+	for _, item := range b.children {
+		if item.IsFocused() {
+			return true
+		}
+	}
+	return false
+}
+
 // ====================================================================================================
 //
 //	Private Helper Functions
 //
 // ====================================================================================================
+
+// Gets how many cells the children overlap by in total along the main axis
+func (b *Flexbox) getBorderCollapseOverlap(laidOutChildren []flexbox_item.FlexboxItem) int {
+	if !b.isBorderCollapsed || len(laidOutChildren) < 2 {
+		return 0
+	}
+	return len(laidOutChildren) - 1
+}
+
+// Drops everything calculated from the children, for when they change
+func (b *Flexbox) invalidateCaches() {
+	b.laidOutChildrenCache = nil
+	b.actualChildWidthsCache = axisSizeCalculationResults{}
+	b.desiredChildHeightsGivenWidthCache = nil
+	b.childXOffsetsCache = nil
+	b.childYOffsetsCache = nil
+}
+
+// Checks that the caches from GetContentHeightForGivenWidth line up with the children as they are now
+func (b *Flexbox) areCachesValid() bool {
+	laidOutChildren := b.getLaidOutChildren()
+	if b.laidOutChildrenCache == nil ||
+		len(b.laidOutChildrenCache) != len(laidOutChildren) ||
+		len(b.actualChildWidthsCache.actualSizes) != len(laidOutChildren) ||
+		len(b.desiredChildHeightsGivenWidthCache) != len(laidOutChildren) {
+		return false
+	}
+	for idx, item := range laidOutChildren {
+		if b.laidOutChildrenCache[idx] != item {
+			return false
+		}
+	}
+	return true
+}
+
+// Gets the children that take part in the layout, leaving out the collapsed ones
+func (b *Flexbox) getLaidOutChildren() []flexbox_item.FlexboxItem {
+	result := make([]flexbox_item.FlexboxItem, 0, len(b.children))
+	for _, item := range b.children {
+		if item.IsLaidOut() {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Caches the offsets of the laid-out children against all the children, with the collapsed ones (which don't get
+// mouse messages anyway) put at the top-left corner
+func (b *Flexbox) cacheChildOffsets(laidOutChildren []flexbox_item.FlexboxItem, xOffsets []int, yOffsets []int) {
+	b.childXOffsetsCache = make([]int, len(b.children))
+	b.childYOffsetsCache = make([]int, len(b.children))
+	laidOutIdx := 0
+	for idx, item := range b.children {
+		if laidOutIdx >= len(laidOutChildren) || item != laidOutChildren[laidOutIdx] {
+			continue
+		}
+		b.childXOffsetsCache[idx] = xOffsets[laidOutIdx]
+		b.childYOffsetsCache[idx] = yOffsets[laidOutIdx]
+		laidOutIdx++
+	}
+}
+
+// Lays the children out with each one (apart from the last) giving up its last cell along the main axis to the next
+func (b *Flexbox) renderCollapsedBorders(laidOutChildren []flexbox_item.FlexboxItem, fragments []string, actualWidths []int, actualHeights []int, width int, height int) string {
+	overlappedWidths := make([]int, len(actualWidths))
+	overlappedHeights := make([]int, len(actualHeights))
+	copy(overlappedWidths, actualWidths)
+	copy(overlappedHeights, actualHeights)
+	overlappedSizes := overlappedHeights
+	if b.direction.isMainAxisHorizontal() {
+		overlappedSizes = overlappedWidths
+	}
+	for idx := 0; idx < len(overlappedSizes)-1; idx++ {
+		overlappedSizes[idx] = utilities.GetMaxInt(0, overlappedSizes[idx]-1)
+	}
+
+	xOffsets, yOffsets := b.direction.getChildOffsets(
+		overlappedWidths,
+		overlappedHeights,
+		width,
+		height,
+		b.horizontalAlignment,
+		b.verticalAlignment,
+	)
+	b.cacheChildOffsets(laidOutChildren, xOffsets, yOffsets)
+	return drawCollapsedFragments(
+		fragments,
+		xOffsets,
+		yOffsets,
+		width,
+		height,
+		b.direction.isMainAxisHorizontal(),
+	)
+}
+
+// Gets where the child's top-left corner was during the last View
+func (b *Flexbox) getChildOffset(idx int) (xOffset int, yOffset int) {
+	if len(b.childXOffsetsCache) != len(b.children) {
+		return 0, 0
+	}
+	return b.childXOffsetsCache[idx], b.childYOffsetsCache[idx]
+}
@@ -2,10 +2,13 @@ package flexbox
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/button"
 	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
 	"github.com/mieubrisse/box-layout-test/components/stylebox"
 	"github.com/mieubrisse/box-layout-test/components/test_assertions"
 	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -70,3 +73,260 @@ func TestAdvancedColumnLayout(t *testing.T) {
 	component.GetContentHeightForGivenWidth(width)
 	component.View(width, height)
 }
+
+func TestDesiredHeightAlongEachAxis(t *testing.T) {
+	newChildren := func() []flexbox_item.FlexboxItem {
+		return []flexbox_item.FlexboxItem{
+			flexbox_item.New(text.New("a")),
+			flexbox_item.New(text.New("b\nc")),
+			flexbox_item.New(text.New("d")),
+		}
+	}
+
+	// A column stacks its children, so it wants all of their heights together
+	column := NewWithContents(newChildren()...).SetDirection(Column)
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(1, 1, 4, 4),
+		test_assertions.GetHeightAtWidthAssertions(1, 4, 5, 4),
+		test_assertions.GetRenderedContentAssertion(1, 4, "a\nb\nc\nd"),
+	)
+	test_assertions.CheckAll(t, assertions, column)
+
+	// A row only wants the height of its tallest child
+	row := NewWithContents(newChildren()...)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(3, 3, 2, 2),
+		test_assertions.GetHeightAtWidthAssertions(3, 2),
+		test_assertions.GetRenderedContentAssertion(3, 2, "abd\n c "),
+	)
+	test_assertions.CheckAll(t, assertions, row)
+}
+
+func TestCollapsedBorders(t *testing.T) {
+	border := lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+	newCell := func(str string) flexbox_item.FlexboxItem {
+		return flexbox_item.New(stylebox.New(text.New(str)).SetStyle(border))
+	}
+
+	row := NewWithContents(newCell("a"), newCell("b")).SetBorderCollapse(true)
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(5, 5, 3, 3),
+		test_assertions.GetHeightAtWidthAssertions(5, 3),
+		test_assertions.GetRenderedContentAssertion(5, 3, "┌─┬─┐\n│a│b│\n└─┴─┘"),
+	)
+	test_assertions.CheckAll(t, assertions, row)
+
+	grid := NewWithContents(
+		flexbox_item.New(NewWithContents(newCell("a"), newCell("b")).SetBorderCollapse(true)),
+		flexbox_item.New(NewWithContents(newCell("c"), newCell("d")).SetBorderCollapse(true)),
+	).SetDirection(Column).SetBorderCollapse(true)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(5, 5, 5, 5),
+		test_assertions.GetHeightAtWidthAssertions(5, 5),
+		test_assertions.GetRenderedContentAssertion(5, 5, "┌─┬─┐\n│a│b│\n├─┼─┤\n│c│d│\n└─┴─┘"),
+	)
+	test_assertions.CheckAll(t, assertions, grid)
+
+	// Neighbors of different heights still join where they meet
+	uneven := NewWithContents(newCell("a\nb"), newCell("c")).SetBorderCollapse(true)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetHeightAtWidthAssertions(5, 4),
+		test_assertions.GetRenderedContentAssertion(5, 4, "┌─┬─┐\n│a│c│\n│b├─┘\n└─┘  "),
+	)
+	test_assertions.CheckAll(t, assertions, uneven)
+}
+
+func TestLayoutContextIsPassedToChildren(t *testing.T) {
+	probe := &contextProbe{seenValues: map[string][]interface{}{}}
+	box := NewWithContents(
+		flexbox_item.New(text.New("a")),
+		flexbox_item.New(stylebox.New(probe).SetInheritedValue(contextProbeKey("inner"), "from stylebox")),
+	)
+
+	ctx := components.NewLayoutContext().With(contextProbeKey("outer"), "from root")
+	box.GetContentMinMaxInContext(ctx)
+	box.GetContentHeightForGivenWidthInContext(ctx, 10)
+	box.ViewInContext(ctx, 10, 1)
+
+	for _, phase := range []string{"min-max", "height", "view"} {
+		require.Equal(t, []interface{}{"from root", "from stylebox"}, probe.seenValues[phase], "Wrong values in phase '%v'", phase)
+	}
+}
+
+func TestHiddenChildren(t *testing.T) {
+	middle := flexbox_item.New(text.New("bb"))
+	row := NewWithContents(
+		flexbox_item.New(text.New("a")),
+		middle,
+		flexbox_item.New(text.New("c")),
+	)
+
+	// Collapsed children are left out of the layout entirely
+	middle.SetVisible(false)
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(2, 2, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(2, 1),
+		test_assertions.GetRenderedContentAssertion(2, 1, "ac"),
+	)
+	test_assertions.CheckAll(t, assertions, row)
+
+	// Hidden children keep their space
+	middle.SetHiddenMode(flexbox_item.Hidden)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(4, 4, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(4, 1),
+		test_assertions.GetRenderedContentAssertion(4, 1, "a  c"),
+	)
+	test_assertions.CheckAll(t, assertions, row)
+
+	middle.SetVisible(true)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetHeightAtWidthAssertions(4, 1),
+		test_assertions.GetRenderedContentAssertion(4, 1, "abbc"),
+	)
+	test_assertions.CheckAll(t, assertions, row)
+}
+
+func TestQueries(t *testing.T) {
+	status := text.New("OK")
+	statusItem := flexbox_item.New(status).SetID("status").SetClasses("footer")
+	pods := stylebox.New(text.New("pods")).SetID("pods").SetClasses("table", "footer")
+	app := NewWithContents(
+		flexbox_item.New(NewWithContents(statusItem).SetID("inner")),
+		flexbox_item.New(pods),
+	).SetID("app")
+
+	found, isFound := components.FindByID(app, "status")
+	require.True(t, isFound)
+	require.Equal(t, statusItem, found)
+	require.Equal(t, status, found.(flexbox_item.FlexboxItem).GetComponent())
+
+	found, isFound = components.FindByID(app, "pods")
+	require.True(t, isFound)
+	require.Equal(t, pods, found)
+
+	found, isFound = components.FindByID(app, "app")
+	require.True(t, isFound)
+	require.Equal(t, app, found)
+
+	_, isFound = components.FindByID(app, "missing")
+	require.False(t, isFound)
+
+	// Depth-first, in layout order
+	require.Equal(t, []components.Component{statusItem, pods}, components.FindAll(app, components.HasClass("footer")))
+	require.Equal(t, []components.Component{pods}, components.FindAll(app, components.HasClass("table")))
+
+	texts := components.FindAll(app, func(component components.Component) bool {
+		_, ok := component.(text.Text)
+		return ok
+	})
+	require.Len(t, texts, 2)
+}
+
+func TestChildMutations(t *testing.T) {
+	newChild := func(str string) flexbox_item.FlexboxItem {
+		return flexbox_item.New(text.New(str))
+	}
+	row := NewWithContents(newChild("a"), newChild("b"))
+	check := func(expected string) {
+		assertions := test_assertions.FlattenAssertionGroups(
+			test_assertions.GetHeightAtWidthAssertions(len(expected), 1),
+			test_assertions.GetRenderedContentAssertion(len(expected), 1, expected),
+		)
+		test_assertions.CheckAll(t, assertions, row)
+	}
+	check("ab")
+
+	row.InsertChild(1, newChild("c"))
+	check("acb")
+	row.InsertChild(99, newChild("d"))
+	check("acbd")
+	row.MoveChild(3, 0)
+	check("dacb")
+	row.MoveChild(0, 2)
+	check("acdb")
+	row.RemoveChild(1)
+	check("adb")
+	row.ReplaceChild(0, newChild("e"))
+	check("edb")
+
+	// Invalid indexes leave the children alone
+	row.RemoveChild(5).MoveChild(-1, 0).ReplaceChild(3, newChild("f"))
+	check("edb")
+	require.Len(t, row.GetChildren(), 3)
+
+	// The focus moves over to a replacement
+	submit := button.New("Go")
+	row.ReplaceChild(0, flexbox_item.New(submit))
+	row.SetFocus(true)
+	replacement := button.New("Ok")
+	row.ReplaceChild(0, flexbox_item.New(replacement))
+	require.False(t, submit.IsFocused())
+	require.True(t, replacement.IsFocused())
+}
+
+func TestStaleCachesAreRecalculated(t *testing.T) {
+	row := NewWithContents(flexbox_item.New(text.New("a")))
+	row.GetContentMinMax()
+	row.GetContentHeightForGivenWidth(3)
+
+	// Changing the slice directly (rather than with the mutation methods) can't invalidate the caches, and neither can
+	// hiding a child
+	children := append(row.GetChildren(), flexbox_item.New(text.New("b")), flexbox_item.New(text.New("c")))
+	row.children = children
+	require.Equal(t, "abc", row.View(3, 1))
+
+	children[0].SetVisible(false)
+	require.Equal(t, "bc ", row.View(3, 1))
+
+	// View without the earlier phases works too
+	require.Equal(t, "x", NewWithContents(flexbox_item.New(text.New("x"))).View(1, 1))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+type contextProbeKey string
+
+// Records the values it sees in the layout context during each phase
+type contextProbe struct {
+	seenValues map[string][]interface{}
+}
+
+func (p *contextProbe) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return p.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (p *contextProbe) GetContentHeightForGivenWidth(width int) int {
+	return p.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (p *contextProbe) View(width int, height int) string {
+	return p.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (p *contextProbe) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	p.record("min-max", ctx)
+	return 1, 1, 1, 1
+}
+
+func (p *contextProbe) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	p.record("height", ctx)
+	return 1
+}
+
+func (p *contextProbe) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	p.record("view", ctx)
+	return "p"
+}
+
+func (p *contextProbe) record(phase string, ctx components.LayoutContext) {
+	values := make([]interface{}, 0)
+	for _, key := range []string{"outer", "inner"} {
+		if value, found := ctx.Get(contextProbeKey(key)); found {
+			values = append(values, value)
+		}
+	}
+	p.seenValues[phase] = values
+}
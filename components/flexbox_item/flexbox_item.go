@@ -2,8 +2,10 @@ package flexbox_item
 
 import (
 	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/utilities"
 )
 
 type OverflowStyle int
@@ -35,10 +37,19 @@ func WithOverflowStyle(style OverflowStyle) FlexboxItemOpt {
 }
 
 type FlexboxItem interface {
-	components.Component
+	// Messages & focus get passed straight through to the inner component (if it's interactive), as do its overlays and
+	// the layout context
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Identifiable
+	components.Container
 
 	GetComponent() components.Component
 
+	SetID(id string) FlexboxItem
+	SetClasses(classes ...string) FlexboxItem
+
 	GetMinWidth() FlexboxItemDimensionValue
 	SetMinWidth(min FlexboxItemDimensionValue) FlexboxItem
 	GetMaxWidth() FlexboxItemDimensionValue
@@ -51,11 +62,25 @@ type FlexboxItem interface {
 
 	GetOverflowStyle() OverflowStyle
 	SetOverflowStyle(style OverflowStyle) FlexboxItem
+
+	// An item that isn't visible can't be focused (losing the focus if it had it), doesn't contribute overlays and only
+	// passes broadcast messages (see components.IsBroadcastMsg) to the inner component
+	IsVisible() bool
+	SetVisible(isVisible bool) FlexboxItem
+	// Whether a hidden item keeps its space; defaults to Collapsed
+	GetHiddenMode() HiddenMode
+	SetHiddenMode(mode HiddenMode) FlexboxItem
+
+	// Whether the item takes part in the layout (i.e. it's visible, or hidden while keeping its space)
+	IsLaidOut() bool
 }
 
 type flexboxItemImpl struct {
 	component components.Component
 
+	id      string
+	classes []string
+
 	// These determine how the item flexes
 	// This is analogous to both "flex-basis" and "flex-grow", where:
 	// - MaxAvailable indicates "flex-grow: >1" (see weight below)
@@ -67,23 +92,49 @@ type flexboxItemImpl struct {
 
 	overflowStyle OverflowStyle
 
+	isVisible  bool
+	hiddenMode HiddenMode
+
 	// TODO weight (analogous to flex-grow)
 	// When the child size constraint is set to MaxAvailable, then this will be used
 }
 
 func New(component components.Component) FlexboxItem {
+	var maxWidth FlexboxItemDimensionValue = MaxContent
+	if filler, ok := component.(components.WidthFiller); ok && filler.FillsWidth() {
+		maxWidth = MaxAvailable
+	}
 	return &flexboxItemImpl{
 		component:     component,
+		id:            "",
+		classes:       nil,
 		minWidth:      MinContent,
-		maxWidth:      MaxContent,
+		maxWidth:      maxWidth,
 		minHeight:     MinContent,
 		maxHeight:     MaxContent,
 		overflowStyle: Wrap,
+		isVisible:     true,
+		hiddenMode:    Collapsed,
 	}
 }
 
 func (item *flexboxItemImpl) GetContentMinMax() (minWidth int, maxWidth int, minHeight int, maxHeight int) {
-	innerMinWidth, innerMaxWidth, innerMinHeight, innerMaxHeight := item.GetComponent().GetContentMinMax()
+	return item.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (item *flexboxItemImpl) GetContentHeightForGivenWidth(width int) int {
+	return item.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (item *flexboxItemImpl) View(width int, height int) string {
+	return item.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (item *flexboxItemImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth int, maxWidth int, minHeight int, maxHeight int) {
+	if !item.IsLaidOut() {
+		return 0, 0, 0, 0
+	}
+	innerMinWidth, innerMaxWidth, innerMinHeight, innerMaxHeight := components.GetChildContentMinMax(item.GetComponent(), ctx)
 	itemMinWidth, itemMaxWidth, itemMinHeight, itemMaxHeight := calculateFlexboxItemContentSizesFromInnerContentSizes(
 		innerMinWidth,
 		innerMaxWidth,
@@ -95,14 +146,30 @@ func (item *flexboxItemImpl) GetContentMinMax() (minWidth int, maxWidth int, min
 	return itemMinWidth, itemMaxWidth, itemMinHeight, itemMaxHeight
 }
 
-func (item *flexboxItemImpl) GetContentHeightForGivenWidth(width int) int {
-	return item.component.GetContentHeightForGivenWidth(width)
+func (item *flexboxItemImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 || !item.IsLaidOut() {
+		return 0
+	}
+	result := components.GetChildContentHeightForGivenWidth(item.component, ctx, width)
+
+	// Fixed heights apply here too, so that e.g. a fixed max height caps how far a component that grows with its
+	// contents can grow (with the min winning if they conflict, as in GetContentMinMax)
+	if fixedMaxHeight, isFixed := item.GetMaxHeight().getFixedSize(); isFixed {
+		result = utilities.GetMinInt(result, fixedMaxHeight)
+	}
+	if fixedMinHeight, isFixed := item.GetMinHeight().getFixedSize(); isFixed {
+		result = utilities.GetMaxInt(result, fixedMinHeight)
+	}
+	return result
 }
 
-func (item *flexboxItemImpl) View(width int, height int) string {
-	if width == 0 || height == 0 {
+func (item *flexboxItemImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	if width == 0 || height == 0 || !item.IsLaidOut() {
 		return ""
 	}
+	if !item.isVisible {
+		return lipgloss.NewStyle().Width(width).Height(height).Render("")
+	}
 
 	component := item.GetComponent()
 
@@ -113,14 +180,14 @@ func (item *flexboxItemImpl) View(width int, height int) string {
 	case Truncate:
 		// If truncating, the child will _think_ they have infinite space available
 		// and then we'll truncate them later
-		_, maxWidth, _, _ := component.GetContentMinMax()
+		_, maxWidth, _, _ := components.GetChildContentMinMax(component, ctx)
 		widthWhenRendering = maxWidth
 	default:
 		panic(fmt.Sprintf("Unknown item overflow style: %v", item.GetOverflowStyle()))
 	}
 
 	// TODO allow column format
-	result := component.View(widthWhenRendering, height)
+	result := components.ViewChild(component, ctx, widthWhenRendering, height)
 
 	// Truncate, in case the inner item runs over (which will almost definitely be the case when overflowStyle = Truncate)
 	result = lipgloss.NewStyle().
@@ -137,10 +204,56 @@ func (item *flexboxItemImpl) View(width int, height int) string {
 	return result
 }
 
+func (item *flexboxItemImpl) Update(msg tea.Msg) tea.Cmd {
+	if !item.isVisible && !components.IsBroadcastMsg(msg) {
+		return nil
+	}
+	// The inner component always gets rendered at the item's top-left corner
+	return components.UpdateChild(item.component, msg, 0, 0)
+}
+
+func (item *flexboxItemImpl) SetFocus(isFocused bool) {
+	components.SetChildFocus(item.component, isFocused && item.isVisible)
+}
+
+func (item *flexboxItemImpl) IsFocused() bool {
+	return components.IsChildFocused(item.component)
+}
+
+func (item *flexboxItemImpl) GetOverlays() []components.Overlay {
+	if !item.isVisible {
+		return nil
+	}
+	// Overlays aren't part of the layout, so they don't get truncated along with the inner component
+	return components.GetChildOverlays(item.component, 0, 0)
+}
+
 func (item *flexboxItemImpl) GetComponent() components.Component {
 	return item.component
 }
 
+func (item *flexboxItemImpl) GetChildComponents() []components.Component {
+	return []components.Component{item.component}
+}
+
+func (item *flexboxItemImpl) GetID() string {
+	return item.id
+}
+
+func (item *flexboxItemImpl) SetID(id string) FlexboxItem {
+	item.id = id
+	return item
+}
+
+func (item *flexboxItemImpl) GetClasses() []string {
+	return item.classes
+}
+
+func (item *flexboxItemImpl) SetClasses(classes ...string) FlexboxItem {
+	item.classes = classes
+	return item
+}
+
 func (item *flexboxItemImpl) GetMinWidth() FlexboxItemDimensionValue {
 	return item.minWidth
 }
@@ -186,6 +299,31 @@ func (item *flexboxItemImpl) SetOverflowStyle(style OverflowStyle) FlexboxItem {
 	return item
 }
 
+func (item *flexboxItemImpl) IsVisible() bool {
+	return item.isVisible
+}
+
+func (item *flexboxItemImpl) SetVisible(isVisible bool) FlexboxItem {
+	if !isVisible {
+		components.SetChildFocus(item.component, false)
+	}
+	item.isVisible = isVisible
+	return item
+}
+
+func (item *flexboxItemImpl) GetHiddenMode() HiddenMode {
+	return item.hiddenMode
+}
+
+func (item *flexboxItemImpl) SetHiddenMode(mode HiddenMode) FlexboxItem {
+	item.hiddenMode = mode
+	return item
+}
+
+func (item *flexboxItemImpl) IsLaidOut() bool {
+	return item.isVisible || item.hiddenMode == Hidden
+}
+
 // ====================================================================================================
 //                                   Private Helper Functions
 // ====================================================================================================
@@ -6,6 +6,9 @@ type FlexboxItemDimensionValue interface {
 
 	// Given a min and a max, gets the corresponding size based on what FlexboxItemDimensionValue this is
 	getSizeRetriever() func(min, max int) int
+
+	// Gets the size if it's a FixedSize, which doesn't depend on the content at all
+	getFixedSize() (int, bool)
 }
 
 // Indicates a size == the minimum content size of the item, which:
@@ -43,6 +46,7 @@ func FixedSize(size int) FlexboxItemDimensionValue {
 			return size
 		},
 		shouldGrow: false,
+		isFixed:    true,
 	}
 }
 
@@ -58,6 +62,9 @@ type dimensionValueImpl struct {
 
 	// Whether this item should expand to consume additional free space beyond its min and max
 	shouldGrow bool
+
+	// Whether this is a FixedSize, meaning the size retriever returns the same thing no matter the min & max
+	isFixed bool
 }
 
 func (impl dimensionValueImpl) getSizeRetriever() func(min int, max int) int {
@@ -68,3 +75,10 @@ func (impl dimensionValueImpl) getSizeRetriever() func(min int, max int) int {
 func (impl dimensionValueImpl) ShouldGrow() bool {
 	return impl.shouldGrow
 }
+
+func (impl dimensionValueImpl) getFixedSize() (int, bool) {
+	if !impl.isFixed {
+		return 0, false
+	}
+	return impl.sizeRetriever(0, 0), true
+}
@@ -2,9 +2,11 @@ package flexbox_item
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/button"
 	"github.com/mieubrisse/box-layout-test/components/stylebox"
 	"github.com/mieubrisse/box-layout-test/components/test_assertions"
 	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -50,6 +52,35 @@ func TestWrap(t *testing.T) {
 	test_assertions.CheckAll(t, assertions, component)
 }
 
+func TestFixedHeights(t *testing.T) {
+	// The text wraps to one word per line when narrow
+	capped := New(text.New("aa bb cc dd")).SetMaxHeight(FixedSize(2))
+	test_assertions.CheckAll(t, test_assertions.GetHeightAtWidthAssertions(
+		2, 2,
+		11, 1,
+	), capped)
+
+	raised := New(text.New("aa bb cc dd")).SetMinHeight(FixedSize(3))
+	test_assertions.CheckAll(t, test_assertions.GetHeightAtWidthAssertions(
+		2, 4,
+		11, 3,
+	), raised)
+
+	// The min wins when they conflict
+	conflicting := New(text.New("aa bb cc dd")).SetMinHeight(FixedSize(3)).SetMaxHeight(FixedSize(2))
+	test_assertions.CheckAll(t, test_assertions.GetHeightAtWidthAssertions(
+		2, 3,
+		11, 3,
+	), conflicting)
+
+	// Sizes that depend on the content leave the height at the width alone
+	unfixed := New(text.New("aa bb cc dd")).SetMaxHeight(MinContent)
+	test_assertions.CheckAll(t, test_assertions.GetHeightAtWidthAssertions(
+		2, 4,
+		11, 1,
+	), unfixed)
+}
+
 func TestStyleboxInside(t *testing.T) {
 	contained := stylebox.New(text.New("This is child 2")).
 		SetStyle(lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()))
@@ -62,3 +93,29 @@ func TestStyleboxInside(t *testing.T) {
 	test_assertions.CheckAll(t, assertions, component)
 
 }
+
+func TestInvisible(t *testing.T) {
+	submit := button.New("Go")
+	component := New(submit)
+	component.SetFocus(true)
+	require.True(t, submit.IsFocused())
+
+	// Hiding takes the focus away, and it can't come back until the item is shown again
+	component.SetVisible(false)
+	require.False(t, submit.IsFocused())
+	component.SetFocus(true)
+	require.False(t, component.IsFocused())
+
+	test_assertions.CheckAll(t, test_assertions.GetContentSizeAssertions(0, 0, 0, 0), component)
+
+	component.SetHiddenMode(Hidden)
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(6, 6, 1, 1),
+		test_assertions.GetRenderedContentAssertion(6, 1, "      "),
+	)
+	test_assertions.CheckAll(t, assertions, component)
+
+	component.SetVisible(true)
+	component.SetFocus(true)
+	require.True(t, submit.IsFocused())
+}
@@ -0,0 +1,14 @@
+package flexbox_item
+
+// HiddenMode is how an item that isn't visible affects the layout
+type HiddenMode int
+
+const (
+	// The item takes no part in the layout, as if it wasn't in the flexbox at all
+	// Analogous to "display: none" in CSS
+	Collapsed HiddenMode = iota
+
+	// The item keeps its space, which gets left blank
+	// Analogous to "visibility: hidden" in CSS
+	Hidden
+)
@@ -0,0 +1,119 @@
+package form
+
+import (
+	"github.com/mieubrisse/box-layout-test/components"
+	"strconv"
+	"strings"
+)
+
+// ValueGetter gets the current value of a field, for validating & submitting it
+type ValueGetter func() string
+
+// FieldValidator checks the value of a single field, returning the error to show under the field if it's invalid
+type FieldValidator func(value string) error
+
+// Field is a labelled component in a form
+type Field interface {
+	// The key that the field's value gets stored under in the form's Values
+	GetName() string
+
+	GetLabel() string
+	SetLabel(label string) Field
+
+	GetComponent() components.Component
+
+	// By default the value gets read from the component if it's one of the known input components (text inputs, text
+	// areas, dropdowns, checkboxes, toggles & radio groups), and is empty otherwise
+	GetValue() string
+	SetValueGetter(getter ValueGetter) Field
+
+	// The validators get run in the order they were added, and the first error is the one that gets shown
+	GetValidators() []FieldValidator
+	AddValidator(validator FieldValidator) Field
+}
+
+type fieldImpl struct {
+	name string
+
+	label string
+
+	component components.Component
+
+	// Nil means the value gets read from the component
+	valueGetter ValueGetter
+
+	validators []FieldValidator
+}
+
+func NewField(name string, label string, component components.Component) Field {
+	return &fieldImpl{
+		name:        name,
+		label:       sanitizeLabel(label),
+		component:   component,
+		valueGetter: nil,
+		validators:  make([]FieldValidator, 0),
+	}
+}
+
+func (f fieldImpl) GetName() string {
+	return f.name
+}
+
+func (f fieldImpl) GetLabel() string {
+	return f.label
+}
+
+func (f *fieldImpl) SetLabel(label string) Field {
+	f.label = sanitizeLabel(label)
+	return f
+}
+
+func (f fieldImpl) GetComponent() components.Component {
+	return f.component
+}
+
+func (f fieldImpl) GetValue() string {
+	if f.valueGetter != nil {
+		return f.valueGetter()
+	}
+	return getComponentValue(f.component)
+}
+
+func (f *fieldImpl) SetValueGetter(getter ValueGetter) Field {
+	f.valueGetter = getter
+	return f
+}
+
+func (f fieldImpl) GetValidators() []FieldValidator {
+	return f.validators
+}
+
+func (f *fieldImpl) AddValidator(validator FieldValidator) Field {
+	f.validators = append(f.validators, validator)
+	return f
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Reads the value of the known input components by their methods, so the form doesn't depend on their packages
+func getComponentValue(component components.Component) string {
+	switch castedComponent := component.(type) {
+	case interface{ GetValue() string }:
+		return castedComponent.GetValue()
+	case interface{ GetSelectedOption() (string, bool) }:
+		option, _ := castedComponent.GetSelectedOption()
+		return option
+	case interface{ IsChecked() bool }:
+		return strconv.FormatBool(castedComponent.IsChecked())
+	case interface{ IsOn() bool }:
+		return strconv.FormatBool(castedComponent.IsOn())
+	}
+	return ""
+}
+
+// Labels are a single line
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
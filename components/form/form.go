@@ -0,0 +1,542 @@
+package form
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/components/textinput"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+// The space between the label column & the field column
+const labelGap = "  "
+
+// Values are the values of a form's fields, by field name
+type Values map[string]string
+
+// Validator checks the values of the form as a whole (e.g. that two password fields match), returning the errors to
+// show by field name
+type Validator func(values Values) map[string]error
+
+// Form lays out labelled fields with the labels in a column, so that the fields line up; when there isn't enough width
+// for both columns, each label goes above its field instead
+// A field's validation errors get shown under it once the user has moved off it or tried to submit
+// The errors shown get brought up to date when the form gets a message or is submitted, rather than while laying out,
+// so values set directly on the fields' components show their errors after the next message
+// While the form has the focus, Tab & Shift+Tab move the focus between the fields
+// The form gets submitted by pressing Enter in one of its text inputs, activating one of its buttons, or calling Submit
+// Analogous to the <form> tag in HTML
+type Form interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Container
+
+	GetFields() []Field
+	AddField(field Field) Form
+
+	// Cross-field validators, which get run after the field validators
+	GetValidators() []Validator
+	AddValidator(validator Validator) Form
+
+	GetValues() Values
+
+	// Runs all the validators, returning the errors by field name
+	// This doesn't show the errors; see Submit
+	Validate() map[string]error
+
+	// Shows the errors on all the fields, and sends a SubmittedMsg if there aren't any (otherwise the focus moves to the
+	// first field with an error)
+	Submit() tea.Cmd
+
+	GetStyles() Styles
+	SetStyles(styles Styles) Form
+}
+
+// SubmittedMsg is sent when a form gets submitted with no validation errors
+type SubmittedMsg struct {
+	Form   Form
+	Values Values
+}
+
+type formImpl struct {
+	fields []Field
+
+	validators []Validator
+
+	// The names of the fields whose errors get shown, because the user has moved off them or tried to submit
+	touchedFieldNames map[string]bool
+
+	// Nil for the default styles
+	styles *Styles
+
+	// Validation errors as of the last change the form knows about (i.e. the last Update, Submit, or change to the fields
+	// or validators), so the layout phases only read them
+	errorsCache map[string]error
+
+	// Where each field got rendered in the last View, for routing mouse messages & placing overlays
+	fieldLayoutsCache []fieldLayout
+}
+
+// Where a field & its parts go within the form
+type fieldLayout struct {
+	// The line the field's section of the form starts on
+	top int
+
+	// The line the label goes on (only if the field has one), relative to the top of the section
+	labelY int
+
+	// The position of the field's component, relative to the top-left corner of the section
+	fieldX      int
+	fieldY      int
+	fieldWidth  int
+	fieldHeight int
+
+	// Shown under the field, wrapped to the width to the right of the label column
+	errorLines []string
+
+	// The total height of the section
+	height int
+}
+
+func New(fields ...Field) Form {
+	result := &formImpl{
+		fields:            fields,
+		validators:        make([]Validator, 0),
+		touchedFieldNames: map[string]bool{},
+		styles:            nil,
+		errorsCache:       map[string]error{},
+		fieldLayoutsCache: nil,
+	}
+	result.refreshErrors()
+	return result
+}
+
+func (f formImpl) GetFields() []Field {
+	return f.fields
+}
+
+func (f *formImpl) AddField(field Field) Form {
+	f.fields = append(f.fields, field)
+	f.refreshErrors()
+	return f
+}
+
+// The fields' components
+func (f formImpl) GetChildComponents() []components.Component {
+	result := make([]components.Component, len(f.fields))
+	for idx, field := range f.fields {
+		result[idx] = field.GetComponent()
+	}
+	return result
+}
+
+func (f formImpl) GetValidators() []Validator {
+	return f.validators
+}
+
+func (f *formImpl) AddValidator(validator Validator) Form {
+	f.validators = append(f.validators, validator)
+	f.refreshErrors()
+	return f
+}
+
+func (f formImpl) GetValues() Values {
+	result := Values{}
+	for _, field := range f.fields {
+		result[field.GetName()] = field.GetValue()
+	}
+	return result
+}
+
+func (f formImpl) Validate() map[string]error {
+	result := map[string]error{}
+	for _, field := range f.fields {
+		value := field.GetValue()
+		for _, validator := range field.GetValidators() {
+			if err := validator(value); err != nil {
+				result[field.GetName()] = err
+				break
+			}
+		}
+	}
+
+	// Cross-field errors don't replace the errors of the individual fields, which are more specific
+	values := f.GetValues()
+	for _, validator := range f.validators {
+		for name, err := range validator(values) {
+			if _, found := result[name]; !found && err != nil {
+				result[name] = err
+			}
+		}
+	}
+	return result
+}
+
+func (f *formImpl) Submit() tea.Cmd {
+	for _, field := range f.fields {
+		f.touchedFieldNames[field.GetName()] = true
+	}
+
+	f.refreshErrors()
+	errs := f.errorsCache
+	if len(errs) > 0 {
+		for idx, field := range f.fields {
+			if _, found := errs[field.GetName()]; found && f.focusField(idx) {
+				break
+			}
+		}
+		return nil
+	}
+
+	submittedMsg := SubmittedMsg{
+		Form:   f,
+		Values: f.GetValues(),
+	}
+	return func() tea.Msg {
+		return submittedMsg
+	}
+}
+
+func (f formImpl) GetStyles() Styles {
+	if f.styles == nil {
+		return DefaultStyles()
+	}
+	return *f.styles
+}
+
+func (f *formImpl) SetStyles(styles Styles) Form {
+	f.styles = &styles
+	return f
+}
+
+func (f *formImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return f.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (f *formImpl) GetContentHeightForGivenWidth(width int) int {
+	return f.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (f *formImpl) View(width int, height int) string {
+	return f.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (f *formImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	// When stacked, the fields (and labels) each get the full width
+	for _, field := range f.fields {
+		fieldMinWidth, _, _, _ := components.GetChildContentMinMax(field.GetComponent(), ctx)
+		minWidth = utilities.GetMaxInt(minWidth, utilities.GetMaxInt(fieldMinWidth, lipgloss.Width(field.GetLabel())))
+	}
+
+	maxWidth = minWidth
+	labelColumnWidth := f.getLabelColumnWidth()
+	for _, field := range f.fields {
+		_, fieldMaxWidth, _, _ := components.GetChildContentMinMax(field.GetComponent(), ctx)
+		maxWidth = utilities.GetMaxInt(maxWidth, labelColumnWidth+fieldMaxWidth)
+	}
+
+	// Like text, the height is smallest at the largest width
+	minHeight = f.GetContentHeightForGivenWidthInContext(ctx, maxWidth)
+	maxHeight = f.GetContentHeightForGivenWidthInContext(ctx, minWidth)
+	return
+}
+
+func (f *formImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 {
+		return 0
+	}
+	result := 0
+	for _, layout := range f.getFieldLayouts(ctx, width) {
+		result += layout.height
+	}
+	return result
+}
+
+func (f *formImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	if width == 0 || height == 0 {
+		f.fieldLayoutsCache = nil
+		return ""
+	}
+
+	layouts := f.getFieldLayouts(ctx, width)
+	f.fieldLayoutsCache = layouts
+
+	lines := make([]string, 0, height)
+	for idx, field := range f.fields {
+		layout := layouts[idx]
+		sectionLines := make([]string, layout.height)
+
+		if field.GetLabel() != "" {
+			labelStyle := f.GetStyles().Label
+			if components.IsChildFocused(field.GetComponent()) {
+				labelStyle = f.GetStyles().FocusedLabel
+			}
+			labelWidth := width
+			if layout.labelY == layout.fieldY {
+				labelWidth = layout.fieldX
+			}
+			sectionLines[layout.labelY] = control.FitToWidth(labelStyle.Render(field.GetLabel()), labelWidth)
+		}
+
+		componentLines := strings.Split(components.ViewChild(field.GetComponent(), ctx, layout.fieldWidth, layout.fieldHeight), "\n")
+		for lineIdx := 0; lineIdx < layout.fieldHeight; lineIdx++ {
+			componentLine := ""
+			if lineIdx < len(componentLines) {
+				componentLine = componentLines[lineIdx]
+			}
+			sectionLineIdx := layout.fieldY + lineIdx
+			sectionLines[sectionLineIdx] = control.FitToWidth(sectionLines[sectionLineIdx], layout.fieldX) +
+				control.FitToWidth(componentLine, layout.fieldWidth)
+		}
+
+		for lineIdx, errorLine := range layout.errorLines {
+			sectionLines[layout.fieldY+layout.fieldHeight+lineIdx] = strings.Repeat(" ", layout.fieldX) +
+				f.GetStyles().Error.Render(errorLine)
+		}
+
+		for _, line := range sectionLines {
+			lines = append(lines, control.FitToWidth(line, width))
+		}
+	}
+
+	// The parent should have given us the height we asked for, but just in case
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+func (f *formImpl) Update(msg tea.Msg) tea.Cmd {
+	cmd := f.handleMsg(msg)
+
+	// The message may have changed the values
+	f.refreshErrors()
+	return cmd
+}
+
+func (f *formImpl) SetFocus(isFocused bool) {
+	if !isFocused {
+		for _, field := range f.fields {
+			f.unfocusField(field)
+		}
+		return
+	}
+
+	for idx := range f.fields {
+		if f.focusField(idx) {
+			return
+		}
+	}
+}
+
+func (f formImpl) IsFocused() bool {
+	return f.getFocusedFieldIdx() != -1
+}
+
+func (f *formImpl) GetOverlays() []components.Overlay {
+	result := make([]components.Overlay, 0)
+	for idx, field := range f.fields {
+		xOffset, yOffset := f.getFieldOffset(idx)
+		result = append(result, components.GetChildOverlays(field.GetComponent(), xOffset, yOffset)...)
+	}
+	return result
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Gets the width of the label column, including the gap after it
+func (f formImpl) getLabelColumnWidth() int {
+	result := 0
+	for _, field := range f.fields {
+		result = utilities.GetMaxInt(result, lipgloss.Width(field.GetLabel()))
+	}
+	if result == 0 {
+		return 0
+	}
+	return result + lipgloss.Width(labelGap)
+}
+
+// The labels go above the fields when there isn't room for every field beside the label column
+func (f formImpl) isStacked(ctx components.LayoutContext, width int) bool {
+	labelColumnWidth := f.getLabelColumnWidth()
+	for _, field := range f.fields {
+		fieldMinWidth, _, _, _ := components.GetChildContentMinMax(field.GetComponent(), ctx)
+		if labelColumnWidth+fieldMinWidth > width {
+			return true
+		}
+	}
+	return false
+}
+
+func (f formImpl) getFieldLayouts(ctx components.LayoutContext, width int) []fieldLayout {
+	isStacked := f.isStacked(ctx, width)
+	labelColumnWidth := f.getLabelColumnWidth()
+
+	result := make([]fieldLayout, len(f.fields))
+	top := 0
+	for idx, field := range f.fields {
+		hasLabel := field.GetLabel() != ""
+		layout := fieldLayout{
+			top:    top,
+			labelY: 0,
+			fieldX: labelColumnWidth,
+			fieldY: 0,
+		}
+		if isStacked {
+			layout.fieldX = 0
+			if hasLabel {
+				layout.fieldY = 1
+			}
+		}
+
+		// The field is never given more width than it wants, like a flexbox item with the default max width
+		_, fieldMaxWidth, _, _ := components.GetChildContentMinMax(field.GetComponent(), ctx)
+		layout.fieldWidth = utilities.GetMinInt(width-layout.fieldX, fieldMaxWidth)
+		layout.fieldHeight = components.GetChildContentHeightForGivenWidth(field.GetComponent(), ctx, layout.fieldWidth)
+
+		if err, found := f.errorsCache[field.GetName()]; found && f.touchedFieldNames[field.GetName()] {
+			layout.errorLines = strings.Split(text.Wrap(sanitizeLabel(err.Error()), width-layout.fieldX), "\n")
+		}
+
+		layout.height = layout.fieldY + layout.fieldHeight + len(layout.errorLines)
+		if hasLabel {
+			// The label still needs its line, even if the field is empty
+			layout.height = utilities.GetMaxInt(layout.height, layout.labelY+1)
+		}
+
+		result[idx] = layout
+		top += layout.height
+	}
+	return result
+}
+
+// Gets the position of the field's component within the form, as of the last View
+func (f formImpl) getFieldOffset(idx int) (int, int) {
+	if len(f.fieldLayoutsCache) != len(f.fields) {
+		return 0, 0
+	}
+	layout := f.fieldLayoutsCache[idx]
+	return layout.fieldX, layout.top + layout.fieldY
+}
+
+// Gets the field whose component is at the position (relative to the form) as of the last View, or -1 if there isn't one
+func (f formImpl) getFieldIdxAt(x int, y int) int {
+	if len(f.fieldLayoutsCache) != len(f.fields) {
+		return -1
+	}
+	for idx, layout := range f.fieldLayoutsCache {
+		xOffset, yOffset := f.getFieldOffset(idx)
+		if x >= xOffset && x < xOffset+layout.fieldWidth && y >= yOffset && y < yOffset+layout.fieldHeight {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (f formImpl) getFocusedFieldIdx() int {
+	for idx, field := range f.fields {
+		if components.IsChildFocused(field.GetComponent()) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (f formImpl) hasFieldComponent(component components.Component) bool {
+	for _, field := range f.fields {
+		if field.GetComponent() == component {
+			return true
+		}
+	}
+	return false
+}
+
+// Moves the focus by the number of fields that accept it, wrapping around at the ends
+func (f *formImpl) moveFocus(delta int) {
+	numFields := len(f.fields)
+	currentIdx := f.getFocusedFieldIdx()
+	if currentIdx == -1 && delta < 0 {
+		currentIdx = numFields
+	}
+	for step := 1; step <= numFields; step++ {
+		idx := ((currentIdx+delta*step)%numFields + numFields) % numFields
+		if idx == currentIdx {
+			return
+		}
+		if f.focusField(idx) {
+			return
+		}
+	}
+}
+
+// Moves the focus to the field if it accepts it, returning whether it did
+func (f *formImpl) focusField(idx int) bool {
+	if !components.SetChildFocus(f.fields[idx].GetComponent(), true) {
+		return false
+	}
+	for otherIdx, field := range f.fields {
+		if otherIdx != idx {
+			f.unfocusField(field)
+		}
+	}
+	return true
+}
+
+// Moving off a field is what makes its errors show
+func (f *formImpl) unfocusField(field Field) {
+	if components.IsChildFocused(field.GetComponent()) {
+		f.touchedFieldNames[field.GetName()] = true
+	}
+	components.SetChildFocus(field.GetComponent(), false)
+}
+
+// Does what the form itself does with the message, and passes it on to the fields
+func (f *formImpl) handleMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if f.IsFocused() {
+			switch msg.Type {
+			case tea.KeyTab:
+				f.moveFocus(1)
+				return nil
+			case tea.KeyShiftTab:
+				f.moveFocus(-1)
+				return nil
+			}
+		}
+	case tea.MouseMsg:
+		// Clicking on a field moves the focus to it
+		if msg.Type == tea.MouseLeft {
+			if idx := f.getFieldIdxAt(msg.X, msg.Y); idx != -1 {
+				f.focusField(idx)
+			}
+		}
+	case textinput.SubmittedMsg:
+		if f.hasFieldComponent(msg.Input) {
+			return f.Submit()
+		}
+	case button.PressedMsg:
+		if f.hasFieldComponent(msg.Button) {
+			return f.Submit()
+		}
+	}
+
+	cmds := make([]tea.Cmd, len(f.fields))
+	for idx, field := range f.fields {
+		xOffset, yOffset := f.getFieldOffset(idx)
+		cmds[idx] = components.UpdateChild(field.GetComponent(), msg, xOffset, yOffset)
+	}
+	return tea.Batch(cmds...)
+}
+
+// Runs the validators, which is left to the methods that can change the values so the layout phases only read
+func (f *formImpl) refreshErrors() {
+	f.errorsCache = f.Validate()
+}
@@ -0,0 +1,202 @@
+package form
+
+import (
+	"errors"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/checkbox"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/textinput"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestAlignedAndStackedLayouts(t *testing.T) {
+	form := New(
+		NewField("name", "Name", textinput.New().SetWidth(8).SetValue("Al")),
+		NewField("email", "Email", textinput.New().SetWidth(8)),
+	)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(8, 15, 2, 4),
+		test_assertions.GetHeightAtWidthAssertions(
+			8, 4,
+			14, 4,
+			15, 2,
+			20, 2,
+		),
+		// The fields line up after the widest label
+		test_assertions.GetRenderedContentAssertion(15, 2, "Name   Al      \nEmail          "),
+		// Without room for both columns, the labels go above the fields
+		test_assertions.GetRenderedContentAssertion(10, 4, "Name      \nAl        \nEmail     \n          "),
+	)
+
+	test_assertions.CheckAll(t, assertions, form)
+}
+
+func TestValidation(t *testing.T) {
+	password := textinput.New().SetWidth(6).SetValue("abc")
+	confirmation := textinput.New().SetWidth(6).SetValue("abd")
+	form := New(
+		NewField("user", "User", textinput.New().SetWidth(6)).AddValidator(required),
+		NewField("password", "Pass", password),
+		NewField("confirmation", "Again", confirmation),
+	).AddValidator(func(values Values) map[string]error {
+		if values["password"] != values["confirmation"] {
+			return map[string]error{"confirmation": errors.New("Passwords don't match")}
+		}
+		return nil
+	})
+	form.SetFocus(true)
+
+	errs := form.Validate()
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs["user"], "Required")
+	require.EqualError(t, errs["confirmation"], "Passwords don't match")
+
+	// Errors only show once the user has moved off the field
+	require.Equal(t, 3, form.GetContentHeightForGivenWidth(20))
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, 4, getHeight(form, 20))
+
+	// Submitting shows all the errors, and moves the focus back to the first field with one
+	require.Nil(t, form.Submit())
+	require.Equal(
+		t,
+		"User           \n"+
+			"       Required\n"+
+			"Pass   abc     \n"+
+			"Again  abd     \n"+
+			// Like Text, words wider than the field stick out past it and get cut off
+			"       Password\n"+
+			"       don't   \n"+
+			"       match   ",
+		render(form, 15),
+	)
+	require.Len(t, test_assertions.GetMsgs(form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("me")})), 1)
+	confirmation.SetValue("abc")
+
+	msgs := test_assertions.GetMsgs(form.Submit())
+	require.Equal(
+		t,
+		[]tea.Msg{SubmittedMsg{Form: form, Values: Values{"user": "me", "password": "abc", "confirmation": "abc"}}},
+		msgs,
+	)
+}
+
+func TestLayoutDoesNotValidate(t *testing.T) {
+	numValidations := 0
+	name := textinput.New().SetWidth(6)
+	form := New(NewField("name", "Name", name).AddValidator(func(value string) error {
+		numValidations++
+		return required(value)
+	}))
+	form.SetFocus(true)
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	form.SetFocus(false)
+
+	numValidations = 0
+	require.Equal(t, "Name  x     ", render(form, 12))
+	require.Equal(t, 0, numValidations)
+
+	// A value set directly on the field gets validated with the next message
+	name.SetValue("")
+	require.Equal(t, 1, getHeight(form, 12))
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, "Name        \n      Requir", render(form, 12))
+}
+
+func TestTabFocus(t *testing.T) {
+	name := textinput.New().SetWidth(6)
+	subscribe := checkbox.New("Subscribe")
+	submit := button.New("Submit")
+	form := New(
+		NewField("name", "Name", name),
+		NewField("subscribe", "", subscribe),
+		NewField("submit", "", submit),
+	)
+
+	// Tab does nothing until the form has the focus
+	require.Nil(t, form.Update(tea.KeyMsg{Type: tea.KeyTab}))
+	require.False(t, form.IsFocused())
+
+	form.SetFocus(true)
+	require.True(t, name.IsFocused())
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.False(t, name.IsFocused())
+	require.True(t, subscribe.IsFocused())
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.True(t, submit.IsFocused())
+
+	// The focus wraps around at the ends
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.True(t, name.IsFocused())
+	form.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	require.True(t, submit.IsFocused())
+
+	// Disabled fields get skipped
+	subscribe.SetDisabled(true)
+	form.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	form.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	require.True(t, submit.IsFocused())
+
+	form.SetFocus(false)
+	require.False(t, form.IsFocused())
+}
+
+func TestSubmitting(t *testing.T) {
+	name := textinput.New().SetWidth(6).SetValue("Ann")
+	subscribe := checkbox.New("Subscribe").SetChecked(true)
+	submit := button.New("Submit")
+	form := New(
+		NewField("name", "Name", name),
+		NewField("subscribe", "", subscribe),
+		NewField("submit", "", submit),
+	)
+	expectedMsg := SubmittedMsg{Form: form, Values: Values{"name": "Ann", "subscribe": "true", "submit": ""}}
+
+	// Pressing Enter in a text input submits
+	form.SetFocus(true)
+	inputMsgs := test_assertions.GetMsgs(form.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.Len(t, inputMsgs, 1)
+	require.IsType(t, textinput.SubmittedMsg{}, inputMsgs[0])
+	require.Equal(t, []tea.Msg{expectedMsg}, test_assertions.GetMsgs(form.Update(inputMsgs[0])))
+
+	// Clicking the button focuses it & submits
+	require.Equal(t, "Name  Ann          \n      [x] Subscribe\n      [ Submit ]   ", render(form, 19))
+	form.Update(tea.MouseMsg{X: 8, Y: 2, Type: tea.MouseLeft})
+	require.True(t, submit.IsFocused())
+	var submittedMsgs []tea.Msg
+	for _, msg := range test_assertions.GetMsgs(form.Update(tea.MouseMsg{X: 8, Y: 2, Type: tea.MouseRelease})) {
+		if _, ok := msg.(button.PressedMsg); ok {
+			submittedMsgs = append(submittedMsgs, test_assertions.GetMsgs(form.Update(msg))...)
+		}
+	}
+	require.Equal(t, []tea.Msg{expectedMsg}, submittedMsgs)
+
+	// Messages from components outside the form are ignored
+	require.Nil(t, form.Update(button.PressedMsg{Button: button.New("Other")}))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func required(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("Required")
+	}
+	return nil
+}
+
+// Runs the layout phases in order, like a parent would
+func getHeight(form Form, width int) int {
+	form.GetContentMinMax()
+	return form.GetContentHeightForGivenWidth(width)
+}
+
+func render(form Form, width int) string {
+	return form.View(width, getHeight(form, width))
+}
@@ -0,0 +1,26 @@
+package form
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles controls how the parts of the form around the fields get styled
+type Styles struct {
+	Label lipgloss.Style
+
+	// Used for the label of the field that has the focus
+	FocusedLabel lipgloss.Style
+
+	// Used for the validation errors shown under the fields
+	Error lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Label:        lipgloss.NewStyle(),
+		FocusedLabel: lipgloss.NewStyle().Bold(true),
+		Error:        lipgloss.NewStyle().Foreground(theme.Color(theme.Danger)),
+	}
+}
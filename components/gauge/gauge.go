@@ -0,0 +1,257 @@
+package gauge
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/components/progress_bar"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// Same as the progress bar, including growing to fill the free space in a flexbox
+	defaultBarWidth = 20
+	minBarWidth     = 1
+
+	defaultValueFormat = "%.0f"
+)
+
+// Threshold colors the gauge once its value reaches the threshold's value
+type Threshold struct {
+	Value float64
+	Color lipgloss.TerminalColor
+}
+
+// Gauge shows a value within a range as a bar followed by the value, colored by the highest threshold the value has
+// reached (e.g. green, then yellow from 60, then red from 85)
+// Analogous to the <meter> tag in HTML
+type Gauge interface {
+	components.InteractiveComponent
+	components.WidthFiller
+
+	// The value gets clamped to the range
+	GetValue() float64
+	SetValue(value float64) Gauge
+
+	GetRange() (minValue float64, maxValue float64)
+	SetRange(minValue float64, maxValue float64) Gauge
+
+	GetThresholds() []Threshold
+	SetThresholds(thresholds ...Threshold) Gauge
+
+	// Shown before the bar
+	GetLabel() string
+	SetLabel(label string) Gauge
+
+	// The fmt format the value gets shown with after the bar
+	GetValueFormat() string
+	SetValueFormat(format string) Gauge
+
+	GetStyles() progress_bar.Styles
+	SetStyles(styles progress_bar.Styles) Gauge
+}
+
+// ValueMsg sets the value of the gauge, so that the value can come from a tea.Cmd
+type ValueMsg struct {
+	Gauge Gauge
+	Value float64
+}
+
+type gaugeImpl struct {
+	value float64
+
+	minValue float64
+	maxValue float64
+
+	// Sorted by value
+	thresholds []Threshold
+
+	label string
+
+	valueFormat string
+
+	// The filled style's foreground gets replaced by the color of the threshold that's been reached
+	// Nil for the default styles
+	styles *progress_bar.Styles
+}
+
+func New(minValue float64, maxValue float64) Gauge {
+	result := &gaugeImpl{
+		value:       minValue,
+		minValue:    minValue,
+		maxValue:    maxValue,
+		thresholds:  make([]Threshold, 0),
+		label:       "",
+		valueFormat: defaultValueFormat,
+		styles:      nil,
+	}
+	result.SetRange(minValue, maxValue)
+	return result
+}
+
+func (g gaugeImpl) GetValue() float64 {
+	return g.value
+}
+
+func (g *gaugeImpl) SetValue(value float64) Gauge {
+	if math.IsNaN(value) {
+		value = g.minValue
+	}
+	g.value = math.Max(g.minValue, math.Min(g.maxValue, value))
+	return g
+}
+
+func (g gaugeImpl) GetRange() (minValue float64, maxValue float64) {
+	return g.minValue, g.maxValue
+}
+
+func (g *gaugeImpl) SetRange(minValue float64, maxValue float64) Gauge {
+	if maxValue < minValue {
+		minValue, maxValue = maxValue, minValue
+	}
+	g.minValue = minValue
+	g.maxValue = maxValue
+	g.SetValue(g.value)
+	return g
+}
+
+func (g gaugeImpl) GetThresholds() []Threshold {
+	return g.thresholds
+}
+
+func (g *gaugeImpl) SetThresholds(thresholds ...Threshold) Gauge {
+	g.thresholds = append([]Threshold(nil), thresholds...)
+	sort.SliceStable(g.thresholds, func(i, j int) bool {
+		return g.thresholds[i].Value < g.thresholds[j].Value
+	})
+	return g
+}
+
+func (g gaugeImpl) GetLabel() string {
+	return g.label
+}
+
+func (g *gaugeImpl) SetLabel(label string) Gauge {
+	g.label = strings.Join(strings.Fields(label), " ")
+	return g
+}
+
+func (g gaugeImpl) GetValueFormat() string {
+	return g.valueFormat
+}
+
+func (g *gaugeImpl) SetValueFormat(format string) Gauge {
+	g.valueFormat = format
+	return g
+}
+
+func (g gaugeImpl) GetStyles() progress_bar.Styles {
+	if g.styles == nil {
+		return progress_bar.DefaultStyles()
+	}
+	return *g.styles
+}
+
+func (g *gaugeImpl) SetStyles(styles progress_bar.Styles) Gauge {
+	g.styles = &styles
+	return g
+}
+
+func (g *gaugeImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	textWidth := g.getTextWidth()
+	return textWidth + minBarWidth, textWidth + defaultBarWidth, 1, 1
+}
+
+func (g *gaugeImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (g *gaugeImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	styles := g.GetStyles()
+	valueStyle := styles.Label
+	if color, found := g.getThresholdColor(); found && color != nil {
+		styles.Filled = styles.Filled.Copy().Foreground(color)
+		valueStyle = valueStyle.Copy().Foreground(color)
+	}
+
+	progress := 0.0
+	if g.maxValue > g.minValue {
+		progress = (g.value - g.minValue) / (g.maxValue - g.minValue)
+	}
+	barWidth := utilities.GetMaxInt(0, width-g.getTextWidth())
+
+	result := ""
+	if g.label != "" {
+		result += styles.Label.Render(g.label) + " "
+	}
+	result += progress_bar.RenderBar(progress, barWidth, styles)
+	result += " " + valueStyle.Render(lipgloss.PlaceHorizontal(g.getValueWidth(), lipgloss.Right, g.formatValue(g.value)))
+	return control.FitToWidth(result, width)
+}
+
+func (g *gaugeImpl) Update(msg tea.Msg) tea.Cmd {
+	if valueMsg, ok := msg.(ValueMsg); ok && valueMsg.Gauge == Gauge(g) {
+		g.SetValue(valueMsg.Value)
+	}
+	return nil
+}
+
+// Gauges don't take the focus
+func (g *gaugeImpl) SetFocus(isFocused bool) {}
+
+func (g gaugeImpl) IsFocused() bool {
+	return false
+}
+
+// The bar looks the same at any width, so it may as well use all there is
+func (g gaugeImpl) FillsWidth() bool {
+	return true
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Gets the color of the highest threshold the value has reached, returning false if it hasn't reached any
+func (g gaugeImpl) getThresholdColor() (lipgloss.TerminalColor, bool) {
+	var result lipgloss.TerminalColor
+	found := false
+	for _, threshold := range g.thresholds {
+		if g.value < threshold.Value {
+			break
+		}
+		result = threshold.Color
+		found = true
+	}
+	return result, found
+}
+
+func (g gaugeImpl) formatValue(value float64) string {
+	return fmt.Sprintf(g.valueFormat, value)
+}
+
+// Wide enough for the value at either end of the range, so the bar doesn't change size as the value changes
+func (g gaugeImpl) getValueWidth() int {
+	return utilities.GetMaxInt(lipgloss.Width(g.formatValue(g.minValue)), lipgloss.Width(g.formatValue(g.maxValue)))
+}
+
+// Gets the width of everything but the bar
+func (g gaugeImpl) getTextWidth() int {
+	result := 1 + g.getValueWidth()
+	if g.label != "" {
+		result += lipgloss.Width(g.label) + 1
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package gauge
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var yellow = lipgloss.Color("#FFD700")
+var red = lipgloss.Color("#FF0000")
+
+func TestSizeAndRender(t *testing.T) {
+	gauge := New(0, 100).SetLabel("CPU").SetValue(50)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(9, 28, 1, 1),
+		// The value is right-aligned in room for the widest value in the range
+		test_assertions.GetRenderedContentAssertion(12, 1, "CPU ██    50"),
+	)
+	test_assertions.CheckAll(t, assertions, gauge)
+
+	gauge.SetRange(-1, 1).SetValueFormat("%.1f").SetValue(0.5)
+	test_assertions.CheckAll(t, test_assertions.GetRenderedContentAssertion(13, 1, "CPU ███   0.5"), gauge)
+}
+
+func TestThresholds(t *testing.T) {
+	gauge := New(0, 100).SetThresholds(
+		Threshold{Value: 85, Color: red},
+		Threshold{Value: 60, Color: yellow},
+	)
+
+	_, found := gauge.(*gaugeImpl).getThresholdColor()
+	require.False(t, found)
+
+	for value, expectedColor := range map[float64]lipgloss.TerminalColor{60: yellow, 84: yellow, 85: red, 100: red} {
+		gauge.SetValue(value)
+		color, found := gauge.(*gaugeImpl).getThresholdColor()
+		require.True(t, found)
+		require.Equal(t, expectedColor, color)
+	}
+}
+
+func TestValueMsg(t *testing.T) {
+	gauge := New(0, 10)
+
+	require.Nil(t, gauge.Update(ValueMsg{Gauge: New(0, 10), Value: 5}))
+	require.Equal(t, 0.0, gauge.GetValue())
+
+	// Values get clamped to the range
+	require.Nil(t, gauge.Update(ValueMsg{Gauge: gauge, Value: 50}))
+	require.Equal(t, 10.0, gauge.GetValue())
+}
+
+func TestFillsFlexbox(t *testing.T) {
+	gauge := New(0, 100).SetLabel("CPU").SetValue(50)
+	column := flexbox.NewWithContents(flexbox_item.New(gauge)).SetDirection(flexbox.Column)
+	column.GetContentMinMax()
+	require.Equal(t, 1, column.GetContentHeightForGivenWidth(40))
+	require.Equal(t, "CPU "+strings.Repeat("█", 16)+strings.Repeat(" ", 17)+" 50", column.View(40, 1))
+}
@@ -0,0 +1,71 @@
+package components
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// InteractiveComponent is a component that reacts to messages from the bubbletea runtime (keypresses, mouse events,
+// ticks, etc.)
+// Messages get routed down through the component tree by the containers, which means:
+//   - Every component receives every key message, so it should only act on keys when it's focused
+//   - Mouse messages have their coordinates translated to be relative to the component's top-left corner, so they can
+//     land outside the component (see IsMouseMsgInBounds)
+type InteractiveComponent interface {
+	Component
+
+	// Returns the command the bubbletea runtime should run as a result of the message, if any
+	Update(msg tea.Msg) tea.Cmd
+
+	// On containers, focusing gives the focus to the first descendant that accepts it and unfocusing removes the focus
+	// from all descendants
+	SetFocus(isFocused bool)
+
+	// On containers, this is true if any descendant is focused
+	IsFocused() bool
+}
+
+// UpdateChild routes the message to the child if it's interactive, translating the coordinates of mouse messages using
+// the position of the child's top-left corner within the parent
+func UpdateChild(child Component, msg tea.Msg, xOffset int, yOffset int) tea.Cmd {
+	interactiveChild, ok := child.(InteractiveComponent)
+	if !ok {
+		return nil
+	}
+	if mouseMsg, ok := msg.(tea.MouseMsg); ok {
+		mouseMsg.X -= xOffset
+		mouseMsg.Y -= yOffset
+		msg = mouseMsg
+	}
+	return interactiveChild.Update(msg)
+}
+
+// IsBroadcastMsg is whether the message is for every component (e.g. ticks), rather than for the focused one (keys) or the
+// one under the mouse, so that containers pass it to their children that aren't on the screen too, keeping them up to
+// date for when they are
+func IsBroadcastMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		return false
+	}
+	return true
+}
+
+// SetChildFocus sets the focus on the child if it's interactive, returning whether the child ended up focused
+func SetChildFocus(child Component, isFocused bool) bool {
+	interactiveChild, ok := child.(InteractiveComponent)
+	if !ok {
+		return false
+	}
+	interactiveChild.SetFocus(isFocused)
+	return interactiveChild.IsFocused()
+}
+
+// IsChildFocused returns whether the child is an interactive component that's focused
+func IsChildFocused(child Component) bool {
+	interactiveChild, ok := child.(InteractiveComponent)
+	return ok && interactiveChild.IsFocused()
+}
+
+// IsMouseMsgInBounds returns whether the mouse message (already translated to the component's coordinates) landed
+// inside a component of the given size
+func IsMouseMsgInBounds(msg tea.MouseMsg, width int, height int) bool {
+	return msg.X >= 0 && msg.X < width && msg.Y >= 0 && msg.Y < height
+}
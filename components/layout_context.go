@@ -0,0 +1,85 @@
+package components
+
+// LayoutContext carries settings down the component tree during all three layout phases, so that components can read
+// what their ancestors set (like inherited properties in CSS) rather than relying on globals
+// It's immutable; With returns a new context for the subtree, leaving the parent's alone
+type LayoutContext struct {
+	values map[interface{}]interface{}
+}
+
+// ContextualComponent is a component that uses the layout context, or passes it on to its children
+// The plain Component methods should behave like their context versions given an empty context
+type ContextualComponent interface {
+	Component
+
+	GetContentMinMaxInContext(ctx LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int)
+	GetContentHeightForGivenWidthInContext(ctx LayoutContext, width int) int
+	ViewInContext(ctx LayoutContext, width int, height int) string
+}
+
+// NewLayoutContext creates an empty context, which is what the top of the component tree starts with
+func NewLayoutContext() LayoutContext {
+	return LayoutContext{
+		values: map[interface{}]interface{}{},
+	}
+}
+
+// With returns a copy of the context with the value set for the key
+// As with context.Context, packages should use keys of their own unexported type so they can't collide
+func (ctx LayoutContext) With(key interface{}, value interface{}) LayoutContext {
+	values := make(map[interface{}]interface{}, len(ctx.values)+1)
+	for existingKey, existingValue := range ctx.values {
+		values[existingKey] = existingValue
+	}
+	values[key] = value
+	return LayoutContext{
+		values: values,
+	}
+}
+
+// Merge returns a copy of the context with all of the other context's values set, overriding the values already there
+func (ctx LayoutContext) Merge(other LayoutContext) LayoutContext {
+	if len(other.values) == 0 {
+		return ctx
+	}
+	values := make(map[interface{}]interface{}, len(ctx.values)+len(other.values))
+	for key, value := range ctx.values {
+		values[key] = value
+	}
+	for key, value := range other.values {
+		values[key] = value
+	}
+	return LayoutContext{
+		values: values,
+	}
+}
+
+// Get returns the value for the key set by the nearest ancestor, if any ancestor set it
+func (ctx LayoutContext) Get(key interface{}) (interface{}, bool) {
+	value, found := ctx.values[key]
+	return value, found
+}
+
+// GetChildContentMinMax gets the child's min & max sizes, passing it the context if it uses it
+func GetChildContentMinMax(child Component, ctx LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	if contextualChild, ok := child.(ContextualComponent); ok {
+		return contextualChild.GetContentMinMaxInContext(ctx)
+	}
+	return child.GetContentMinMax()
+}
+
+// GetChildContentHeightForGivenWidth gets the child's height at the width, passing it the context if it uses it
+func GetChildContentHeightForGivenWidth(child Component, ctx LayoutContext, width int) int {
+	if contextualChild, ok := child.(ContextualComponent); ok {
+		return contextualChild.GetContentHeightForGivenWidthInContext(ctx, width)
+	}
+	return child.GetContentHeightForGivenWidth(width)
+}
+
+// ViewChild renders the child, passing it the context if it uses it
+func ViewChild(child Component, ctx LayoutContext, width int, height int) string {
+	if contextualChild, ok := child.(ContextualComponent); ok {
+		return contextualChild.ViewInContext(ctx, width, height)
+	}
+	return child.View(width, height)
+}
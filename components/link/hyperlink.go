@@ -0,0 +1,167 @@
+package link
+
+import (
+	"strings"
+)
+
+// Hyperlinks can't be put into the text as OSC 8 escape sequences directly, because the layout math (lipgloss &
+// reflow) would count the URL towards the width of the text and could split the sequence when wrapping. Instead,
+// links are marked using zero-width characters (which the layout math ignores), and then the markers get turned into
+// the real escape sequences once the final output has been laid out.
+//
+// The markers look like:
+//
+//	<delimiter><URL encoded as digits><delimiter>visible text<delimiter><delimiter>
+//
+// where each byte of the URL is encoded as base-3 digits using zero-width characters.
+
+const (
+	markerDelimiter = '\uFEFF'
+
+	// Zero-width space, non-joiner, and joiner, respectively
+	markerDigitZero = '\u200B'
+	markerDigitOne  = '\u200C'
+	markerDigitTwo  = '\u200D'
+
+	// 3^6 = 729, which is enough to hold a byte
+	digitsPerByte = 6
+
+	osc8Prefix     = "\x1b]8;;"
+	osc8Terminator = "\x1b\\"
+)
+
+var markerDigits = []rune{markerDigitZero, markerDigitOne, markerDigitTwo}
+
+// Span marks the visible text as linking to the URL, returning a string that can be used inside any other text (e.g.
+// passed to text.New)
+// Each word is marked separately, so the link survives being word-wrapped across lines
+func Span(url string, visibleText string) string {
+	if url == "" {
+		return visibleText
+	}
+
+	openMarker := encodeOpenMarker(url)
+	closeMarker := string([]rune{markerDelimiter, markerDelimiter})
+
+	words := strings.Split(visibleText, " ")
+	for idx, word := range words {
+		if word == "" {
+			continue
+		}
+		words[idx] = openMarker + word + closeMarker
+	}
+	return strings.Join(words, " ")
+}
+
+// Resolve replaces the link markers in fully laid-out output with OSC 8 escape sequences, or removes them (leaving
+// just the plain visible text) if hyperlinks aren't enabled
+// Links that are still open at the end of a line get closed, so they never bleed into neighboring content
+func Resolve(str string, isEnabled bool) string {
+	if !strings.ContainsRune(str, markerDelimiter) {
+		return str
+	}
+
+	var builder strings.Builder
+	runes := []rune(str)
+	isLinkOpen := false
+	for idx := 0; idx < len(runes); idx++ {
+		r := runes[idx]
+		switch {
+		case r == '\n' && isLinkOpen:
+			builder.WriteString(getCloseSequence(isEnabled))
+			isLinkOpen = false
+			builder.WriteRune(r)
+		case r != markerDelimiter:
+			builder.WriteRune(r)
+		case idx+1 < len(runes) && runes[idx+1] == markerDelimiter:
+			// Close marker
+			if isLinkOpen {
+				builder.WriteString(getCloseSequence(isEnabled))
+				isLinkOpen = false
+			}
+			idx++
+		default:
+			url, endIdx, ok := decodeOpenMarker(runes, idx)
+			if !ok {
+				// Not a marker we made, so leave it be
+				builder.WriteRune(r)
+				continue
+			}
+			if isLinkOpen {
+				builder.WriteString(getCloseSequence(isEnabled))
+			}
+			if isEnabled {
+				builder.WriteString(osc8Prefix + url + osc8Terminator)
+			}
+			isLinkOpen = true
+			idx = endIdx
+		}
+	}
+	if isLinkOpen {
+		builder.WriteString(getCloseSequence(isEnabled))
+	}
+	return builder.String()
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func encodeOpenMarker(url string) string {
+	var builder strings.Builder
+	builder.WriteRune(markerDelimiter)
+	for _, b := range []byte(url) {
+		digits := make([]rune, digitsPerByte)
+		value := int(b)
+		for idx := digitsPerByte - 1; idx >= 0; idx-- {
+			digits[idx] = markerDigits[value%len(markerDigits)]
+			value /= len(markerDigits)
+		}
+		builder.WriteString(string(digits))
+	}
+	builder.WriteRune(markerDelimiter)
+	return builder.String()
+}
+
+// Decodes the open marker starting at the given index, returning the URL and the index of the marker's last rune
+func decodeOpenMarker(runes []rune, startIdx int) (url string, endIdx int, ok bool) {
+	urlBytes := make([]byte, 0)
+	value, numDigits := 0, 0
+	for idx := startIdx + 1; idx < len(runes); idx++ {
+		r := runes[idx]
+		if r == markerDelimiter {
+			if numDigits != 0 || len(urlBytes) == 0 {
+				return "", 0, false
+			}
+			return string(urlBytes), idx, true
+		}
+
+		digit, isDigit := getMarkerDigitValue(r)
+		if !isDigit {
+			return "", 0, false
+		}
+		value = value*len(markerDigits) + digit
+		numDigits++
+		if numDigits == digitsPerByte {
+			urlBytes = append(urlBytes, byte(value))
+			value, numDigits = 0, 0
+		}
+	}
+	return "", 0, false
+}
+
+func getMarkerDigitValue(r rune) (int, bool) {
+	for value, digit := range markerDigits {
+		if r == digit {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+func getCloseSequence(isEnabled bool) string {
+	if !isEnabled {
+		return ""
+	}
+	return osc8Prefix + osc8Terminator
+}
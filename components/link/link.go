@@ -0,0 +1,117 @@
+package link
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"strings"
+)
+
+// Link is text that links to a URL, which is clickable in terminals that support OSC 8 hyperlinks
+// Analogous to the <a> tag in HTML
+// NOTE: the output contains markers that need to be turned into hyperlinks with Resolve (which bubblebath does
+// automatically)
+type Link interface {
+	components.Component
+
+	GetURL() string
+	SetURL(url string) Link
+
+	GetText() string
+	SetText(str string) Link
+
+	GetStyle() lipgloss.Style
+	SetStyle(style lipgloss.Style) Link
+
+	GetTextAlignment() text.TextAlignment
+	SetTextAlignment(alignment text.TextAlignment) Link
+}
+
+type linkImpl struct {
+	url string
+
+	visibleText string
+
+	style lipgloss.Style
+
+	// The link is laid out exactly like any other text, so we delegate to a text component
+	inner text.Text
+}
+
+func New(url string, visibleText string) Link {
+	result := &linkImpl{
+		url:         url,
+		visibleText: visibleText,
+		style:       lipgloss.NewStyle().Underline(true),
+		inner:       text.New(""),
+	}
+	result.updateInnerContents()
+	return result
+}
+
+func (l linkImpl) GetURL() string {
+	return l.url
+}
+
+func (l *linkImpl) SetURL(url string) Link {
+	l.url = url
+	l.updateInnerContents()
+	return l
+}
+
+func (l linkImpl) GetText() string {
+	return l.visibleText
+}
+
+func (l *linkImpl) SetText(str string) Link {
+	l.visibleText = str
+	l.updateInnerContents()
+	return l
+}
+
+func (l linkImpl) GetStyle() lipgloss.Style {
+	return l.style
+}
+
+func (l *linkImpl) SetStyle(style lipgloss.Style) Link {
+	l.style = style
+	l.updateInnerContents()
+	return l
+}
+
+func (l linkImpl) GetTextAlignment() text.TextAlignment {
+	return l.inner.GetTextAlignment()
+}
+
+func (l *linkImpl) SetTextAlignment(alignment text.TextAlignment) Link {
+	l.inner.SetTextAlignment(alignment)
+	return l
+}
+
+func (l *linkImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return l.inner.GetContentMinMax()
+}
+
+func (l *linkImpl) GetContentHeightForGivenWidth(width int) int {
+	return l.inner.GetContentHeightForGivenWidth(width)
+}
+
+func (l *linkImpl) View(width int, height int) string {
+	return l.inner.View(width, height)
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (l *linkImpl) updateInnerContents() {
+	// Styling each word separately keeps the styling from being split by word wrapping
+	words := strings.Split(l.visibleText, " ")
+	for idx, word := range words {
+		if word == "" {
+			continue
+		}
+		words[idx] = l.style.Render(word)
+	}
+	l.inner.SetContents(Span(l.url, strings.Join(words, " ")))
+}
@@ -0,0 +1,55 @@
+package link
+
+import (
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const url = "https://example.com/a?b=c"
+
+func TestLinkIsMeasuredByVisibleText(t *testing.T) {
+	component := New(url, "click here please")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(6, 17, 1, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			6, 3,
+			10, 2,
+			17, 1,
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestResolveDisabledGivesPlainText(t *testing.T) {
+	component := New(url, "click here please")
+	require.Equal(t, "click here\nplease    ", Resolve(component.View(10, 2), false))
+}
+
+func TestResolveEnabledWrapsEachLine(t *testing.T) {
+	component := New(url, "click here please")
+	resolved := Resolve(component.View(10, 2), true)
+
+	openSequence := osc8Prefix + url + osc8Terminator
+	closeSequence := osc8Prefix + osc8Terminator
+	lines := strings.Split(resolved, "\n")
+	require.Equal(t, openSequence+"click"+closeSequence+" "+openSequence+"here"+closeSequence, lines[0])
+	require.Equal(t, openSequence+"please"+closeSequence+"    ", lines[1])
+}
+
+func TestResolveClosesTruncatedLinks(t *testing.T) {
+	// Simulates a parent truncating the line partway through the link
+	truncated := []rune(Span(url, "truncated"))
+	truncated = truncated[:len(truncated)-4]
+
+	resolved := Resolve(string(truncated)+"\nnext", true)
+	require.Equal(t, osc8Prefix+url+osc8Terminator+"truncat"+osc8Prefix+osc8Terminator+"\nnext", resolved)
+}
+
+func TestSpanWithoutUrlIsPlain(t *testing.T) {
+	require.Equal(t, "plain text", Span("", "plain text"))
+}
@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+// A line across the full width it's given
+// Analogous to the <hr> tag in HTML
+type horizontalRuleImpl struct {
+	style lipgloss.Style
+}
+
+func newHorizontalRule(style lipgloss.Style) *horizontalRuleImpl {
+	return &horizontalRuleImpl{
+		style: style,
+	}
+}
+
+func (rule horizontalRuleImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	// The rule doesn't have any content of its own, so it'll only be wider than this if the parent lets it grow
+	return 1, 1, 1, 1
+}
+
+func (rule horizontalRuleImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (rule horizontalRuleImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	return rule.style.Render(strings.Repeat("─", width))
+}
@@ -0,0 +1,89 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/link"
+	"github.com/yuin/goldmark/ast"
+	extension_ast "github.com/yuin/goldmark/extension/ast"
+	"strings"
+)
+
+// Renders all the inline children of the node to a string, with the given style applied
+func (b *treeBuilder) renderInlines(node ast.Node, style lipgloss.Style) string {
+	var builder strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		builder.WriteString(b.renderInline(child, style))
+	}
+	return builder.String()
+}
+
+func (b *treeBuilder) renderInline(node ast.Node, style lipgloss.Style) string {
+	switch node := node.(type) {
+	case *ast.Text:
+		result := styleWords(style, string(node.Segment.Value(b.source)))
+		if node.HardLineBreak() {
+			result += "\n"
+		} else if node.SoftLineBreak() {
+			result += " "
+		}
+		return result
+	case *ast.String:
+		return styleWords(style, string(node.Value))
+	case *ast.Emphasis:
+		emphasisStyle := b.theme.Emphasis
+		if node.Level >= 2 {
+			emphasisStyle = b.theme.Strong
+		}
+		return b.renderInlines(node, layerStyle(emphasisStyle, style))
+	case *extension_ast.Strikethrough:
+		return b.renderInlines(node, layerStyle(b.theme.Strikethrough, style))
+	case *ast.CodeSpan:
+		return b.renderInlines(node, layerStyle(b.theme.InlineCode, style))
+	case *ast.Link:
+		url := string(node.Destination)
+		linkText := link.Span(url, b.renderInlines(node, layerStyle(b.theme.Link, style)))
+		if url == "" || url == string(node.Text(b.source)) {
+			return linkText
+		}
+		// The URL is still shown, for terminals that don't support hyperlinks
+		return linkText + " " + styleWords(layerStyle(b.theme.LinkURL, style), "("+url+")")
+	case *ast.AutoLink:
+		url := string(node.URL(b.source))
+		return link.Span(url, styleWords(layerStyle(b.theme.Link, style), url))
+	case *ast.Image:
+		altText := string(node.Text(b.source))
+		return styleWords(layerStyle(b.theme.Image, style), "[image: "+altText+"]")
+	case *extension_ast.TaskCheckBox:
+		if node.IsChecked {
+			return styleWords(style, "[x] ")
+		}
+		return styleWords(style, "[ ] ")
+	case *ast.RawHTML:
+		var builder strings.Builder
+		for idx := 0; idx < node.Segments.Len(); idx++ {
+			segment := node.Segments.At(idx)
+			builder.Write(segment.Value(b.source))
+		}
+		return styleWords(style, builder.String())
+	default:
+		return b.renderInlines(node, style)
+	}
+}
+
+// Layers the inner style on top of the outer one, with the inner style taking precedence
+func layerStyle(inner lipgloss.Style, outer lipgloss.Style) lipgloss.Style {
+	return inner.Copy().Inherit(outer)
+}
+
+// Styles each word individually, leaving the spaces between them unstyled
+// This way, the styling never gets split across the line breaks that word wrapping inserts
+func styleWords(style lipgloss.Style, str string) string {
+	words := strings.Split(str, " ")
+	for idx, word := range words {
+		if word == "" {
+			continue
+		}
+		words[idx] = style.Render(word)
+	}
+	return strings.Join(words, " ")
+}
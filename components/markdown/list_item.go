@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/utilities"
+)
+
+// A list item, which is a marker (e.g. a bullet or a number) in a fixed-width gutter with the item's body beside it
+// The gutter never shrinks, so that the markers stay intact and the bodies of all the items line up
+type listItemImpl struct {
+	marker string
+
+	markerWidth int
+
+	body components.Component
+}
+
+func newListItem(marker string, markerWidth int, body components.Component) *listItemImpl {
+	return &listItemImpl{
+		marker:      marker,
+		markerWidth: markerWidth,
+		body:        body,
+	}
+}
+
+func (item *listItemImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	bodyMinWidth, bodyMaxWidth, bodyMinHeight, bodyMaxHeight := item.body.GetContentMinMax()
+
+	minWidth = bodyMinWidth + item.markerWidth
+	maxWidth = bodyMaxWidth + item.markerWidth
+
+	// The marker always needs a line
+	minHeight = utilities.GetMaxInt(1, bodyMinHeight)
+	maxHeight = utilities.GetMaxInt(1, bodyMaxHeight)
+	return
+}
+
+func (item *listItemImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+
+	bodyWidth := utilities.GetMaxInt(0, width-item.markerWidth)
+	return utilities.GetMaxInt(1, item.body.GetContentHeightForGivenWidth(bodyWidth))
+}
+
+func (item *listItemImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	bodyWidth := utilities.GetMaxInt(0, width-item.markerWidth)
+	bodyStr := item.body.View(bodyWidth, height)
+
+	gutter := lipgloss.NewStyle().Width(item.markerWidth).Render(item.marker)
+	joined := lipgloss.JoinHorizontal(lipgloss.Top, gutter, bodyStr)
+
+	return lipgloss.NewStyle().
+		MaxWidth(width).
+		MaxHeight(height).
+		Render(joined)
+}
@@ -0,0 +1,117 @@
+package markdown
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/theme"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Markdown renders a CommonMark document (plus GitHub-flavored tables, strikethrough, and task lists) as a tree of
+// this project's components, so that it reflows with the normal layout phases
+// The tree gets built with the theme's styles, so with the default theme it gets rebuilt on theme.ChangedMsg
+type Markdown interface {
+	components.InteractiveComponent
+
+	GetSource() string
+	SetSource(source string) Markdown
+
+	GetTheme() Theme
+	SetTheme(theme Theme) Markdown
+}
+
+type markdownImpl struct {
+	source string
+
+	// Nil for the default theme
+	theme *Theme
+
+	// The component tree built from the source, which all the layout phases get delegated to
+	// Rebuilt whenever the source or the theme changes
+	root components.Component
+
+	// The width that the root last calculated its height for, which is the width its layout caches are valid for
+	// (nil if the height hasn't been calculated since the tree was last built)
+	lastHeightCalculationWidth *int
+}
+
+func New(source string) Markdown {
+	result := &markdownImpl{
+		source:                     source,
+		theme:                      nil,
+		root:                       nil,
+		lastHeightCalculationWidth: nil,
+	}
+	result.rebuild()
+	return result
+}
+
+func (m markdownImpl) GetSource() string {
+	return m.source
+}
+
+func (m *markdownImpl) SetSource(source string) Markdown {
+	m.source = source
+	m.rebuild()
+	return m
+}
+
+func (m markdownImpl) GetTheme() Theme {
+	if m.theme == nil {
+		return DefaultTheme()
+	}
+	return *m.theme
+}
+
+func (m *markdownImpl) SetTheme(theme Theme) Markdown {
+	m.theme = &theme
+	m.rebuild()
+	return m
+}
+
+func (m *markdownImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return m.root.GetContentMinMax()
+}
+
+func (m *markdownImpl) GetContentHeightForGivenWidth(width int) int {
+	m.lastHeightCalculationWidth = &width
+	return m.root.GetContentHeightForGivenWidth(width)
+}
+
+func (m *markdownImpl) View(width int, height int) string {
+	// The tree is full of flexboxes, which cache their layout in the height calculation phase
+	if m.lastHeightCalculationWidth == nil || *m.lastHeightCalculationWidth != width {
+		m.GetContentHeightForGivenWidth(width)
+	}
+	return m.root.View(width, height)
+}
+
+func (m *markdownImpl) Update(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(theme.ChangedMsg); ok && m.theme == nil {
+		m.rebuild()
+	}
+	return nil
+}
+
+// Markdown doesn't take the focus
+func (m *markdownImpl) SetFocus(isFocused bool) {}
+
+func (m markdownImpl) IsFocused() bool {
+	return false
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (m *markdownImpl) rebuild() {
+	sourceBytes := []byte(m.source)
+	parser := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+	document := parser.Parse(text.NewReader(sourceBytes))
+
+	builder := newTreeBuilder(sourceBytes, m.GetTheme())
+	m.root = builder.buildBlockContainer(document, false)
+	m.lastHeightCalculationWidth = nil
+}
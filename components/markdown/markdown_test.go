@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/theme"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// NOTE: lipgloss doesn't output colors when there's no terminal, so the theme styling doesn't show up in these tests
+
+func TestHeadingAndParagraph(t *testing.T) {
+	component := New("# Title\n\nSome text here")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(5, 14, 3, 5),
+		test_assertions.GetHeightAtWidthAssertions(
+			8, 5,
+			20, 3,
+		),
+		test_assertions.GetRenderedContentAssertion(8, 5, "Title   \n        \nSome    \ntext    \nhere    "),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestLists(t *testing.T) {
+	component := New("- one\n- two\n  - nested\n\n3. a\n4. b")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(10, 10, 6, 6),
+		test_assertions.GetRenderedContentAssertion(
+			20,
+			6,
+			"• one               \n• two               \n  • nested          \n                    \n3. a                \n4. b                ",
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestTable(t *testing.T) {
+	component := New("| Name | Value |\n|:-----|------:|\n| a | 1 |\n| longer name | 22 |")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(14, 19, 4, 5),
+		test_assertions.GetHeightAtWidthAssertions(
+			14, 5,
+			19, 4,
+		),
+		test_assertions.GetRenderedContentAssertion(
+			20,
+			4,
+			"Name        │ Value \n────────────┼────── \na           │     1 \nlonger name │    22 ",
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestCodeBlockIsNotWrapped(t *testing.T) {
+	component := New("```\nline one\n  indented\n```")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(12, 12, 2, 2),
+		test_assertions.GetHeightAtWidthAssertions(
+			8, 2,
+			20, 2,
+		),
+		test_assertions.GetRenderedContentAssertion(8, 2, " line o \n   inde "),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestBlockQuoteAndRule(t *testing.T) {
+	component := New("> quoted\n\n---")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(8, 8, 3, 3),
+		// The rule should stretch to whatever width it's given
+		test_assertions.GetRenderedContentAssertion(12, 3, "┃ quoted    \n            \n────────────"),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestSetSourceRebuilds(t *testing.T) {
+	component := New("first")
+	component.SetSource("second source")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(6, 13, 1, 2),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestThemeChangeRebuilds(t *testing.T) {
+	defer theme.SetActive(theme.GetActive())
+	component := New("[link](https://example.com)")
+
+	theme.SetActive(theme.Dark())
+	require.Equal(t, theme.Dark().Color(theme.Primary), component.GetTheme().Link.GetForeground())
+	root := component.(*markdownImpl).root
+	component.Update(theme.ChangedMsg{Theme: theme.Dark()})
+	require.NotSame(t, root, component.(*markdownImpl).root)
+
+	// A theme that was set doesn't change, so the tree doesn't need rebuilding
+	component.SetTheme(DefaultTheme())
+	root = component.(*markdownImpl).root
+	component.Update(theme.ChangedMsg{Theme: theme.Light()})
+	require.Same(t, root, component.(*markdownImpl).root)
+}
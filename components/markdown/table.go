@@ -0,0 +1,205 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+const (
+	columnSeparator        = " │ "
+	headerSeparatorFill    = "─"
+	headerSeparatorJoiner  = "─┼─"
+	numHeaderSeparatorRows = 1
+)
+
+// A table whose columns all line up, with a separator line under the header
+// Each column gets at least its min content width, and any extra space is handed out in proportion to how much
+// more each column would like (so columns that wrap the most get the most of the extra space)
+type tableImpl struct {
+	header []text.Text
+
+	rows [][]text.Text
+
+	separatorStyle lipgloss.Style
+}
+
+func newTable(header []text.Text, rows [][]text.Text, separatorStyle lipgloss.Style) *tableImpl {
+	return &tableImpl{
+		header:         header,
+		rows:           rows,
+		separatorStyle: separatorStyle,
+	}
+}
+
+func (table *tableImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	columnMinWidths, columnMaxWidths := table.getColumnMinMaxWidths()
+	separatorsWidth := table.getSeparatorsWidth()
+
+	minWidth, maxWidth = separatorsWidth, separatorsWidth
+	for idx := range columnMinWidths {
+		minWidth += columnMinWidths[idx]
+		maxWidth += columnMaxWidths[idx]
+	}
+
+	minHeight = numHeaderSeparatorRows
+	maxHeight = numHeaderSeparatorRows
+	for _, row := range table.getAllRows() {
+		rowMinHeight := 0
+		for _, cell := range row {
+			_, _, cellMinHeight, _ := cell.GetContentMinMax()
+			rowMinHeight = utilities.GetMaxInt(rowMinHeight, cellMinHeight)
+		}
+		minHeight += rowMinHeight
+		maxHeight += table.getRowHeight(row, columnMinWidths)
+	}
+	return
+}
+
+func (table *tableImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+
+	columnWidths := table.getColumnWidths(width)
+	result := numHeaderSeparatorRows
+	for _, row := range table.getAllRows() {
+		result += table.getRowHeight(row, columnWidths)
+	}
+	return result
+}
+
+func (table *tableImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	columnWidths := table.getColumnWidths(width)
+
+	renderedRows := make([]string, 0, len(table.rows)+2)
+	renderedRows = append(renderedRows, table.renderRow(table.header, columnWidths))
+
+	separatorFills := make([]string, len(columnWidths))
+	for idx, columnWidth := range columnWidths {
+		separatorFills[idx] = strings.Repeat(headerSeparatorFill, columnWidth)
+	}
+	renderedRows = append(renderedRows, table.separatorStyle.Render(strings.Join(separatorFills, headerSeparatorJoiner)))
+
+	for _, row := range table.rows {
+		renderedRows = append(renderedRows, table.renderRow(row, columnWidths))
+	}
+
+	return lipgloss.NewStyle().
+		MaxWidth(width).
+		MaxHeight(height).
+		Render(strings.Join(renderedRows, "\n"))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (table *tableImpl) getAllRows() [][]text.Text {
+	return append([][]text.Text{table.header}, table.rows...)
+}
+
+func (table *tableImpl) getNumColumns() int {
+	return len(table.header)
+}
+
+func (table *tableImpl) getSeparatorsWidth() int {
+	return utilities.GetMaxInt(0, table.getNumColumns()-1) * lipgloss.Width(columnSeparator)
+}
+
+func (table *tableImpl) getColumnMinMaxWidths() (columnMinWidths []int, columnMaxWidths []int) {
+	columnMinWidths = make([]int, table.getNumColumns())
+	columnMaxWidths = make([]int, table.getNumColumns())
+	for _, row := range table.getAllRows() {
+		for idx, cell := range row {
+			if idx >= table.getNumColumns() {
+				break
+			}
+			cellMinWidth, cellMaxWidth, _, _ := cell.GetContentMinMax()
+			columnMinWidths[idx] = utilities.GetMaxInt(columnMinWidths[idx], cellMinWidth)
+			columnMaxWidths[idx] = utilities.GetMaxInt(columnMaxWidths[idx], cellMaxWidth)
+		}
+	}
+	return
+}
+
+// Gets the width of each column when the table has the given width
+func (table *tableImpl) getColumnWidths(width int) []int {
+	columnMinWidths, columnMaxWidths := table.getColumnMinMaxWidths()
+
+	totalMinWidth, totalMaxWidth := 0, 0
+	for idx := range columnMinWidths {
+		totalMinWidth += columnMinWidths[idx]
+		totalMaxWidth += columnMaxWidths[idx]
+	}
+
+	widthForColumns := width - table.getSeparatorsWidth()
+	if widthForColumns >= totalMaxWidth {
+		return columnMaxWidths
+	}
+	if widthForColumns <= totalMinWidth {
+		// We'll get truncated
+		return columnMinWidths
+	}
+
+	extraSpace := widthForColumns - totalMinWidth
+	totalDesiredExtraSpace := totalMaxWidth - totalMinWidth
+	result := make([]int, len(columnMinWidths))
+	spaceAllocated := 0
+	for idx := range columnMinWidths {
+		result[idx] = columnMinWidths[idx]
+		if idx == len(columnMinWidths)-1 {
+			// Dump any rounding remainder on the last column
+			result[idx] += extraSpace - spaceAllocated
+			break
+		}
+
+		share := extraSpace * (columnMaxWidths[idx] - columnMinWidths[idx]) / totalDesiredExtraSpace
+		result[idx] += share
+		spaceAllocated += share
+	}
+	return result
+}
+
+func (table *tableImpl) getRowHeight(row []text.Text, columnWidths []int) int {
+	result := 1
+	for idx, cell := range row {
+		if idx >= len(columnWidths) {
+			break
+		}
+		result = utilities.GetMaxInt(result, cell.GetContentHeightForGivenWidth(columnWidths[idx]))
+	}
+	return result
+}
+
+func (table *tableImpl) renderRow(row []text.Text, columnWidths []int) string {
+	rowHeight := table.getRowHeight(row, columnWidths)
+
+	fragments := make([]string, 0, 2*len(columnWidths))
+	separator := table.separatorStyle.Render(strings.TrimSuffix(strings.Repeat(columnSeparator+"\n", rowHeight), "\n"))
+	for idx, columnWidth := range columnWidths {
+		if idx > 0 {
+			fragments = append(fragments, separator)
+		}
+
+		cellStr := ""
+		if idx < len(row) {
+			cellStr = row[idx].View(columnWidth, rowHeight)
+		}
+
+		// Expand the cell to a full block, so the separators line up
+		// (the zero width case has to be handled specially, as lipgloss treats a width of 0 as unset)
+		if columnWidth == 0 {
+			cellStr = strings.Repeat("\n", rowHeight-1)
+		} else {
+			cellStr = lipgloss.NewStyle().Width(columnWidth).Height(rowHeight).Render(cellStr)
+		}
+		fragments = append(fragments, cellStr)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, fragments...)
+}
@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/code_block"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Theme controls how each kind of Markdown element gets styled
+// The inline styles (e.g. Emphasis, Link) get layered on top of the style of the block they're in, so e.g. a link
+// inside a heading gets both the heading's and the link's styling
+type Theme struct {
+	// Indexed by heading level, so Headings[0] is for "#" and Headings[5] is for "######"
+	Headings [6]lipgloss.Style
+
+	Emphasis      lipgloss.Style
+	Strong        lipgloss.Style
+	Strikethrough lipgloss.Style
+	InlineCode    lipgloss.Style
+
+	// Used for the link text, and for the URL that gets shown next to it
+	Link    lipgloss.Style
+	LinkURL lipgloss.Style
+
+	// Used for the alt text of images, as images can't be displayed
+	Image lipgloss.Style
+
+	// These get put on a Stylebox around the block, so padding & borders are allowed
+	CodeBlock  lipgloss.Style
+	BlockQuote lipgloss.Style
+
+	// Used to syntax-highlight code blocks whose language is recognized
+	CodeHighlighting code_block.Theme
+
+	// The string used as the marker for unordered list items
+	Bullet     string
+	ListMarker lipgloss.Style
+
+	TableHeader    lipgloss.Style
+	TableSeparator lipgloss.Style
+
+	HorizontalRule lipgloss.Style
+}
+
+// DefaultTheme is built from the active theme's colors
+func DefaultTheme() Theme {
+	mutedColor := theme.Color(theme.Muted)
+	accentColor := theme.Color(theme.Primary)
+	codeBackgroundColor := theme.Color(theme.Selection)
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+
+	return Theme{
+		Headings: [6]lipgloss.Style{
+			headingStyle.Copy().Underline(true),
+			headingStyle.Copy(),
+			headingStyle.Copy(),
+			headingStyle.Copy().Foreground(lipgloss.NoColor{}),
+			headingStyle.Copy().Foreground(lipgloss.NoColor{}),
+			headingStyle.Copy().Foreground(mutedColor),
+		},
+		Emphasis:         lipgloss.NewStyle().Italic(true),
+		Strong:           lipgloss.NewStyle().Bold(true),
+		Strikethrough:    lipgloss.NewStyle().Strikethrough(true),
+		InlineCode:       lipgloss.NewStyle().Foreground(theme.Color(theme.Secondary)).Background(codeBackgroundColor),
+		Link:             lipgloss.NewStyle().Foreground(accentColor).Underline(true),
+		LinkURL:          lipgloss.NewStyle().Foreground(mutedColor),
+		Image:            lipgloss.NewStyle().Foreground(mutedColor).Italic(true),
+		CodeBlock:        lipgloss.NewStyle().Background(codeBackgroundColor).Padding(0, 1),
+		CodeHighlighting: code_block.DefaultTheme(),
+		BlockQuote: lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Border(lipgloss.ThickBorder(), false, false, false, true).
+			BorderForeground(mutedColor).
+			PaddingLeft(1),
+		Bullet:         "•",
+		ListMarker:     lipgloss.NewStyle().Foreground(accentColor),
+		TableHeader:    lipgloss.NewStyle().Bold(true),
+		TableSeparator: lipgloss.NewStyle().Foreground(mutedColor),
+		HorizontalRule: lipgloss.NewStyle().Foreground(mutedColor),
+	}
+}
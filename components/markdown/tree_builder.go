@@ -0,0 +1,174 @@
+package markdown
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/code_block"
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/stylebox"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/yuin/goldmark/ast"
+	extension_ast "github.com/yuin/goldmark/extension/ast"
+	"strings"
+)
+
+// The number of blank lines between blocks in a loose container (analogous to the margin on a <p> tag)
+const blockSpacing = 1
+
+// Walks the goldmark AST, turning it into components
+type treeBuilder struct {
+	source []byte
+
+	theme Theme
+}
+
+func newTreeBuilder(source []byte, theme Theme) *treeBuilder {
+	return &treeBuilder{
+		source: source,
+		theme:  theme,
+	}
+}
+
+// Builds a column of all the block children of the node
+// Tight containers (e.g. the items of a tight list) don't get any spacing between their blocks
+func (b *treeBuilder) buildBlockContainer(node ast.Node, isTight bool) components.Component {
+	items := make([]flexbox_item.FlexboxItem, 0, node.ChildCount())
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		component := b.buildBlock(child)
+		if len(items) > 0 && !isTight {
+			component = stylebox.New(component).SetStyle(lipgloss.NewStyle().PaddingTop(blockSpacing))
+		}
+
+		// Blocks take the full width, so that things like code block backgrounds span the document
+		items = append(items, flexbox_item.New(component).SetMaxWidth(flexbox_item.MaxAvailable))
+	}
+
+	return flexbox.NewWithContents(items...).SetDirection(flexbox.Column)
+}
+
+func (b *treeBuilder) buildBlock(node ast.Node) components.Component {
+	switch node := node.(type) {
+	case *ast.Heading:
+		headingStyle := b.theme.Headings[node.Level-1]
+		return text.New(b.renderInlines(node, headingStyle))
+	case *ast.Paragraph, *ast.TextBlock:
+		return text.New(b.renderInlines(node, lipgloss.NewStyle()))
+	case *ast.FencedCodeBlock:
+		language := string(node.Language(b.source))
+		if tokenizer, found := code_block.GetTokenizerForLanguage(language); found {
+			code := code_block.New(b.getLinesText(node)).
+				SetTokenizer(tokenizer).
+				SetTheme(b.theme.CodeHighlighting).
+				SetWrapMode(code_block.Clip)
+			return stylebox.New(code).SetStyle(b.theme.CodeBlock)
+		}
+		code := text.New(b.getLinesText(node)).SetWhitespaceMode(text.WhitespacePre)
+		return stylebox.New(code).SetStyle(b.theme.CodeBlock)
+	case *ast.CodeBlock:
+		code := text.New(b.getLinesText(node)).SetWhitespaceMode(text.WhitespacePre)
+		return stylebox.New(code).SetStyle(b.theme.CodeBlock)
+	case *ast.HTMLBlock:
+		return text.New(b.getLinesText(node)).SetWhitespaceMode(text.WhitespacePre)
+	case *ast.List:
+		return b.buildList(node)
+	case *ast.Blockquote:
+		return stylebox.New(b.buildBlockContainer(node, false)).SetStyle(b.theme.BlockQuote)
+	case *ast.ThematicBreak:
+		return newHorizontalRule(b.theme.HorizontalRule)
+	case *extension_ast.Table:
+		return b.buildTable(node)
+	default:
+		// We don't know how to handle the block specially, so fall back to just displaying its text
+		return text.New(string(node.Text(b.source)))
+	}
+}
+
+func (b *treeBuilder) buildList(list *ast.List) components.Component {
+	lastNumber := list.Start + list.ChildCount() - 1
+	markerWidth := 0
+	if list.IsOrdered() {
+		markerWidth = lipgloss.Width(fmt.Sprintf("%d%c ", lastNumber, list.Marker))
+	} else {
+		markerWidth = lipgloss.Width(b.theme.Bullet + " ")
+	}
+
+	items := make([]flexbox_item.FlexboxItem, 0, list.ChildCount())
+	number := list.Start
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		var marker string
+		if list.IsOrdered() {
+			marker = fmt.Sprintf("%d%c", number, list.Marker)
+			// Right-align the numbers, so the periods line up
+			marker = strings.Repeat(" ", markerWidth-1-lipgloss.Width(marker)) + marker
+		} else {
+			marker = b.theme.Bullet
+		}
+		number++
+
+		var component components.Component = newListItem(
+			b.theme.ListMarker.Render(marker),
+			markerWidth,
+			b.buildBlockContainer(child, list.IsTight),
+		)
+		if len(items) > 0 && !list.IsTight {
+			component = stylebox.New(component).SetStyle(lipgloss.NewStyle().PaddingTop(blockSpacing))
+		}
+		items = append(items, flexbox_item.New(component).SetMaxWidth(flexbox_item.MaxAvailable))
+	}
+
+	return flexbox.NewWithContents(items...).SetDirection(flexbox.Column)
+}
+
+func (b *treeBuilder) buildTable(table *extension_ast.Table) components.Component {
+	header := make([]text.Text, 0)
+	rows := make([][]text.Text, 0)
+	for rowNode := table.FirstChild(); rowNode != nil; rowNode = rowNode.NextSibling() {
+		_, isHeader := rowNode.(*extension_ast.TableHeader)
+
+		cellStyle := lipgloss.NewStyle()
+		if isHeader {
+			cellStyle = b.theme.TableHeader
+		}
+
+		row := make([]text.Text, 0, rowNode.ChildCount())
+		for cellNode := rowNode.FirstChild(); cellNode != nil; cellNode = cellNode.NextSibling() {
+			cell := text.New(b.renderInlines(cellNode, cellStyle))
+			if tableCell, ok := cellNode.(*extension_ast.TableCell); ok {
+				cell.SetTextAlignment(getTextAlignment(tableCell.Alignment))
+			}
+			row = append(row, cell)
+		}
+
+		if isHeader {
+			header = row
+		} else {
+			rows = append(rows, row)
+		}
+	}
+
+	return newTable(header, rows, b.theme.TableSeparator)
+}
+
+// Gets the raw text of a block that stores its contents as lines (e.g. a code block)
+func (b *treeBuilder) getLinesText(node ast.Node) string {
+	var builder strings.Builder
+	lines := node.Lines()
+	for idx := 0; idx < lines.Len(); idx++ {
+		segment := lines.At(idx)
+		builder.Write(segment.Value(b.source))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func getTextAlignment(alignment extension_ast.Alignment) text.TextAlignment {
+	switch alignment {
+	case extension_ast.AlignCenter:
+		return text.AlignCenter
+	case extension_ast.AlignRight:
+		return text.AlignRight
+	default:
+		return text.AlignLeft
+	}
+}
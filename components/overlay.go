@@ -0,0 +1,188 @@
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/reflow/truncate"
+	"strings"
+)
+
+// Overlay is content that gets drawn on top of the rest of the UI (e.g. a dropdown's list of options), rather than
+// taking up space in the layout
+type Overlay struct {
+	// The area the overlay is attached to, relative to the top-left corner of the component providing the overlay
+	AnchorX      int
+	AnchorY      int
+	AnchorWidth  int
+	AnchorHeight int
+
+	// Gets placed just below the anchor if it fits there, and otherwise wherever there's more space (above or below)
+	Content string
+
+	// Receives the mouse messages that land on the overlay, with coordinates relative to the overlay's top-left corner
+	// These messages don't get routed to the components underneath the overlay
+	HandleMouse func(msg tea.MouseMsg) tea.Cmd
+}
+
+// OverlayProvider is implemented by the components that can show overlays, and by the containers so they can collect
+// the overlays of their children
+type OverlayProvider interface {
+	// Returns the overlays that are open as of the last View
+	GetOverlays() []Overlay
+}
+
+// PlacedOverlay is an overlay that has been positioned on the screen
+type PlacedOverlay struct {
+	Overlay
+
+	// The position of the overlay's top-left corner on the screen
+	X int
+	Y int
+
+	// The overlay's content, cut down to fit on the screen
+	PlacedContent string
+}
+
+// GetChildOverlays gets the overlays of the child if it has any, translating them using the position of the child's
+// top-left corner within the parent
+func GetChildOverlays(child Component, xOffset int, yOffset int) []Overlay {
+	provider, ok := child.(OverlayProvider)
+	if !ok {
+		return nil
+	}
+	overlays := provider.GetOverlays()
+	for idx := range overlays {
+		overlays[idx].AnchorX += xOffset
+		overlays[idx].AnchorY += yOffset
+	}
+	return overlays
+}
+
+// PlaceOverlays positions the overlays on a screen of the given size
+func PlaceOverlays(overlays []Overlay, screenWidth int, screenHeight int) []PlacedOverlay {
+	result := make([]PlacedOverlay, 0, len(overlays))
+	for _, overlay := range overlays {
+		lines := strings.Split(overlay.Content, "\n")
+		contentWidth := lipgloss.Width(overlay.Content)
+
+		spaceBelow := utilities.GetMaxInt(0, screenHeight-(overlay.AnchorY+overlay.AnchorHeight))
+		spaceAbove := utilities.Clamp(overlay.AnchorY, 0, screenHeight)
+		var y int
+		if len(lines) <= spaceBelow || spaceBelow >= spaceAbove {
+			y = overlay.AnchorY + overlay.AnchorHeight
+			lines = lines[:utilities.GetMinInt(len(lines), spaceBelow)]
+		} else {
+			lines = lines[:utilities.GetMinInt(len(lines), spaceAbove)]
+			y = overlay.AnchorY - len(lines)
+		}
+
+		// Shift left rather than running off the right edge of the screen
+		x := utilities.Clamp(overlay.AnchorX, 0, utilities.GetMaxInt(0, screenWidth-contentWidth))
+		for idx, line := range lines {
+			lines[idx] = truncate.String(line, uint(screenWidth-x))
+		}
+
+		result = append(result, PlacedOverlay{
+			Overlay:       overlay,
+			X:             x,
+			Y:             y,
+			PlacedContent: strings.Join(lines, "\n"),
+		})
+	}
+	return result
+}
+
+// DrawOverlays draws the placed overlays over the view, with later overlays on top of earlier ones
+func DrawOverlays(view string, placedOverlays []PlacedOverlay) string {
+	if len(placedOverlays) == 0 {
+		return view
+	}
+
+	for _, placed := range placedOverlays {
+		view = DrawOver(view, placed.PlacedContent, placed.X, placed.Y)
+	}
+	return view
+}
+
+// DrawOver draws the content over the view with its top-left corner at the position, keeping the styling of the parts
+// of the view around it
+// Any part of the content outside the view's lines gets dropped
+func DrawOver(view string, content string, x int, y int) string {
+	if content == "" {
+		return view
+	}
+	viewLines := strings.Split(view, "\n")
+	for idx, contentLine := range strings.Split(content, "\n") {
+		lineIdx := y + idx
+		if lineIdx < 0 || lineIdx >= len(viewLines) {
+			continue
+		}
+		viewLines[lineIdx] = drawOverLine(viewLines[lineIdx], contentLine, x)
+	}
+	return strings.Join(viewLines, "\n")
+}
+
+// FindOverlayAt finds the topmost placed overlay covering the screen position, returning false if there isn't one
+func FindOverlayAt(placedOverlays []PlacedOverlay, x int, y int) (PlacedOverlay, bool) {
+	for idx := len(placedOverlays) - 1; idx >= 0; idx-- {
+		placed := placedOverlays[idx]
+		width, height := lipgloss.Size(placed.PlacedContent)
+		if placed.PlacedContent != "" && x >= placed.X && x < placed.X+width && y >= placed.Y && y < placed.Y+height {
+			return placed, true
+		}
+	}
+	return PlacedOverlay{}, false
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Replaces the part of the line starting at the column with the overlay line, keeping the rest of the line's styling
+func drawOverLine(line string, overlayLine string, column int) string {
+	const resetSequence = "\x1b[0m"
+
+	left := truncate.String(line, uint(column))
+	left += strings.Repeat(" ", utilities.GetMaxInt(0, column-ansi.PrintableRuneWidth(left)))
+	right := skipColumns(line, column+ansi.PrintableRuneWidth(overlayLine))
+
+	// Resets keep the styles of the line & the overlay from bleeding into each other
+	if strings.Contains(left, "\x1b") {
+		left += resetSequence
+	}
+	if strings.Contains(overlayLine, "\x1b") {
+		overlayLine += resetSequence
+	}
+	return left + overlayLine + right
+}
+
+// Drops the first columns of the line, keeping any escape sequences in them so the rest of the line is still styled
+func skipColumns(line string, numColumns int) string {
+	var escapes strings.Builder
+	isInEscape := false
+	skippedWidth := 0
+	for byteIdx, r := range line {
+		switch {
+		case r == ansi.Marker:
+			isInEscape = true
+			escapes.WriteRune(r)
+		case isInEscape:
+			escapes.WriteRune(r)
+			isInEscape = !ansi.IsTerminator(r)
+		case skippedWidth >= numColumns:
+			return escapes.String() + line[byteIdx:]
+		default:
+			runeWidth := ansi.PrintableRuneWidth(string(r))
+			if skippedWidth+runeWidth > numColumns {
+				// A wide character got cut in half, so a space takes the place of what's left of it
+				skippedWidth += runeWidth
+				escapes.WriteString(strings.Repeat(" ", skippedWidth-numColumns))
+				continue
+			}
+			skippedWidth += runeWidth
+		}
+	}
+	return escapes.String()
+}
@@ -0,0 +1,174 @@
+package progress_bar
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"math"
+	"strings"
+)
+
+const (
+	// The width the bar asks for outside a flexbox; in one, the bar grows to fill the free space (see FillsWidth)
+	defaultBarWidth = 20
+
+	// The bar always gets at least this much room next to the label
+	minBarWidth = 1
+
+	// Wide enough for "100%", so the bar doesn't change size as the progress changes
+	percentageLabelFormat = " %3d%%"
+)
+
+// The blocks used for the last partially-filled cell, in eighths of a cell
+var partialBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+const fullBlock = "█"
+
+// ProgressBar shows how far along something is as a bar that fills the width it's given, using the partial block
+// characters so the bar moves in eighths of a cell rather than whole cells
+// Analogous to the <progress> tag in HTML
+type ProgressBar interface {
+	components.InteractiveComponent
+	components.WidthFiller
+
+	// Between 0 and 1
+	GetProgress() float64
+	SetProgress(progress float64) ProgressBar
+
+	// Whether a percentage is shown after the bar
+	IsPercentageShown() bool
+	SetPercentageShown(isShown bool) ProgressBar
+
+	GetStyles() Styles
+	SetStyles(styles Styles) ProgressBar
+}
+
+// ProgressMsg sets the progress of the bar, so that the progress can come from a tea.Cmd
+type ProgressMsg struct {
+	Bar      ProgressBar
+	Progress float64
+}
+
+type progressBarImpl struct {
+	progress float64
+
+	isPercentageShown bool
+
+	// Nil for the default styles
+	styles *Styles
+}
+
+func New() ProgressBar {
+	return &progressBarImpl{
+		progress:          0,
+		isPercentageShown: false,
+		styles:            nil,
+	}
+}
+
+func (p progressBarImpl) GetProgress() float64 {
+	return p.progress
+}
+
+func (p *progressBarImpl) SetProgress(progress float64) ProgressBar {
+	if math.IsNaN(progress) {
+		progress = 0
+	}
+	p.progress = math.Max(0, math.Min(1, progress))
+	return p
+}
+
+func (p progressBarImpl) IsPercentageShown() bool {
+	return p.isPercentageShown
+}
+
+func (p *progressBarImpl) SetPercentageShown(isShown bool) ProgressBar {
+	p.isPercentageShown = isShown
+	return p
+}
+
+func (p progressBarImpl) GetStyles() Styles {
+	if p.styles == nil {
+		return DefaultStyles()
+	}
+	return *p.styles
+}
+
+func (p *progressBarImpl) SetStyles(styles Styles) ProgressBar {
+	p.styles = &styles
+	return p
+}
+
+func (p *progressBarImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	labelWidth := lipgloss.Width(p.getLabel())
+	return labelWidth + minBarWidth, labelWidth + defaultBarWidth, 1, 1
+}
+
+func (p *progressBarImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (p *progressBarImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	label := p.getLabel()
+	barWidth := utilities.GetMaxInt(0, width-lipgloss.Width(label))
+	bar := RenderBar(p.progress, barWidth, p.GetStyles())
+	return control.FitToWidth(bar+p.GetStyles().Label.Render(label), width)
+}
+
+func (p *progressBarImpl) Update(msg tea.Msg) tea.Cmd {
+	if progressMsg, ok := msg.(ProgressMsg); ok && progressMsg.Bar == ProgressBar(p) {
+		p.SetProgress(progressMsg.Progress)
+	}
+	return nil
+}
+
+// Progress bars don't take the focus
+func (p *progressBarImpl) SetFocus(isFocused bool) {}
+
+func (p progressBarImpl) IsFocused() bool {
+	return false
+}
+
+// The bar looks the same at any width, so it may as well use all there is
+func (p progressBarImpl) FillsWidth() bool {
+	return true
+}
+
+// RenderBar draws a bar of exactly the width, filled to the progress (between 0 and 1) to the nearest eighth of a cell
+func RenderBar(progress float64, width int, styles Styles) string {
+	if width <= 0 {
+		return ""
+	}
+	numEighths := int(math.Round(math.Max(0, math.Min(1, progress)) * float64(width*8)))
+	numFullCells := numEighths / 8
+	partialBlock := partialBlocks[numEighths%8]
+
+	result := styles.Filled.Render(strings.Repeat(fullBlock, numFullCells))
+	numEmptyCells := width - numFullCells
+	if partialBlock != "" {
+		// The partial cell is part fill & part track, so it takes its background from the empty style
+		result += styles.Filled.Copy().Inherit(styles.Empty).Render(partialBlock)
+		numEmptyCells--
+	}
+	return result + styles.Empty.Render(strings.Repeat(" ", numEmptyCells))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (p progressBarImpl) getLabel() string {
+	if !p.isPercentageShown {
+		return ""
+	}
+	return fmt.Sprintf(percentageLabelFormat, int(math.Floor(p.progress*100)))
+}
@@ -0,0 +1,78 @@
+package progress_bar
+
+import (
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestFillsGivenWidth(t *testing.T) {
+	bar := New().SetProgress(0.5)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(1, 20, 1, 1),
+		test_assertions.GetRenderedContentAssertion(4, 1, "██  "),
+		test_assertions.GetRenderedContentAssertion(10, 1, "█████     "),
+	)
+
+	test_assertions.CheckAll(t, assertions, bar)
+}
+
+func TestSubCellPrecision(t *testing.T) {
+	// 0.3 of 4 cells is 9.6 eighths, which rounds to one full cell & two eighths
+	require.Equal(t, "█▎  ", RenderBar(0.3, 4, DefaultStyles()))
+	require.Equal(t, "▏   ", RenderBar(0.03, 4, DefaultStyles()))
+	require.Equal(t, "    ", RenderBar(0, 4, DefaultStyles()))
+	require.Equal(t, "████", RenderBar(1, 4, DefaultStyles()))
+	require.Equal(t, "", RenderBar(0.5, 0, DefaultStyles()))
+}
+
+func TestPercentage(t *testing.T) {
+	bar := New().SetPercentageShown(true).SetProgress(0.3)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(6, 25, 1, 1),
+		test_assertions.GetRenderedContentAssertion(9, 1, "█▎    30%"),
+	)
+	test_assertions.CheckAll(t, assertions, bar)
+
+	// The progress gets clamped
+	bar.SetProgress(1.5)
+	test_assertions.CheckAll(t, test_assertions.GetRenderedContentAssertion(9, 1, "████ 100%"), bar)
+}
+
+func TestProgressMsg(t *testing.T) {
+	bar := New()
+	otherBar := New()
+
+	require.Nil(t, bar.Update(ProgressMsg{Bar: otherBar, Progress: 0.5}))
+	require.Equal(t, 0.0, bar.GetProgress())
+
+	require.Nil(t, bar.Update(ProgressMsg{Bar: bar, Progress: 0.75}))
+	require.Equal(t, 0.75, bar.GetProgress())
+}
+
+func TestFillsFlexbox(t *testing.T) {
+	bar := New().SetProgress(0.5)
+
+	// Across a column
+	column := flexbox.NewWithContents(flexbox_item.New(bar)).SetDirection(flexbox.Column)
+	column.GetContentMinMax()
+	require.Equal(t, strings.Repeat("█", 20)+strings.Repeat(" ", 20), column.View(40, column.GetContentHeightForGivenWidth(40)))
+
+	// Along a row, leaving its neighbors their size
+	row := flexbox.NewWithContents(flexbox_item.New(text.New("Load")), flexbox_item.New(bar)).SetDirection(flexbox.Row)
+	row.GetContentMinMax()
+	require.Equal(t, "Load"+strings.Repeat("█", 18)+strings.Repeat(" ", 18), row.View(40, row.GetContentHeightForGivenWidth(40)))
+
+	// The default can still be overridden
+	capped := flexbox.NewWithContents(flexbox_item.New(bar).SetMaxWidth(flexbox_item.MaxContent))
+	capped.GetContentMinMax()
+	require.Equal(t, strings.Repeat("█", 10)+strings.Repeat(" ", 30), capped.View(40, capped.GetContentHeightForGivenWidth(40)))
+}
@@ -0,0 +1,27 @@
+package progress_bar
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles controls how each part of the progress bar gets styled
+type Styles struct {
+	// Used for the filled part of the bar, whose characters are drawn in the foreground color
+	Filled lipgloss.Style
+
+	// Used for the rest of the bar, which is drawn with spaces so only the background color shows
+	Empty lipgloss.Style
+
+	// Used for the percentage
+	Label lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Filled: lipgloss.NewStyle().Foreground(theme.Color(theme.Primary)),
+		Empty:  lipgloss.NewStyle().Background(theme.Color(theme.Selection)),
+		Label:  lipgloss.NewStyle(),
+	}
+}
@@ -0,0 +1,89 @@
+package components
+
+// Identifiable is a component that has an ID and class tags, so that it can be found in the tree (see FindByID and
+// FindAll)
+// IDs are meant to be unique within a tree, while classes can be shared by any number of components
+type Identifiable interface {
+	GetID() string
+	GetClasses() []string
+}
+
+// Container is a component with children, which the queries walk through
+type Container interface {
+	// In the order they get laid out, including the ones not currently shown (e.g. inactive tabs)
+	GetChildComponents() []Component
+}
+
+// FindByID finds the first component in the tree with the ID, searching depth-first from the root (which is included)
+func FindByID(root Component, id string) (Component, bool) {
+	var result Component
+	found := walk(root, func(component Component) bool {
+		if HasID(id)(component) {
+			result = component
+			return true
+		}
+		return false
+	})
+	return result, found
+}
+
+// FindAll finds every component in the tree that matches the predicate, in depth-first order from the root (which is
+// included)
+func FindAll(root Component, predicate func(component Component) bool) []Component {
+	result := make([]Component, 0)
+	walk(root, func(component Component) bool {
+		if predicate(component) {
+			result = append(result, component)
+		}
+		return false
+	})
+	return result
+}
+
+// HasID gets a predicate for FindAll that matches the components with the ID
+func HasID(id string) func(component Component) bool {
+	return func(component Component) bool {
+		identifiable, ok := component.(Identifiable)
+		return ok && id != "" && identifiable.GetID() == id
+	}
+}
+
+// HasClass gets a predicate for FindAll that matches the components with the class
+func HasClass(class string) func(component Component) bool {
+	return func(component Component) bool {
+		identifiable, ok := component.(Identifiable)
+		if !ok {
+			return false
+		}
+		for _, componentClass := range identifiable.GetClasses() {
+			if componentClass == class {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Visits the components depth-first, stopping as soon as the visitor returns true (which the result says)
+func walk(component Component, visitor func(component Component) bool) bool {
+	if component == nil {
+		return false
+	}
+	if visitor(component) {
+		return true
+	}
+	container, ok := component.(Container)
+	if !ok {
+		return false
+	}
+	for _, child := range container.GetChildComponents() {
+		if walk(child, visitor) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+package radio_group
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+const (
+	selectedMarker   = "(•)"
+	unselectedMarker = "( )"
+
+	// The space between options when they're laid out horizontally
+	horizontalGap = "  "
+
+	// Indicates that no option is selected
+	NoSelection = -1
+)
+
+type Orientation int
+
+const (
+	// Each option gets its own line
+	Vertical Orientation = iota
+
+	// All the options go on one line
+	Horizontal
+)
+
+// RadioGroup is a set of options of which at most one can be selected
+// While it's focused, the arrow keys move between the options and Enter or Space selects one; clicking an option also
+// selects it
+// Analogous to a set of <input type="radio"> tags with the same name in HTML
+type RadioGroup interface {
+	components.InteractiveComponent
+
+	GetOptions() []string
+	// The selection is kept if it's still a valid index
+	SetOptions(options []string) RadioGroup
+
+	// Setting the selection programmatically doesn't send a ChangedMsg
+	GetSelectedIndex() int
+	SetSelectedIndex(idx int) RadioGroup
+	// Returns false if nothing is selected
+	GetSelectedOption() (string, bool)
+
+	GetOrientation() Orientation
+	SetOrientation(orientation Orientation) RadioGroup
+
+	IsDisabled() bool
+	SetDisabled(isDisabled bool) RadioGroup
+
+	IsPressed() bool
+
+	// The focused & pressed styles only get applied to the option that the arrow keys are on
+	GetStyles() control.Styles
+	SetStyles(styles control.Styles) RadioGroup
+}
+
+// ChangedMsg is sent when the user selects an option
+type ChangedMsg struct {
+	Group          RadioGroup
+	SelectedIndex  int
+	SelectedOption string
+}
+
+type radioGroupImpl struct {
+	options []string
+
+	selectedIdx int
+
+	// The option that the arrow keys are on, which Enter or Space will select
+	highlightedIdx int
+
+	orientation Orientation
+
+	// Nil for the default styles
+	styles *control.Styles
+
+	state *control.State
+}
+
+func New(options ...string) RadioGroup {
+	result := &radioGroupImpl{
+		options:        nil,
+		selectedIdx:    NoSelection,
+		highlightedIdx: 0,
+		orientation:    Vertical,
+		styles:         nil,
+		state:          control.NewState(),
+	}
+	result.SetOptions(options)
+	return result
+}
+
+func (r radioGroupImpl) GetOptions() []string {
+	return r.options
+}
+
+func (r *radioGroupImpl) SetOptions(options []string) RadioGroup {
+	r.options = make([]string, len(options))
+	for idx, option := range options {
+		r.options[idx] = sanitizeLabel(option)
+	}
+	if r.selectedIdx >= len(r.options) {
+		r.selectedIdx = NoSelection
+	}
+	r.highlightedIdx = utilities.Clamp(r.highlightedIdx, 0, utilities.GetMaxInt(0, len(r.options)-1))
+	return r
+}
+
+func (r radioGroupImpl) GetSelectedIndex() int {
+	return r.selectedIdx
+}
+
+func (r *radioGroupImpl) SetSelectedIndex(idx int) RadioGroup {
+	if idx < 0 || idx >= len(r.options) {
+		r.selectedIdx = NoSelection
+		return r
+	}
+	r.selectedIdx = idx
+	r.highlightedIdx = idx
+	return r
+}
+
+func (r radioGroupImpl) GetSelectedOption() (string, bool) {
+	if r.selectedIdx == NoSelection {
+		return "", false
+	}
+	return r.options[r.selectedIdx], true
+}
+
+func (r radioGroupImpl) GetOrientation() Orientation {
+	return r.orientation
+}
+
+func (r *radioGroupImpl) SetOrientation(orientation Orientation) RadioGroup {
+	r.orientation = orientation
+	return r
+}
+
+func (r radioGroupImpl) IsDisabled() bool {
+	return r.state.IsDisabled()
+}
+
+func (r *radioGroupImpl) SetDisabled(isDisabled bool) RadioGroup {
+	r.state.SetDisabled(isDisabled)
+	return r
+}
+
+func (r radioGroupImpl) IsPressed() bool {
+	return r.state.IsPressed()
+}
+
+func (r radioGroupImpl) GetStyles() control.Styles {
+	if r.styles == nil {
+		return control.DefaultStyles()
+	}
+	return *r.styles
+}
+
+func (r *radioGroupImpl) SetStyles(styles control.Styles) RadioGroup {
+	r.styles = &styles
+	return r
+}
+
+func (r *radioGroupImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	if len(r.options) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	// The markers are the same width, so selecting doesn't change the size
+	var width, height int
+	switch r.orientation {
+	case Horizontal:
+		width = lipgloss.Width(strings.Join(r.getOptionContents(), horizontalGap))
+		height = 1
+	default:
+		for _, content := range r.getOptionContents() {
+			width = utilities.GetMaxInt(width, lipgloss.Width(content))
+		}
+		height = len(r.options)
+	}
+	return width, width, height, height
+}
+
+func (r *radioGroupImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	_, _, height, _ := r.GetContentMinMax()
+	return height
+}
+
+func (r *radioGroupImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	r.state.RecordViewSize(width, height)
+
+	renderedOptions := make([]string, len(r.options))
+	for idx, content := range r.getOptionContents() {
+		renderedOptions[idx] = r.getOptionStyle(idx).Render(content)
+	}
+
+	if r.orientation == Horizontal {
+		return control.FitToWidth(strings.Join(renderedOptions, horizontalGap), width)
+	}
+	if len(renderedOptions) > height {
+		renderedOptions = renderedOptions[:height]
+	}
+	for idx, rendered := range renderedOptions {
+		renderedOptions[idx] = control.FitToWidth(rendered, width)
+	}
+	return strings.Join(renderedOptions, "\n")
+}
+
+func (r *radioGroupImpl) Update(msg tea.Msg) tea.Cmd {
+	if len(r.options) == 0 {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !r.state.IsFocused() {
+			break
+		}
+		previousKey, nextKey := tea.KeyUp, tea.KeyDown
+		if r.orientation == Horizontal {
+			previousKey, nextKey = tea.KeyLeft, tea.KeyRight
+		}
+		switch msg.Type {
+		case previousKey:
+			r.highlightedIdx = utilities.GetMaxInt(0, r.highlightedIdx-1)
+			return nil
+		case nextKey:
+			r.highlightedIdx = utilities.GetMinInt(len(r.options)-1, r.highlightedIdx+1)
+			return nil
+		}
+	case tea.MouseMsg:
+		// Pressing on an option moves to it, so that the press shows on the right option
+		if msg.Type == tea.MouseLeft && !r.state.IsDisabled() {
+			if idx := r.getOptionIdxAt(msg.X, msg.Y); idx != NoSelection {
+				r.highlightedIdx = idx
+			}
+		}
+	}
+
+	activation, cmd := r.state.Update(msg)
+	if !activation.IsActivated {
+		return cmd
+	}
+	if activation.IsMouse {
+		idx := r.getOptionIdxAt(activation.MouseX, activation.MouseY)
+		if idx == NoSelection {
+			return cmd
+		}
+		r.highlightedIdx = idx
+	}
+	if r.selectedIdx == r.highlightedIdx {
+		return cmd
+	}
+	r.selectedIdx = r.highlightedIdx
+	changedMsg := ChangedMsg{
+		Group:          r,
+		SelectedIndex:  r.selectedIdx,
+		SelectedOption: r.options[r.selectedIdx],
+	}
+	return tea.Batch(cmd, func() tea.Msg {
+		return changedMsg
+	})
+}
+
+func (r *radioGroupImpl) SetFocus(isFocused bool) {
+	r.state.SetFocus(isFocused && len(r.options) > 0)
+}
+
+func (r radioGroupImpl) IsFocused() bool {
+	return r.state.IsFocused()
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (r radioGroupImpl) getOptionContents() []string {
+	result := make([]string, len(r.options))
+	for idx, option := range r.options {
+		marker := unselectedMarker
+		if idx == r.selectedIdx {
+			marker = selectedMarker
+		}
+		result[idx] = marker + " " + option
+	}
+	return result
+}
+
+func (r radioGroupImpl) getOptionStyle(idx int) lipgloss.Style {
+	if r.state.IsDisabled() || idx == r.highlightedIdx {
+		return r.GetStyles().Get(r.state)
+	}
+	return r.GetStyles().Normal
+}
+
+// Gets the option at the position (relative to the group), or NoSelection if there isn't one there
+func (r radioGroupImpl) getOptionIdxAt(x int, y int) int {
+	if r.orientation == Vertical {
+		if y < 0 || y >= len(r.options) || x < 0 {
+			return NoSelection
+		}
+		return y
+	}
+
+	if y != 0 {
+		return NoSelection
+	}
+	optionStart := 0
+	for idx, content := range r.getOptionContents() {
+		optionEnd := optionStart + lipgloss.Width(content)
+		if x >= optionStart && x < optionEnd {
+			return idx
+		}
+		optionStart = optionEnd + lipgloss.Width(horizontalGap)
+	}
+	return NoSelection
+}
+
+// Options are a single line
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
@@ -0,0 +1,83 @@
+package radio_group
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestVerticalSize(t *testing.T) {
+	group := New("Small", "Medium", "Large").SetSelectedIndex(1)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(10, 10, 3, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			5, 3,
+			10, 3,
+		),
+		test_assertions.GetRenderedContentAssertion(10, 3, "( ) Small \n(•) Medium\n( ) Large "),
+	)
+
+	test_assertions.CheckAll(t, assertions, group)
+}
+
+func TestHorizontalSize(t *testing.T) {
+	group := New("Yes", "No").SetOrientation(Horizontal)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(15, 15, 1, 1),
+		test_assertions.GetRenderedContentAssertion(15, 1, "( ) Yes  ( ) No"),
+	)
+
+	test_assertions.CheckAll(t, assertions, group)
+}
+
+func TestKeyboardSelection(t *testing.T) {
+	group := New("Small", "Medium", "Large")
+	group.SetFocus(true)
+
+	require.Nil(t, group.Update(tea.KeyMsg{Type: tea.KeyDown}))
+	require.Nil(t, group.Update(tea.KeyMsg{Type: tea.KeyDown}))
+	require.Nil(t, group.Update(tea.KeyMsg{Type: tea.KeyDown}))
+	require.Equal(t, NoSelection, group.GetSelectedIndex())
+
+	msgs := test_assertions.GetMsgs(group.Update(tea.KeyMsg{Type: tea.KeySpace}))
+	require.Contains(t, msgs, ChangedMsg{Group: group, SelectedIndex: 2, SelectedOption: "Large"})
+	option, isSelected := group.GetSelectedOption()
+	require.True(t, isSelected)
+	require.Equal(t, "Large", option)
+
+	// Selecting what's already selected isn't a change
+	for _, msg := range test_assertions.GetMsgs(group.Update(tea.KeyMsg{Type: tea.KeyEnter})) {
+		_, isChangedMsg := msg.(ChangedMsg)
+		require.False(t, isChangedMsg)
+	}
+}
+
+func TestMouseSelection(t *testing.T) {
+	group := New("Yes", "No").SetOrientation(Horizontal)
+	group.View(15, 1)
+
+	// Clicking in the gap between the options does nothing
+	group.Update(tea.MouseMsg{X: 8, Y: 0, Type: tea.MouseLeft})
+	require.Nil(t, test_assertions.GetMsgs(group.Update(tea.MouseMsg{X: 8, Y: 0, Type: tea.MouseRelease})))
+
+	group.Update(tea.MouseMsg{X: 10, Y: 0, Type: tea.MouseLeft})
+	msgs := test_assertions.GetMsgs(group.Update(tea.MouseMsg{X: 13, Y: 0, Type: tea.MouseRelease}))
+	require.Equal(t, []tea.Msg{ChangedMsg{Group: group, SelectedIndex: 1, SelectedOption: "No"}}, msgs)
+}
+
+func TestEmptyGroupCantBeFocused(t *testing.T) {
+	group := New()
+	group.SetFocus(true)
+	require.False(t, group.IsFocused())
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(0, 0, 0, 0),
+	)
+	test_assertions.CheckAll(t, assertions, group)
+}
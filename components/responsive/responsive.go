@@ -0,0 +1,195 @@
+package responsive
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"sort"
+)
+
+// Breakpoint is a variant of the layout, used when the responsive component gets at least the min width
+type Breakpoint struct {
+	MinWidth  int
+	Component components.Component
+}
+
+// Responsive switches between variants of a layout depending on the width it gets, e.g. a row of columns when there's
+// room and a single stacked column when there isn't
+// The variant gets picked when the width is known (in GetContentHeightForGivenWidth & View), using the breakpoint
+// with the largest min width that fits; below all the min widths, the narrowest breakpoint gets used
+// The min & max sizes are the union of all the variants, so the parent leaves room for whichever gets picked
+// The focus moves along to the new variant when the variant changes, and the variants that aren't on the screen only get
+// broadcast messages (see components.IsBroadcastMsg)
+type Responsive interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Container
+
+	// Sorted by min width
+	GetBreakpoints() []Breakpoint
+	AddBreakpoint(minWidth int, component components.Component) Responsive
+
+	// Gets the variant picked for the last width the component was laid out at
+	GetActiveComponent() components.Component
+}
+
+type responsiveImpl struct {
+	breakpoints []Breakpoint
+
+	// The breakpoint picked for the last width given
+	activeIdx int
+}
+
+func New(breakpoints ...Breakpoint) Responsive {
+	result := &responsiveImpl{
+		breakpoints: make([]Breakpoint, 0, len(breakpoints)),
+		activeIdx:   0,
+	}
+	for _, breakpoint := range breakpoints {
+		result.AddBreakpoint(breakpoint.MinWidth, breakpoint.Component)
+	}
+	return result
+}
+
+func (r responsiveImpl) GetBreakpoints() []Breakpoint {
+	return r.breakpoints
+}
+
+func (r *responsiveImpl) AddBreakpoint(minWidth int, component components.Component) Responsive {
+	activeComponent := r.GetActiveComponent()
+	r.breakpoints = append(r.breakpoints, Breakpoint{
+		MinWidth:  utilities.GetMaxInt(0, minWidth),
+		Component: component,
+	})
+	sort.SliceStable(r.breakpoints, func(i, j int) bool {
+		return r.breakpoints[i].MinWidth < r.breakpoints[j].MinWidth
+	})
+
+	// The sort can move the active breakpoint, so it gets found again
+	for idx, breakpoint := range r.breakpoints {
+		if activeComponent != nil && breakpoint.Component == activeComponent {
+			r.activeIdx = idx
+		}
+	}
+	return r
+}
+
+// All the variants, including the ones not picked
+func (r responsiveImpl) GetChildComponents() []components.Component {
+	result := make([]components.Component, len(r.breakpoints))
+	for idx, breakpoint := range r.breakpoints {
+		result[idx] = breakpoint.Component
+	}
+	return result
+}
+
+func (r responsiveImpl) GetActiveComponent() components.Component {
+	if len(r.breakpoints) == 0 {
+		return nil
+	}
+	return r.breakpoints[r.activeIdx].Component
+}
+
+func (r *responsiveImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return r.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (r *responsiveImpl) GetContentHeightForGivenWidth(width int) int {
+	return r.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (r *responsiveImpl) View(width int, height int) string {
+	return r.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (r *responsiveImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	for idx, breakpoint := range r.breakpoints {
+		variantMinWidth, variantMaxWidth, variantMinHeight, variantMaxHeight := components.GetChildContentMinMax(
+			breakpoint.Component,
+			ctx,
+		)
+		if idx == 0 {
+			minWidth, maxWidth, minHeight, maxHeight = variantMinWidth, variantMaxWidth, variantMinHeight, variantMaxHeight
+			continue
+		}
+		minWidth = utilities.GetMinInt(minWidth, variantMinWidth)
+		maxWidth = utilities.GetMaxInt(maxWidth, variantMaxWidth)
+		minHeight = utilities.GetMinInt(minHeight, variantMinHeight)
+		maxHeight = utilities.GetMaxInt(maxHeight, variantMaxHeight)
+	}
+	return
+}
+
+func (r *responsiveImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if len(r.breakpoints) == 0 {
+		return 0
+	}
+	r.selectBreakpoint(width)
+	return components.GetChildContentHeightForGivenWidth(r.GetActiveComponent(), ctx, width)
+}
+
+func (r *responsiveImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	if len(r.breakpoints) == 0 {
+		return ""
+	}
+	// Normally the same as in GetContentHeightForGivenWidth, but View can get called on its own
+	r.selectBreakpoint(width)
+	return components.ViewChild(r.GetActiveComponent(), ctx, width, height)
+}
+
+func (r *responsiveImpl) Update(msg tea.Msg) tea.Cmd {
+	if len(r.breakpoints) == 0 {
+		return nil
+	}
+	if !components.IsBroadcastMsg(msg) {
+		return components.UpdateChild(r.GetActiveComponent(), msg, 0, 0)
+	}
+	cmds := make([]tea.Cmd, len(r.breakpoints))
+	for idx, breakpoint := range r.breakpoints {
+		cmds[idx] = components.UpdateChild(breakpoint.Component, msg, 0, 0)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (r *responsiveImpl) SetFocus(isFocused bool) {
+	if len(r.breakpoints) == 0 {
+		return
+	}
+	components.SetChildFocus(r.GetActiveComponent(), isFocused)
+}
+
+func (r responsiveImpl) IsFocused() bool {
+	return len(r.breakpoints) > 0 && components.IsChildFocused(r.GetActiveComponent())
+}
+
+func (r responsiveImpl) GetOverlays() []components.Overlay {
+	if len(r.breakpoints) == 0 {
+		return nil
+	}
+	return components.GetChildOverlays(r.GetActiveComponent(), 0, 0)
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Picks the breakpoint for the width, moving the focus along to it if the previous one had it
+func (r *responsiveImpl) selectBreakpoint(width int) {
+	newActiveIdx := 0
+	for idx, breakpoint := range r.breakpoints {
+		if breakpoint.MinWidth <= width {
+			newActiveIdx = idx
+		}
+	}
+	if newActiveIdx == r.activeIdx {
+		return
+	}
+
+	wasFocused := components.IsChildFocused(r.GetActiveComponent())
+	components.SetChildFocus(r.GetActiveComponent(), false)
+	r.activeIdx = newActiveIdx
+	if wasFocused {
+		components.SetChildFocus(r.GetActiveComponent(), true)
+	}
+}
@@ -0,0 +1,82 @@
+package responsive
+
+import (
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBreakpoints(t *testing.T) {
+	newColumns := func() []flexbox_item.FlexboxItem {
+		return []flexbox_item.FlexboxItem{
+			flexbox_item.New(text.New("aa")),
+			flexbox_item.New(text.New("bb")),
+			flexbox_item.New(text.New("cc")),
+		}
+	}
+	row := flexbox.NewWithContents(newColumns()...)
+	column := flexbox.NewWithContents(newColumns()...).SetDirection(flexbox.Column)
+
+	// Added out of order, to check they get sorted
+	component := New(Breakpoint{MinWidth: 6, Component: row}).AddBreakpoint(0, column)
+	require.Equal(t, 0, component.GetBreakpoints()[0].MinWidth)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		// The union of the row (6x1) and the column (2x3)
+		test_assertions.GetContentSizeAssertions(2, 6, 1, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			2, 3,
+			5, 3,
+			6, 1,
+			10, 1,
+		),
+	)
+	test_assertions.CheckAll(t, assertions, component)
+
+	require.Equal(t, "aabbcc", render(component, 6))
+	require.Equal(t, row, component.GetActiveComponent())
+	require.Equal(t, "aa   \nbb   \ncc   ", render(component, 5))
+	require.Equal(t, column, component.GetActiveComponent())
+}
+
+func TestNarrowerThanAllBreakpoints(t *testing.T) {
+	narrow := text.New("narrow")
+	component := New(
+		Breakpoint{MinWidth: 4, Component: narrow},
+		Breakpoint{MinWidth: 10, Component: text.New("wide")},
+	)
+	render(component, 2)
+	require.Equal(t, narrow, component.GetActiveComponent())
+}
+
+func TestFocusFollowsVariant(t *testing.T) {
+	narrow := button.New("A")
+	wide := button.New("B")
+	component := New(
+		Breakpoint{MinWidth: 0, Component: narrow},
+		Breakpoint{MinWidth: 10, Component: wide},
+	)
+
+	render(component, 5)
+	component.SetFocus(true)
+	require.True(t, narrow.IsFocused())
+
+	render(component, 10)
+	require.True(t, component.IsFocused())
+	require.True(t, wide.IsFocused())
+	require.False(t, narrow.IsFocused())
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func render(component Responsive, width int) string {
+	component.GetContentMinMax()
+	return component.View(width, component.GetContentHeightForGivenWidth(width))
+}
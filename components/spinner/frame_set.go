@@ -0,0 +1,34 @@
+package spinner
+
+import "time"
+
+// FrameSet is the frames a spinner cycles through, and how long each one is shown for
+type FrameSet struct {
+	Frames   []string
+	Interval time.Duration
+}
+
+var Line = FrameSet{
+	Frames:   []string{"|", "/", "-", "\\"},
+	Interval: 100 * time.Millisecond,
+}
+
+var Dots = FrameSet{
+	Frames:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	Interval: 80 * time.Millisecond,
+}
+
+var Circle = FrameSet{
+	Frames:   []string{"◐", "◓", "◑", "◒"},
+	Interval: 120 * time.Millisecond,
+}
+
+var Pulse = FrameSet{
+	Frames:   []string{"█", "▓", "▒", "░", "▒", "▓"},
+	Interval: 120 * time.Millisecond,
+}
+
+var Ellipsis = FrameSet{
+	Frames:   []string{"   ", ".  ", ".. ", "..."},
+	Interval: 300 * time.Millisecond,
+}
@@ -0,0 +1,185 @@
+package spinner
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+	"time"
+)
+
+// Spinner shows that something is happening by cycling through a set of frames, with an optional label after them
+// It moves to the next frame each time it gets a TickMsg, sending itself the next TickMsg after the frame set's
+// interval; the first one comes from Start, whose command needs to be run (e.g. with bubblebath.WithInitCmd)
+type Spinner interface {
+	components.InteractiveComponent
+
+	GetFrameSet() FrameSet
+	SetFrameSet(frameSet FrameSet) Spinner
+
+	GetLabel() string
+	SetLabel(label string) Spinner
+
+	// Returns the command that sends the first tick
+	Start() tea.Cmd
+	// Stops on the current frame
+	Stop() Spinner
+	IsSpinning() bool
+
+	GetStyle() lipgloss.Style
+	SetStyle(style lipgloss.Style) Spinner
+}
+
+// TickMsg moves a spinner to its next frame
+type TickMsg struct {
+	spinner *spinnerImpl
+
+	// Identifies the chain of ticks the message belongs to, so that restarting doesn't leave two chains running
+	tickChainIdx int
+}
+
+type spinnerImpl struct {
+	frameSet FrameSet
+
+	frameIdx int
+
+	label string
+
+	isSpinning bool
+
+	// Counts the times the spinner has been started or stopped, so that ticks from before then get ignored
+	tickChainIdx int
+
+	style lipgloss.Style
+}
+
+func New() Spinner {
+	return &spinnerImpl{
+		frameSet:     Line,
+		frameIdx:     0,
+		label:        "",
+		isSpinning:   false,
+		tickChainIdx: 0,
+		style:        lipgloss.NewStyle(),
+	}
+}
+
+func (s spinnerImpl) GetFrameSet() FrameSet {
+	return s.frameSet
+}
+
+func (s *spinnerImpl) SetFrameSet(frameSet FrameSet) Spinner {
+	s.frameSet = frameSet
+	s.frameIdx = 0
+	return s
+}
+
+func (s spinnerImpl) GetLabel() string {
+	return s.label
+}
+
+func (s *spinnerImpl) SetLabel(label string) Spinner {
+	s.label = strings.Join(strings.Fields(label), " ")
+	return s
+}
+
+func (s *spinnerImpl) Start() tea.Cmd {
+	s.isSpinning = true
+	s.tickChainIdx++
+	return s.getTickCmd()
+}
+
+func (s *spinnerImpl) Stop() Spinner {
+	s.isSpinning = false
+	s.tickChainIdx++
+	return s
+}
+
+func (s spinnerImpl) IsSpinning() bool {
+	return s.isSpinning
+}
+
+func (s spinnerImpl) GetStyle() lipgloss.Style {
+	return s.style
+}
+
+func (s *spinnerImpl) SetStyle(style lipgloss.Style) Spinner {
+	s.style = style
+	return s
+}
+
+func (s *spinnerImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	// Sized for the widest frame, so the label doesn't jump around
+	width := s.getFrameWidth()
+	if s.label != "" {
+		width += 1 + lipgloss.Width(s.label)
+	}
+	return width, width, 1, 1
+}
+
+func (s *spinnerImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (s *spinnerImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	result := ""
+	if len(s.frameSet.Frames) > 0 {
+		result = s.style.Render(control.FitToWidth(s.frameSet.Frames[s.frameIdx], s.getFrameWidth()))
+	}
+	if s.label != "" {
+		result += " " + s.label
+	}
+	return control.FitToWidth(result, width)
+}
+
+func (s *spinnerImpl) Update(msg tea.Msg) tea.Cmd {
+	tickMsg, ok := msg.(TickMsg)
+	if !ok || tickMsg.spinner != s || tickMsg.tickChainIdx != s.tickChainIdx || !s.isSpinning {
+		return nil
+	}
+	if len(s.frameSet.Frames) > 0 {
+		s.frameIdx = (s.frameIdx + 1) % len(s.frameSet.Frames)
+	}
+	return s.getTickCmd()
+}
+
+// Spinners don't take the focus
+func (s *spinnerImpl) SetFocus(isFocused bool) {}
+
+func (s spinnerImpl) IsFocused() bool {
+	return false
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (s spinnerImpl) getFrameWidth() int {
+	result := 0
+	for _, frame := range s.frameSet.Frames {
+		result = utilities.GetMaxInt(result, lipgloss.Width(frame))
+	}
+	return result
+}
+
+func (s *spinnerImpl) getTickCmd() tea.Cmd {
+	tickMsg := TickMsg{
+		spinner:      s,
+		tickChainIdx: s.tickChainIdx,
+	}
+	interval := s.frameSet.Interval
+	if interval <= 0 {
+		interval = Line.Interval
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return tickMsg
+	})
+}
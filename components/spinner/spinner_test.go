@@ -0,0 +1,62 @@
+package spinner
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSize(t *testing.T) {
+	spinner := New().SetFrameSet(Ellipsis).SetLabel("Loading")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(11, 11, 1, 1),
+		test_assertions.GetRenderedContentAssertion(11, 1, "    Loading"),
+	)
+
+	test_assertions.CheckAll(t, assertions, spinner)
+}
+
+func TestTicksAdvanceFrames(t *testing.T) {
+	spinner := New().SetFrameSet(FrameSet{Frames: []string{"a", "b", "c"}, Interval: time.Millisecond})
+	require.Equal(t, "a", spinner.View(1, 1))
+
+	tickMsg := spinner.Start()()
+	require.True(t, spinner.IsSpinning())
+	for _, expectedFrame := range []string{"b", "c", "a"} {
+		cmd := spinner.Update(tickMsg)
+		require.NotNil(t, cmd)
+		require.Equal(t, expectedFrame, spinner.View(1, 1))
+		tickMsg = cmd()
+	}
+
+	// Other spinners' ticks are ignored
+	otherTickMsg := New().Start()()
+	require.Nil(t, spinner.Update(otherTickMsg))
+	require.Equal(t, "a", spinner.View(1, 1))
+
+	spinner.Stop()
+	require.Nil(t, spinner.Update(tickMsg))
+	require.Equal(t, "a", spinner.View(1, 1))
+}
+
+func TestRestartingDoesNotDoubleSpeed(t *testing.T) {
+	spinner := New().SetFrameSet(FrameSet{Frames: []string{"a", "b", "c"}, Interval: time.Millisecond})
+
+	staleTickMsg := spinner.Start()()
+	freshTickMsg := spinner.Start()()
+
+	// Only the latest chain of ticks moves the spinner
+	require.Nil(t, spinner.Update(staleTickMsg))
+	require.Equal(t, "a", spinner.View(1, 1))
+	require.NotNil(t, spinner.Update(freshTickMsg))
+	require.Equal(t, "b", spinner.View(1, 1))
+
+	// Spinners never take the focus
+	spinner.SetFocus(true)
+	require.False(t, spinner.IsFocused())
+	require.Nil(t, spinner.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+}
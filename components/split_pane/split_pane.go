@@ -0,0 +1,512 @@
+package split_pane
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"math"
+	"strings"
+)
+
+const (
+	horizontalDivider = "│"
+	verticalDivider   = "─"
+
+	// The divider is one cell thick
+	dividerSize = 1
+
+	defaultRatio = 0.5
+)
+
+type Orientation int
+
+const (
+	// The children go side by side, with a vertical divider between them
+	Horizontal Orientation = iota
+
+	// The children go one above the other, with a horizontal divider between them
+	Vertical
+)
+
+type Side int
+
+const (
+	NoSide Side = iota
+	First
+	Second
+)
+
+// SplitPane splits its space between two children, with a divider between them that can be moved by dragging it or
+// with Alt and the arrow keys while the pane itself has the focus (which it only takes when neither side accepts it)
+// Neither side gets smaller than its minimum content size; moving the divider more than halfway past a side's minimum
+// collapses that side instead, and moving the divider back out expands it again
+// The ratio is kept while a side is collapsed, and can be saved with GetRatio & restored with SetRatio
+// A collapsed side can't have the focus and doesn't get keys or mouse messages; collapsing the focused side moves the
+// focus to the other side (or to the pane itself, if the other side doesn't accept it)
+type SplitPane interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Container
+
+	GetFirst() components.Component
+	GetSecond() components.Component
+
+	GetOrientation() Orientation
+	SetOrientation(orientation Orientation) SplitPane
+
+	// The fraction of the space (not counting the divider) that goes to the first child, before minimum sizes are applied
+	GetRatio() float64
+	SetRatio(ratio float64) SplitPane
+
+	GetCollapsedSide() Side
+	SetCollapsedSide(side Side) SplitPane
+
+	GetStyles() Styles
+	SetStyles(styles Styles) SplitPane
+}
+
+// ResizedMsg is sent when the user moves the divider, so that the ratio can be saved
+type ResizedMsg struct {
+	Pane          SplitPane
+	Ratio         float64
+	CollapsedSide Side
+}
+
+type splitPaneImpl struct {
+	first  components.Component
+	second components.Component
+
+	orientation Orientation
+
+	ratio float64
+
+	collapsedSide Side
+
+	// Nil for the default styles
+	styles *Styles
+
+	// Set when the pane takes the focus itself, because neither child accepts it
+	isFocused bool
+
+	// Set while the divider is being dragged with the mouse
+	isDragging bool
+
+	// The minimum sizes of the children along the split, as of the last GetContentMinMax
+	firstMinSizeCache  int
+	secondMinSizeCache int
+
+	// The size of the children along the split, as of the last GetContentHeightForGivenWidth or View
+	firstSizeCache  int
+	secondSizeCache int
+
+	lastViewWidth  int
+	lastViewHeight int
+}
+
+func New(first components.Component, second components.Component) SplitPane {
+	return &splitPaneImpl{
+		first:              first,
+		second:             second,
+		orientation:        Horizontal,
+		ratio:              defaultRatio,
+		collapsedSide:      NoSide,
+		styles:             nil,
+		isFocused:          false,
+		isDragging:         false,
+		firstMinSizeCache:  0,
+		secondMinSizeCache: 0,
+		firstSizeCache:     0,
+		secondSizeCache:    0,
+		lastViewWidth:      0,
+		lastViewHeight:     0,
+	}
+}
+
+func (s splitPaneImpl) GetChildComponents() []components.Component {
+	return []components.Component{s.first, s.second}
+}
+
+func (s splitPaneImpl) GetFirst() components.Component {
+	return s.first
+}
+
+func (s splitPaneImpl) GetSecond() components.Component {
+	return s.second
+}
+
+func (s splitPaneImpl) GetOrientation() Orientation {
+	return s.orientation
+}
+
+func (s *splitPaneImpl) SetOrientation(orientation Orientation) SplitPane {
+	s.orientation = orientation
+	return s
+}
+
+func (s splitPaneImpl) GetRatio() float64 {
+	return s.ratio
+}
+
+func (s *splitPaneImpl) SetRatio(ratio float64) SplitPane {
+	if math.IsNaN(ratio) {
+		ratio = defaultRatio
+	}
+	s.ratio = math.Max(0, math.Min(1, ratio))
+	return s
+}
+
+func (s splitPaneImpl) GetCollapsedSide() Side {
+	return s.collapsedSide
+}
+
+func (s *splitPaneImpl) SetCollapsedSide(side Side) SplitPane {
+	s.collapse(side)
+	return s
+}
+
+func (s splitPaneImpl) GetStyles() Styles {
+	if s.styles == nil {
+		return DefaultStyles()
+	}
+	return *s.styles
+}
+
+func (s *splitPaneImpl) SetStyles(styles Styles) SplitPane {
+	s.styles = &styles
+	return s
+}
+
+func (s *splitPaneImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return s.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (s *splitPaneImpl) GetContentHeightForGivenWidth(width int) int {
+	return s.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (s *splitPaneImpl) View(width int, height int) string {
+	return s.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (s *splitPaneImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	firstMinWidth, firstMaxWidth, firstMinHeight, firstMaxHeight := components.GetChildContentMinMax(s.first, ctx)
+	secondMinWidth, secondMaxWidth, secondMinHeight, secondMaxHeight := components.GetChildContentMinMax(s.second, ctx)
+
+	// The minimums are cached before collapsing, since they're needed for expanding a collapsed side again
+	s.firstMinSizeCache, s.secondMinSizeCache = firstMinWidth, secondMinWidth
+	if s.orientation == Vertical {
+		s.firstMinSizeCache, s.secondMinSizeCache = firstMinHeight, secondMinHeight
+	}
+
+	// A collapsed side takes no space
+	switch s.collapsedSide {
+	case First:
+		firstMinWidth, firstMaxWidth, firstMinHeight, firstMaxHeight = 0, 0, 0, 0
+	case Second:
+		secondMinWidth, secondMaxWidth, secondMinHeight, secondMaxHeight = 0, 0, 0, 0
+	}
+
+	if s.orientation == Vertical {
+		minWidth = utilities.GetMaxInt(firstMinWidth, secondMinWidth)
+		maxWidth = utilities.GetMaxInt(firstMaxWidth, secondMaxWidth)
+		minHeight = firstMinHeight + dividerSize + secondMinHeight
+		maxHeight = firstMaxHeight + dividerSize + secondMaxHeight
+		return
+	}
+
+	minWidth = firstMinWidth + dividerSize + secondMinWidth
+	maxWidth = firstMaxWidth + dividerSize + secondMaxWidth
+	minHeight = utilities.GetMaxInt(firstMinHeight, secondMinHeight)
+	maxHeight = utilities.GetMaxInt(firstMaxHeight, secondMaxHeight)
+	return
+}
+
+func (s *splitPaneImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 {
+		return 0
+	}
+
+	if s.orientation == Vertical {
+		// Both children get the full width, and the split only gets decided once the height is known
+		return s.getChildHeight(ctx, s.first, First, width) + dividerSize + s.getChildHeight(ctx, s.second, Second, width)
+	}
+
+	s.firstSizeCache, s.secondSizeCache = s.getSizes(width)
+	return utilities.GetMaxInt(
+		s.getChildHeight(ctx, s.first, First, s.firstSizeCache),
+		s.getChildHeight(ctx, s.second, Second, s.secondSizeCache),
+	)
+}
+
+func (s *splitPaneImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	s.lastViewWidth, s.lastViewHeight = width, height
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	dividerStyle := s.GetStyles().Divider
+	if s.isDragging {
+		dividerStyle = s.GetStyles().DraggingDivider
+	} else if s.IsFocused() {
+		dividerStyle = s.GetStyles().FocusedDivider
+	}
+
+	if s.orientation == Vertical {
+		s.firstSizeCache, s.secondSizeCache = s.getSizes(height)
+		lines := make([]string, 0, height)
+		lines = append(lines, getChildLines(ctx, s.first, width, s.firstSizeCache)...)
+		lines = append(lines, dividerStyle.Render(strings.Repeat(verticalDivider, width)))
+		lines = append(lines, getChildLines(ctx, s.second, width, s.secondSizeCache)...)
+
+		// When there isn't even room for the divider
+		if len(lines) > height {
+			lines = lines[:height]
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	s.firstSizeCache, s.secondSizeCache = s.getSizes(width)
+	firstLines := getChildLines(ctx, s.first, s.firstSizeCache, height)
+	secondLines := getChildLines(ctx, s.second, s.secondSizeCache, height)
+	lines := make([]string, height)
+	for idx := range lines {
+		lines[idx] = firstLines[idx] + dividerStyle.Render(horizontalDivider) + secondLines[idx]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *splitPaneImpl) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Only when the pane itself has the focus, as focused children may use Alt & the arrows themselves (e.g. to jump
+		// between words)
+		if !s.isFocused || !msg.Alt {
+			break
+		}
+		shrinkKey, growKey := tea.KeyLeft, tea.KeyRight
+		if s.orientation == Vertical {
+			shrinkKey, growKey = tea.KeyUp, tea.KeyDown
+		}
+		switch msg.Type {
+		case shrinkKey:
+			return s.moveDivider(-1)
+		case growKey:
+			return s.moveDivider(1)
+		}
+	case tea.MouseMsg:
+		if cmd, isHandled := s.handleDividerMouse(msg); isHandled {
+			return cmd
+		}
+	}
+
+	firstXOffset, firstYOffset, secondXOffset, secondYOffset := s.getChildOffsets()
+	return tea.Batch(
+		s.updateChild(First, msg, firstXOffset, firstYOffset),
+		s.updateChild(Second, msg, secondXOffset, secondYOffset),
+	)
+}
+
+func (s *splitPaneImpl) SetFocus(isFocused bool) {
+	if !isFocused {
+		s.isFocused = false
+		components.SetChildFocus(s.first, false)
+		components.SetChildFocus(s.second, false)
+		return
+	}
+
+	// The pane only takes the focus itself if neither child will, so that the divider can still be moved with keys
+	s.isFocused = !s.focusChild(First) && !s.focusChild(Second)
+}
+
+func (s splitPaneImpl) IsFocused() bool {
+	return s.isFocused || components.IsChildFocused(s.first) || components.IsChildFocused(s.second)
+}
+
+func (s *splitPaneImpl) GetOverlays() []components.Overlay {
+	result := make([]components.Overlay, 0)
+	firstXOffset, firstYOffset, secondXOffset, secondYOffset := s.getChildOffsets()
+	if s.collapsedSide != First {
+		result = append(result, components.GetChildOverlays(s.first, firstXOffset, firstYOffset)...)
+	}
+	if s.collapsedSide != Second {
+		result = append(result, components.GetChildOverlays(s.second, secondXOffset, secondYOffset)...)
+	}
+	return result
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Splits the size along the split (which includes the divider) between the children
+func (s splitPaneImpl) getSizes(totalSize int) (firstSize int, secondSize int) {
+	available := utilities.GetMaxInt(0, totalSize-dividerSize)
+	switch s.collapsedSide {
+	case First:
+		return 0, available
+	case Second:
+		return available, 0
+	}
+
+	firstSize = int(math.Round(s.ratio * float64(available)))
+	firstSize = utilities.GetMinInt(firstSize, available-s.secondMinSizeCache)
+
+	// When there isn't room for both minimums, the first side gets its minimum
+	firstSize = utilities.Clamp(utilities.GetMaxInt(firstSize, s.firstMinSizeCache), 0, available)
+	return firstSize, available - firstSize
+}
+
+func (s splitPaneImpl) getChild(side Side) components.Component {
+	if side == First {
+		return s.first
+	}
+	return s.second
+}
+
+// Collapsed sides only get broadcast messages
+func (s splitPaneImpl) updateChild(side Side, msg tea.Msg, xOffset int, yOffset int) tea.Cmd {
+	if s.collapsedSide == side && !components.IsBroadcastMsg(msg) {
+		return nil
+	}
+	return components.UpdateChild(s.getChild(side), msg, xOffset, yOffset)
+}
+
+// Gives the side the focus if it isn't collapsed & accepts it, returning whether it did
+func (s *splitPaneImpl) focusChild(side Side) bool {
+	return s.collapsedSide != side && components.SetChildFocus(s.getChild(side), true)
+}
+
+// Collapses the side (or expands both, for NoSide), moving the focus off the side if it had it
+func (s *splitPaneImpl) collapse(side Side) {
+	s.collapsedSide = side
+	if side == NoSide || !components.IsChildFocused(s.getChild(side)) {
+		return
+	}
+	components.SetChildFocus(s.getChild(side), false)
+	otherSide := First
+	if side == First {
+		otherSide = Second
+	}
+	s.isFocused = !s.focusChild(otherSide)
+}
+
+func (s splitPaneImpl) getChildHeight(ctx components.LayoutContext, child components.Component, side Side, width int) int {
+	if s.collapsedSide == side || width == 0 {
+		return 0
+	}
+	return components.GetChildContentHeightForGivenWidth(child, ctx, width)
+}
+
+// Gets exactly the given number of lines of the child's view, each exactly the width
+func getChildLines(ctx components.LayoutContext, child components.Component, width int, height int) []string {
+	if height == 0 {
+		return nil
+	}
+	viewLines := make([]string, 0)
+	if width > 0 {
+		viewLines = strings.Split(components.ViewChild(child, ctx, width, height), "\n")
+	}
+
+	result := make([]string, height)
+	for idx := range result {
+		line := ""
+		if idx < len(viewLines) {
+			line = viewLines[idx]
+		}
+		result[idx] = control.FitToWidth(line, width)
+	}
+	return result
+}
+
+// Gets the positions of the children's top-left corners within the pane, as of the last View
+func (s splitPaneImpl) getChildOffsets() (firstXOffset, firstYOffset, secondXOffset, secondYOffset int) {
+	if s.orientation == Vertical {
+		return 0, 0, 0, s.firstSizeCache + dividerSize
+	}
+	return 0, 0, s.firstSizeCache + dividerSize, 0
+}
+
+// Handles pressing, dragging & releasing the divider, returning false if the message had nothing to do with it
+func (s *splitPaneImpl) handleDividerMouse(msg tea.MouseMsg) (tea.Cmd, bool) {
+	// The divider runs across the whole pane
+	position, crossPosition, crossSize := msg.X, msg.Y, s.lastViewHeight
+	if s.orientation == Vertical {
+		position, crossPosition, crossSize = msg.Y, msg.X, s.lastViewWidth
+	}
+
+	switch msg.Type {
+	case tea.MouseLeft:
+		if position == s.firstSizeCache && crossPosition >= 0 && crossPosition < crossSize {
+			s.isDragging = true
+			return nil, true
+		}
+	case tea.MouseMotion:
+		if s.isDragging {
+			s.setDividerPosition(position)
+			return nil, true
+		}
+	case tea.MouseRelease:
+		if s.isDragging {
+			s.isDragging = false
+			s.setDividerPosition(position)
+			return s.getResizedCmd(), true
+		}
+	}
+	return nil, false
+}
+
+func (s *splitPaneImpl) moveDivider(delta int) tea.Cmd {
+	switch {
+	case s.collapsedSide == First && delta > 0:
+		s.collapsedSide = NoSide
+		s.setDividerPosition(utilities.GetMaxInt(s.firstMinSizeCache, s.firstSizeCache+delta))
+	case s.collapsedSide == Second && delta < 0:
+		s.collapsedSide = NoSide
+		available := s.firstSizeCache + s.secondSizeCache
+		s.setDividerPosition(utilities.GetMinInt(available-s.secondMinSizeCache, s.firstSizeCache+delta))
+	default:
+		s.setDividerPosition(s.firstSizeCache + delta)
+	}
+	return s.getResizedCmd()
+}
+
+// Moves the divider to the position (along the split), collapsing a side if the divider is more than halfway past its
+// minimum size
+func (s *splitPaneImpl) setDividerPosition(position int) {
+	available := s.firstSizeCache + s.secondSizeCache
+	if available == 0 {
+		return
+	}
+	position = utilities.Clamp(position, 0, available)
+
+	switch {
+	case position < s.firstMinSizeCache:
+		if position*2 < s.firstMinSizeCache {
+			s.collapse(First)
+			return
+		}
+		position = s.firstMinSizeCache
+	case available-position < s.secondMinSizeCache:
+		if (available-position)*2 < s.secondMinSizeCache {
+			s.collapse(Second)
+			return
+		}
+		position = available - s.secondMinSizeCache
+	}
+	s.collapsedSide = NoSide
+	s.ratio = float64(position) / float64(available)
+	s.firstSizeCache, s.secondSizeCache = position, available-position
+}
+
+func (s *splitPaneImpl) getResizedCmd() tea.Cmd {
+	resizedMsg := ResizedMsg{
+		Pane:          s,
+		Ratio:         s.ratio,
+		CollapsedSide: s.collapsedSide,
+	}
+	return func() tea.Msg {
+		return resizedMsg
+	}
+}
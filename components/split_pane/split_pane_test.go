@@ -0,0 +1,155 @@
+package split_pane
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/components/textinput"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHorizontal(t *testing.T) {
+	pane := New(text.New("hello world"), text.New("foo bar"))
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(9, 19, 1, 2),
+		test_assertions.GetHeightAtWidthAssertions(
+			9, 2,
+			19, 2,
+			25, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(19, 2, "hello    │foo bar  \nworld    │         "),
+		// Neither side goes below its minimum size
+		test_assertions.GetRenderedContentAssertion(9, 2, "hello│foo\nworld│bar"),
+	)
+
+	test_assertions.CheckAll(t, assertions, pane)
+}
+
+func TestVertical(t *testing.T) {
+	pane := New(text.New("a"), text.New("b")).SetOrientation(Vertical).SetRatio(0.25)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(1, 1, 3, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			1, 3,
+			5, 3,
+		),
+		test_assertions.GetRenderedContentAssertion(3, 6, "a  \n───\nb  \n   \n   \n   "),
+	)
+
+	test_assertions.CheckAll(t, assertions, pane)
+}
+
+func TestDragging(t *testing.T) {
+	pane := New(text.New("hello world"), text.New("foo bar"))
+	render(pane, 19)
+
+	// Pressing anywhere but the divider doesn't drag it
+	require.Nil(t, pane.Update(tea.MouseMsg{X: 8, Y: 0, Type: tea.MouseLeft}))
+	require.Nil(t, pane.Update(tea.MouseMsg{X: 12, Y: 0, Type: tea.MouseMotion}))
+	require.Equal(t, 0.5, pane.GetRatio())
+
+	pane.Update(tea.MouseMsg{X: 9, Y: 1, Type: tea.MouseLeft})
+	pane.Update(tea.MouseMsg{X: 12, Y: 1, Type: tea.MouseMotion})
+	msgs := test_assertions.GetMsgs(pane.Update(tea.MouseMsg{X: 14, Y: 1, Type: tea.MouseRelease}))
+	require.Equal(t, []tea.Msg{ResizedMsg{Pane: pane, Ratio: 14.0 / 18.0, CollapsedSide: NoSide}}, msgs)
+	require.Equal(t, "hello world   │foo \n              │bar ", render(pane, 19))
+
+	// Dragging past a side's minimum stops at it, and dragging more than halfway past collapses the side
+	pane.Update(tea.MouseMsg{X: 14, Y: 0, Type: tea.MouseLeft})
+	pane.Update(tea.MouseMsg{X: 3, Y: 0, Type: tea.MouseMotion})
+	require.Equal(t, NoSide, pane.GetCollapsedSide())
+	require.Equal(t, "hello│foo bar      \nworld│             ", render(pane, 19))
+	pane.Update(tea.MouseMsg{X: 2, Y: 0, Type: tea.MouseRelease})
+	require.Equal(t, First, pane.GetCollapsedSide())
+	require.Equal(t, "│foo bar           ", render(pane, 19))
+
+	// The ratio from before collapsing comes back
+	pane.SetCollapsedSide(NoSide)
+	require.Equal(t, "hello│foo bar      \nworld│             ", render(pane, 19))
+}
+
+func TestKeyboardResizing(t *testing.T) {
+	pane := New(text.New("hello world"), text.New("foo bar"))
+	render(pane, 19)
+
+	// Without anything focusable inside, the pane takes the focus itself
+	require.Nil(t, pane.Update(tea.KeyMsg{Type: tea.KeyRight, Alt: true}))
+	pane.SetFocus(true)
+	require.True(t, pane.IsFocused())
+
+	// Plain arrows are left to the children
+	require.Nil(t, pane.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	require.Equal(t, 0.5, pane.GetRatio())
+
+	msgs := test_assertions.GetMsgs(pane.Update(tea.KeyMsg{Type: tea.KeyRight, Alt: true}))
+	require.Equal(t, []tea.Msg{ResizedMsg{Pane: pane, Ratio: 10.0 / 18.0, CollapsedSide: NoSide}}, msgs)
+
+	pane.SetCollapsedSide(Second)
+	render(pane, 19)
+	pane.Update(tea.KeyMsg{Type: tea.KeyLeft, Alt: true})
+	require.Equal(t, NoSide, pane.GetCollapsedSide())
+	require.Equal(t, 15.0/18.0, pane.GetRatio())
+
+	// Focusable children get the focus instead of the pane
+	submit := button.New("Go")
+	paneWithButton := New(text.New("hi"), submit)
+	paneWithButton.SetFocus(true)
+	require.True(t, submit.IsFocused())
+	paneWithButton.SetFocus(false)
+	require.False(t, paneWithButton.IsFocused())
+
+	// Alt & the arrows are left to a focused child too, as inputs use them to jump between words
+	input := textinput.New().SetValue("hello world")
+	paneWithInput := New(text.New("hi"), input)
+	render(paneWithInput, 19)
+	paneWithInput.SetFocus(true)
+	require.True(t, input.IsFocused())
+	paneWithInput.Update(tea.KeyMsg{Type: tea.KeyLeft, Alt: true})
+	require.Equal(t, 0.5, paneWithInput.GetRatio())
+	require.Equal(t, 6, input.GetCursorPosition())
+}
+
+func TestCollapsedSideIsLeftOut(t *testing.T) {
+	first := button.New("One")
+	second := button.New("Two")
+	pane := New(first, second)
+	render(pane, 19)
+	second.SetFocus(true)
+
+	// Collapsing the focused side moves the focus to the other side
+	pane.SetCollapsedSide(Second)
+	require.False(t, second.IsFocused())
+	require.True(t, first.IsFocused())
+	pane.SetFocus(false)
+	pane.SetFocus(true)
+	require.True(t, first.IsFocused())
+	require.False(t, second.IsFocused())
+
+	// Clicks where the collapsed side was don't reach it
+	render(pane, 19)
+	pane.Update(tea.MouseMsg{X: 21, Y: 0, Type: tea.MouseLeft})
+	require.Nil(t, pane.Update(tea.MouseMsg{X: 21, Y: 0, Type: tea.MouseRelease}))
+
+	// Without the other side to take it, the pane keeps the focus itself
+	textPane := New(text.New("hi"), button.New("Go"))
+	textPane.SetFocus(true)
+	textPane.SetCollapsedSide(Second)
+	require.True(t, textPane.IsFocused())
+	require.False(t, components.IsChildFocused(textPane.GetSecond()))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func render(pane SplitPane, width int) string {
+	pane.GetContentMinMax()
+	return pane.View(width, pane.GetContentHeightForGivenWidth(width))
+}
@@ -0,0 +1,26 @@
+package split_pane
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles holds the style of the divider in each of its states
+type Styles struct {
+	Divider lipgloss.Style
+
+	// Used while the pane (or anything in it) has the focus
+	FocusedDivider lipgloss.Style
+
+	// Used while the divider is being dragged
+	DraggingDivider lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Divider:         lipgloss.NewStyle().Foreground(theme.Color(theme.Border)),
+		FocusedDivider:  lipgloss.NewStyle().Foreground(theme.Color(theme.FocusedBorder)),
+		DraggingDivider: lipgloss.NewStyle().Bold(true).Foreground(theme.Color(theme.FocusedBorder)),
+	}
+}
@@ -0,0 +1,157 @@
+package stylebox
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"github.com/muesli/reflow/truncate"
+	"math"
+	"strings"
+)
+
+const (
+	// Labels are set off from the border line by a space on either side...
+	borderLabelPadding = " "
+
+	// ...and always leave at least this many border characters at either end, so the corners stay connected
+	minBorderLabelEdgeWidth = 1
+
+	borderLabelTruncationTail = "…"
+)
+
+// Border labels are a single line
+func sanitizeBorderLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
+
+// lipgloss draws every side when a border is set without picking any sides
+func (s styleboxImpl) isBorderSideDrawn(isSideSet bool) bool {
+	border, hasTop, hasRight, hasBottom, hasLeft := s.getActiveStyle().GetBorder()
+	if border == (lipgloss.Border{}) {
+		return false
+	}
+	return isSideSet || !(hasTop || hasRight || hasBottom || hasLeft)
+}
+
+func (s styleboxImpl) isTopBorderDrawn() bool {
+	return s.isBorderSideDrawn(s.getActiveStyle().GetBorderTop())
+}
+
+func (s styleboxImpl) isBottomBorderDrawn() bool {
+	return s.isBorderSideDrawn(s.getActiveStyle().GetBorderBottom())
+}
+
+// Gets the corners at the ends of a top or bottom border line, which are only there when the side borders are drawn
+func (s styleboxImpl) getBorderCorners(leftCorner string, rightCorner string) (string, string) {
+	if !s.isBorderSideDrawn(s.getActiveStyle().GetBorderLeft()) {
+		leftCorner = ""
+	}
+	if !s.isBorderSideDrawn(s.getActiveStyle().GetBorderRight()) {
+		rightCorner = ""
+	}
+	return leftCorner, rightCorner
+}
+
+// Gets the width the box needs to fit the label in full in its border line, or 0 if there's no label to show
+func (s styleboxImpl) getBorderLabelWidth(label string, isBorderDrawn bool) int {
+	if label == "" || !isBorderDrawn {
+		return 0
+	}
+	style := s.getActiveStyle()
+	border := style.GetBorderStyle()
+	leftCorner, rightCorner := s.getBorderCorners(border.TopLeft, border.TopRight)
+	return style.GetHorizontalMargins() +
+		lipgloss.Width(leftCorner) +
+		lipgloss.Width(rightCorner) +
+		2*minBorderLabelEdgeWidth +
+		2*lipgloss.Width(borderLabelPadding) +
+		lipgloss.Width(label)
+}
+
+// The style is the one the box was rendered with, so the labels get the same border colors
+func (s styleboxImpl) drawBorderLabels(styled string, style lipgloss.Style) string {
+	if s.title == "" && s.footer == "" {
+		return styled
+	}
+	border := style.GetBorderStyle()
+	lines := strings.Split(styled, "\n")
+
+	// The border lines sit inside the margins
+	marginLeft := style.GetMarginLeft()
+	borderLineWidth := lipgloss.Width(styled) - style.GetHorizontalMargins()
+	topIdx := style.GetMarginTop()
+	bottomIdx := len(lines) - 1 - style.GetMarginBottom()
+
+	if s.title != "" && s.isTopBorderDrawn() && topIdx < len(lines) {
+		leftCorner, rightCorner := s.getBorderCorners(border.TopLeft, border.TopRight)
+		lineStyle := lipgloss.NewStyle().
+			Foreground(style.GetBorderTopForeground()).
+			Background(style.GetBorderTopBackground())
+		borderLine := renderBorderLine(
+			borderLineWidth,
+			leftCorner,
+			border.Top,
+			rightCorner,
+			s.title,
+			s.titleAlignment,
+			lineStyle,
+		)
+		lines[topIdx] = components.DrawOver(lines[topIdx], borderLine, marginLeft, 0)
+	}
+
+	if s.footer != "" && s.isBottomBorderDrawn() && bottomIdx >= 0 {
+		leftCorner, rightCorner := s.getBorderCorners(border.BottomLeft, border.BottomRight)
+		lineStyle := lipgloss.NewStyle().
+			Foreground(style.GetBorderBottomForeground()).
+			Background(style.GetBorderBottomBackground())
+		borderLine := renderBorderLine(
+			borderLineWidth,
+			leftCorner,
+			border.Bottom,
+			rightCorner,
+			s.footer,
+			s.footerAlignment,
+			lineStyle,
+		)
+		lines[bottomIdx] = components.DrawOver(lines[bottomIdx], borderLine, marginLeft, 0)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Renders a top or bottom border line of the given width with the label in it, truncating the label to keep the border
+// intact (and leaving it out if there's no room for any of it)
+func renderBorderLine(
+	width int,
+	leftCorner string,
+	edge string,
+	rightCorner string,
+	label string,
+	alignment lipgloss.Position,
+	style lipgloss.Style,
+) string {
+	edgesWidth := width - lipgloss.Width(leftCorner) - lipgloss.Width(rightCorner)
+	paddingWidth := 2 * lipgloss.Width(borderLabelPadding)
+	maxLabelWidth := edgesWidth - 2*minBorderLabelEdgeWidth - paddingWidth
+
+	if maxLabelWidth < 1 {
+		return style.Render(leftCorner + strings.Repeat(edge, utilities.GetMaxInt(0, edgesWidth)) + rightCorner)
+	}
+	if lipgloss.Width(label) > maxLabelWidth {
+		label = truncate.StringWithTail(label, uint(maxLabelWidth), borderLabelTruncationTail)
+	}
+	paddedLabel := borderLabelPadding + label + borderLabelPadding
+
+	// The alignment picks where the label goes between the minimum edges
+	leftoverWidth := edgesWidth - lipgloss.Width(paddedLabel) - 2*minBorderLabelEdgeWidth
+	leftEdgeWidth := minBorderLabelEdgeWidth + int(math.Round(float64(leftoverWidth)*float64(alignment)))
+	rightEdgeWidth := edgesWidth - lipgloss.Width(paddedLabel) - leftEdgeWidth
+
+	return style.Render(
+		leftCorner +
+			strings.Repeat(edge, leftEdgeWidth) +
+			paddedLabel +
+			strings.Repeat(edge, rightEdgeWidth) +
+			rightCorner,
+	)
+}
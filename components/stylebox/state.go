@@ -0,0 +1,26 @@
+package stylebox
+
+// State is an interaction state of the stylebox's contents, which can have its own style
+type State int
+
+const (
+	// Something inside the stylebox has the focus
+	Focused State = iota
+
+	// The mouse was last seen over the stylebox (which needs the program to report mouse motion, e.g. with
+	// tea.WithMouseAllMotion)
+	Hovered
+
+	// The inner component is disabled (i.e. it has an IsDisabled method that returns true)
+	// Unlike the focus, only the direct child counts, since a subtree with some of its controls disabled isn't disabled
+	// as a whole; wrap the disabled control itself in the stylebox to style it
+	Disabled
+)
+
+// The states that apply at once get their styles used in this order, so e.g. a disabled box never looks focused
+var statePrecedence = []State{Disabled, Hovered, Focused}
+
+// Implemented by the components that can be disabled (buttons, checkboxes, etc.)
+type disableable interface {
+	IsDisabled() bool
+}
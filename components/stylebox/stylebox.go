@@ -1,78 +1,287 @@
 package stylebox
 
 import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/theme"
 	"github.com/mieubrisse/box-layout-test/utilities"
+	"github.com/muesli/reflow/ansi"
+	"strings"
 )
 
+// Used to find where the content ends up inside the border & padding (from the Unicode private use area, so it will
+// never collide with anything)
+const contentPositionMarker = "\uE000"
+
 // Stylebox is a box explicitly for controlling style
 // No other elements control style
+// The setters change the box in place (so e.g. a box found with components.FindByID can be updated), returning it so
+// that calls can be chained
 type Stylebox interface {
-	components.Component
+	// Messages & focus get passed through to the inner component (if it's interactive), as do its overlays and the
+	// layout context (with the inherited values added)
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Identifiable
+	components.Container
+
+	SetID(id string) Stylebox
+	SetClasses(classes ...string) Stylebox
 
 	GetStyle() lipgloss.Style
-	// NOTE: all layout-affecting properties (height, width, alignment, margin, inline) are ignored
-	// The only layout-affecting property left in place are border and padding
+	// NOTE: the layout-affecting properties a stylebox can't support (height, width, alignment, inline) are dropped
+	// The only layout-affecting properties left in place are margin, border and padding
 	SetStyle(style lipgloss.Style) Stylebox
+	// Like SetStyle, but returns an error listing the unsupported properties (leaving the stylebox as it was) rather than
+	// dropping them
+	SetStyleStrict(style lipgloss.Style) (Stylebox, error)
+
+	// The title gets drawn into the top border line and the footer into the bottom one (so they only show when that
+	// border is drawn), getting truncated when the box is too narrow for them
+	GetTitle() string
+	SetTitle(title string) Stylebox
+	GetTitleAlignment() lipgloss.Position
+	SetTitleAlignment(alignment lipgloss.Position) Stylebox
+
+	GetFooter() string
+	SetFooter(footer string) Stylebox
+	GetFooterAlignment() lipgloss.Position
+	SetFooterAlignment(alignment lipgloss.Position) Stylebox
+
+	// A state's style replaces the style while the state applies, getting picked at every phase of the layout
+	// The state styles should only differ in ways that don't change the size (e.g. colors), so that the layout doesn't
+	// shift when e.g. the mouse moves over the box
+	// The unsupported properties get dropped, as with SetStyle
+	GetStateStyle(state State) (lipgloss.Style, bool)
+	SetStateStyle(state State, style lipgloss.Style) Stylebox
+
+	// The theme colors get resolved against the active theme every time the box renders, overriding the matching
+	// colors of the style (or of the state's style), so the box follows theme switches
+	GetThemeColors() theme.Colors
+	SetThemeColors(colors theme.Colors) Stylebox
+	GetStateThemeColors(state State) (theme.Colors, bool)
+	SetStateThemeColors(state State, colors theme.Colors) Stylebox
+
+	// Inherited values get added to the layout context that the inner component (and everything inside it) gets, e.g.
+	// with theme.WithTheme to give a subtree its own theme
+	GetInheritedValue(key interface{}) (interface{}, bool)
+	SetInheritedValue(key interface{}, value interface{}) Stylebox
 }
 
 type styleboxImpl struct {
 	component components.Component
 
+	id      string
+	classes []string
+
 	style lipgloss.Style
+
+	title          string
+	titleAlignment lipgloss.Position
+
+	footer          string
+	footerAlignment lipgloss.Position
+
+	stateStyles map[State]lipgloss.Style
+
+	themeColors      theme.Colors
+	stateThemeColors map[State]theme.Colors
+
+	inheritedValues components.LayoutContext
+
+	isHovered bool
+
+	// The size given to the last View, which mouse messages get checked against for hovering
+	lastViewWidth  int
+	lastViewHeight int
 }
 
 func New(component components.Component) Stylebox {
 	return &styleboxImpl{
-		component: component,
-		style:     lipgloss.NewStyle(),
+		component:        component,
+		id:               "",
+		classes:          nil,
+		style:            lipgloss.NewStyle(),
+		title:            "",
+		titleAlignment:   lipgloss.Left,
+		footer:           "",
+		footerAlignment:  lipgloss.Left,
+		stateStyles:      map[State]lipgloss.Style{},
+		themeColors:      theme.Colors{},
+		stateThemeColors: map[State]theme.Colors{},
+		inheritedValues:  components.NewLayoutContext(),
+		isHovered:        false,
+		lastViewWidth:    0,
+		lastViewHeight:   0,
 	}
 }
 
+func (s styleboxImpl) GetID() string {
+	return s.id
+}
+
+func (s *styleboxImpl) SetID(id string) Stylebox {
+	s.id = id
+	return s
+}
+
+func (s styleboxImpl) GetClasses() []string {
+	return s.classes
+}
+
+func (s *styleboxImpl) SetClasses(classes ...string) Stylebox {
+	s.classes = classes
+	return s
+}
+
+func (s styleboxImpl) GetChildComponents() []components.Component {
+	return []components.Component{s.component}
+}
+
 func (s styleboxImpl) GetStyle() lipgloss.Style {
 	return s.style
 }
 
-func (s styleboxImpl) SetStyle(style lipgloss.Style) Stylebox {
-	s.style = style.Copy().
-		UnsetMargins().
-		UnsetAlign().
-		UnsetAlignHorizontal().
-		UnsetAlignVertical().
-		UnsetWidth().
-		UnsetMaxWidth().
-		UnsetHeight().
-		UnsetMaxHeight().
-		UnsetInline()
+func (s *styleboxImpl) SetStyle(style lipgloss.Style) Stylebox {
+	s.style = removeUnsupportedProperties(style)
+	return s
+}
+
+func (s *styleboxImpl) SetStyleStrict(style lipgloss.Style) (Stylebox, error) {
+	if unsupportedProperties := getUnsupportedProperties(style); len(unsupportedProperties) > 0 {
+		return s, fmt.Errorf(
+			"the style has properties that a stylebox doesn't support: %s",
+			strings.Join(unsupportedProperties, ", "),
+		)
+	}
+	return s.SetStyle(style), nil
+}
+
+func (s styleboxImpl) GetTitle() string {
+	return s.title
+}
+
+func (s *styleboxImpl) SetTitle(title string) Stylebox {
+	s.title = sanitizeBorderLabel(title)
+	return s
+}
+
+func (s styleboxImpl) GetTitleAlignment() lipgloss.Position {
+	return s.titleAlignment
+}
+
+func (s *styleboxImpl) SetTitleAlignment(alignment lipgloss.Position) Stylebox {
+	s.titleAlignment = alignment
+	return s
+}
+
+func (s styleboxImpl) GetFooter() string {
+	return s.footer
+}
+
+func (s *styleboxImpl) SetFooter(footer string) Stylebox {
+	s.footer = sanitizeBorderLabel(footer)
+	return s
+}
+
+func (s styleboxImpl) GetFooterAlignment() lipgloss.Position {
+	return s.footerAlignment
+}
+
+func (s *styleboxImpl) SetFooterAlignment(alignment lipgloss.Position) Stylebox {
+	s.footerAlignment = alignment
+	return s
+}
+
+func (s styleboxImpl) GetStateStyle(state State) (lipgloss.Style, bool) {
+	style, found := s.stateStyles[state]
+	return style, found
+}
+
+func (s *styleboxImpl) SetStateStyle(state State, style lipgloss.Style) Stylebox {
+	s.stateStyles[state] = removeUnsupportedProperties(style)
+	return s
+}
+
+func (s styleboxImpl) GetThemeColors() theme.Colors {
+	return s.themeColors
+}
+
+func (s *styleboxImpl) SetThemeColors(colors theme.Colors) Stylebox {
+	s.themeColors = colors
+	return s
+}
+
+func (s styleboxImpl) GetStateThemeColors(state State) (theme.Colors, bool) {
+	colors, found := s.stateThemeColors[state]
+	return colors, found
+}
+
+func (s *styleboxImpl) SetStateThemeColors(state State, colors theme.Colors) Stylebox {
+	s.stateThemeColors[state] = colors
+	return s
+}
+
+func (s styleboxImpl) GetInheritedValue(key interface{}) (interface{}, bool) {
+	return s.inheritedValues.Get(key)
+}
+
+func (s *styleboxImpl) SetInheritedValue(key interface{}, value interface{}) Stylebox {
+	s.inheritedValues = s.inheritedValues.With(key, value)
 	return s
 }
 
 func (s styleboxImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return s.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (s styleboxImpl) GetContentHeightForGivenWidth(width int) int {
+	return s.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (s *styleboxImpl) View(width int, height int) string {
+	return s.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (s styleboxImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
 	// TODO cache the results?
-	innerMinWidth, innerMaxWidth, innerMinHeight, innerMaxHeight := s.component.GetContentMinMax()
+	innerMinWidth, innerMaxWidth, innerMinHeight, innerMaxHeight := components.GetChildContentMinMax(
+		s.component,
+		s.getInnerContext(ctx),
+	)
 
-	minWidth = innerMinWidth + s.style.GetHorizontalFrameSize()
-	maxWidth = innerMaxWidth + s.style.GetHorizontalFrameSize()
+	// The box is made wide enough to show the border labels in full
+	labelsWidth := utilities.GetMaxInt(
+		s.getBorderLabelWidth(s.title, s.isTopBorderDrawn()),
+		s.getBorderLabelWidth(s.footer, s.isBottomBorderDrawn()),
+	)
+	minWidth = utilities.GetMaxInt(labelsWidth, innerMinWidth+s.getExtraWidth())
+	maxWidth = utilities.GetMaxInt(labelsWidth, innerMaxWidth+s.getExtraWidth())
 
-	minHeight = innerMinHeight + s.style.GetVerticalFrameSize()
-	maxHeight = innerMaxHeight + s.style.GetVerticalFrameSize()
+	minHeight = innerMinHeight + s.getExtraHeight()
+	maxHeight = innerMaxHeight + s.getExtraHeight()
 	return
 }
 
-func (s styleboxImpl) GetContentHeightForGivenWidth(width int) int {
-	innerWidth := utilities.GetMaxInt(0, width-s.style.GetHorizontalFrameSize())
-	return s.component.GetContentHeightForGivenWidth(innerWidth) + s.style.GetVerticalFrameSize()
+func (s styleboxImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	innerWidth := utilities.GetMaxInt(0, width-s.getExtraWidth())
+	innerHeight := components.GetChildContentHeightForGivenWidth(s.component, s.getInnerContext(ctx), innerWidth)
+	return innerHeight + s.getExtraHeight()
 }
 
-func (s styleboxImpl) View(width int, height int) string {
+// The theme colors get resolved against the context's theme (see theme.WithTheme), falling back to the active theme
+func (s *styleboxImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	s.lastViewWidth, s.lastViewHeight = width, height
 	if width == 0 || height == 0 {
 		return ""
 	}
 
-	innerWidth := utilities.GetMaxInt(0, width-s.style.GetHorizontalFrameSize())
-	innerHeight := utilities.GetMaxInt(0, height-s.style.GetVerticalFrameSize())
-	innerStr := s.component.View(innerWidth, innerHeight)
+	innerWidth := utilities.GetMaxInt(0, width-s.getExtraWidth())
+	innerHeight := utilities.GetMaxInt(0, height-s.getExtraHeight())
+	innerStr := components.ViewChild(s.component, s.getInnerContext(ctx), innerWidth, innerHeight)
 
 	// First truncate to ensure none of the children have overflowed
 	truncatedInnerStr := lipgloss.NewStyle().
@@ -84,7 +293,8 @@ func (s styleboxImpl) View(width int, height int) string {
 	expandedInnerStr := lipgloss.NewStyle().Width(innerWidth).Height(innerHeight).Render(truncatedInnerStr)
 
 	// Apply our styles...
-	styled := s.style.Render(expandedInnerStr)
+	style := s.getActiveStyleInTheme(theme.FromContext(ctx))
+	styled := s.drawBorderLabels(style.Render(expandedInnerStr), style)
 
 	// ...and then truncate down again in case our styles caused an exceeding of the box
 	result := lipgloss.NewStyle().
@@ -95,15 +305,146 @@ func (s styleboxImpl) View(width int, height int) string {
 	return result
 }
 
+func (s *styleboxImpl) Update(msg tea.Msg) tea.Cmd {
+	if mouseMsg, ok := msg.(tea.MouseMsg); ok {
+		s.isHovered = components.IsMouseMsgInBounds(mouseMsg, s.lastViewWidth, s.lastViewHeight)
+	}
+	xOffset, yOffset := s.getContentOffset()
+	return components.UpdateChild(s.component, msg, xOffset, yOffset)
+}
+
+func (s styleboxImpl) SetFocus(isFocused bool) {
+	components.SetChildFocus(s.component, isFocused)
+}
+
+func (s styleboxImpl) IsFocused() bool {
+	return components.IsChildFocused(s.component)
+}
+
+func (s styleboxImpl) GetOverlays() []components.Overlay {
+	xOffset, yOffset := s.getContentOffset()
+	return components.GetChildOverlays(s.component, xOffset, yOffset)
+}
+
+// GetContentOffset gets where content rendered with the style ends up, relative to the top-left corner of the result
+// (i.e. past the margin, border & padding on the top & left)
+func GetContentOffset(style lipgloss.Style) (xOffset int, yOffset int) {
+	lines := strings.Split(style.Render(contentPositionMarker), "\n")
+	for lineIdx, line := range lines {
+		markerIdx := strings.Index(line, contentPositionMarker)
+		if markerIdx == -1 {
+			continue
+		}
+		return ansi.PrintableRuneWidth(line[:markerIdx]), lineIdx
+	}
+	return 0, 0
+}
+
 // ====================================================================================================
 //
 //	Private Helper Functions
 //
 // ====================================================================================================
+// lipgloss's frame size getters count every border side even when only some of them get drawn, so we measure what
+// rendering actually produces instead (which includes the margins)
 func (s styleboxImpl) getExtraWidth() int {
-	return s.style.GetHorizontalFrameSize()
+	return lipgloss.Width(s.getActiveStyle().Render(""))
 }
 
 func (s styleboxImpl) getExtraHeight() int {
-	return s.style.GetVerticalFrameSize()
+	// An empty string is still one line tall
+	return lipgloss.Height(s.getActiveStyle().Render("")) - 1
+}
+
+// Gets the style for the states that apply right now, with the theme colors resolved against the active theme
+// The theme colors don't affect the size, so this is fine for everything but rendering
+func (s styleboxImpl) getActiveStyle() lipgloss.Style {
+	return s.getActiveStyleInTheme(theme.GetActive())
+}
+
+// A state's style and theme colors are picked separately, so e.g. a focused state can change only the border color
+func (s styleboxImpl) getActiveStyleInTheme(activeTheme theme.Theme) lipgloss.Style {
+	style := s.style
+	for _, state := range statePrecedence {
+		stateStyle, found := s.stateStyles[state]
+		if found && s.isInState(state) {
+			style = stateStyle
+			break
+		}
+	}
+
+	colors := s.themeColors
+	for _, state := range statePrecedence {
+		stateColors, found := s.stateThemeColors[state]
+		if found && s.isInState(state) {
+			colors = stateColors
+			break
+		}
+	}
+	if colors.IsEmpty() {
+		return style
+	}
+	return colors.Apply(style, activeTheme)
+}
+
+// Adds the inherited values on top of what the ancestors set
+func (s styleboxImpl) getInnerContext(ctx components.LayoutContext) components.LayoutContext {
+	return ctx.Merge(s.inheritedValues)
+}
+
+func (s styleboxImpl) isInState(state State) bool {
+	switch state {
+	case Focused:
+		return components.IsChildFocused(s.component)
+	case Hovered:
+		return s.isHovered
+	case Disabled:
+		disableableComponent, ok := s.component.(disableable)
+		return ok && disableableComponent.IsDisabled()
+	}
+	return false
+}
+
+func removeUnsupportedProperties(style lipgloss.Style) lipgloss.Style {
+	return style.Copy().
+		UnsetAlign().
+		UnsetAlignHorizontal().
+		UnsetAlignVertical().
+		UnsetWidth().
+		UnsetMaxWidth().
+		UnsetHeight().
+		UnsetMaxHeight().
+		UnsetInline()
+}
+
+// lipgloss doesn't say which properties are set, so the unsupported ones are detected by having non-default values
+func getUnsupportedProperties(style lipgloss.Style) []string {
+	result := make([]string, 0)
+	if style.GetWidth() != 0 {
+		result = append(result, "width")
+	}
+	if style.GetMaxWidth() != 0 {
+		result = append(result, "max width")
+	}
+	if style.GetHeight() != 0 {
+		result = append(result, "height")
+	}
+	if style.GetMaxHeight() != 0 {
+		result = append(result, "max height")
+	}
+	if style.GetAlignHorizontal() != lipgloss.Left {
+		result = append(result, "horizontal alignment")
+	}
+	if style.GetAlignVertical() != lipgloss.Top {
+		result = append(result, "vertical alignment")
+	}
+	if style.GetInline() {
+		result = append(result, "inline")
+	}
+	return result
+}
+
+// Gets the position of the inner component's top-left corner, relative to the stylebox's top-left corner
+func (s styleboxImpl) getContentOffset() (xOffset int, yOffset int) {
+	return GetContentOffset(s.getActiveStyle())
 }
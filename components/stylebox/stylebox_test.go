@@ -1,9 +1,14 @@
 package stylebox
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/button"
 	"github.com/mieubrisse/box-layout-test/components/test_assertions"
 	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/theme"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -105,7 +110,6 @@ func TestColorStylesMaintainSize(t *testing.T) {
 
 func TestProhibitedStylesAreRemoved(t *testing.T) {
 	prohibitedStyles := []lipgloss.Style{
-		lipgloss.NewStyle().Margin(2),
 		lipgloss.NewStyle().Align(lipgloss.Center),
 		lipgloss.NewStyle().AlignHorizontal(lipgloss.Center),
 		lipgloss.NewStyle().AlignVertical(lipgloss.Center),
@@ -121,3 +125,188 @@ func TestProhibitedStylesAreRemoved(t *testing.T) {
 		test_assertions.CheckAll(t, noChangeAssertion, component)
 	}
 }
+
+// lipgloss's frame size getters count all four border sides even when only some get drawn, so the size has to come from
+// what actually gets drawn
+func TestPartialBorders(t *testing.T) {
+	style := lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false)
+	require.Equal(t, 2, style.GetHorizontalFrameSize())
+
+	component := New(text.New("hi")).SetStyle(style)
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(2, 2, 3, 3),
+		test_assertions.GetHeightAtWidthAssertions(2, 3),
+		test_assertions.GetRenderedContentAssertion(2, 3, "──\nhi\n──"),
+	)
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestBorderLabels(t *testing.T) {
+	style := lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+	component := New(text.New("hi")).SetStyle(style).SetTitle("Pods (12)")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		// The box grows to fit the title
+		test_assertions.GetContentSizeAssertions(15, 15, 3, 3),
+		test_assertions.GetRenderedContentAssertion(15, 3, "┌─ Pods (12) ─┐\n│hi           │\n└─────────────┘"),
+		// The title gets truncated when the box is too narrow, leaving the border intact
+		test_assertions.GetRenderedContentAssertion(10, 3, "┌─ Pod… ─┐\n│hi      │\n└────────┘"),
+		test_assertions.GetRenderedContentAssertion(6, 3, "┌────┐\n│hi  │\n└────┘"),
+	)
+	test_assertions.CheckAll(t, assertions, component)
+
+	alignedComponent := component.
+		SetTitleAlignment(lipgloss.Right).
+		SetFooter("3 selected").
+		SetFooterAlignment(lipgloss.Center)
+	test_assertions.CheckAll(
+		t,
+		test_assertions.GetRenderedContentAssertion(20, 3, "┌────── Pods (12) ─┐\n│hi                │\n└─── 3 selected ───┘"),
+		alignedComponent,
+	)
+
+	// Labels only show in borders that are drawn
+	topOnlyComponent := New(text.New("hi")).SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false, false)).
+		SetTitle("Pods").
+		SetFooter("ignored")
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(8, 8, 2, 2),
+		test_assertions.GetRenderedContentAssertion(8, 2, "─ Pods ─\nhi      "),
+	)
+	test_assertions.CheckAll(t, assertions, topOnlyComponent)
+}
+
+func TestMargins(t *testing.T) {
+	style := lipgloss.NewStyle().Margin(1, 2).Border(lipgloss.NormalBorder())
+	component := New(text.New("hi")).SetStyle(style)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(8, 8, 5, 5),
+		test_assertions.GetHeightAtWidthAssertions(8, 5),
+		test_assertions.GetRenderedContentAssertion(8, 5, "        \n  ┌──┐  \n  │hi│  \n  └──┘  \n        "),
+	)
+	test_assertions.CheckAll(t, assertions, component)
+
+	// Border labels stay inside the margins
+	titledComponent := component.SetTitle("T")
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(11, 11, 5, 5),
+		test_assertions.GetRenderedContentAssertion(11, 5, "           \n  ┌─ T ─┐  \n  │hi   │  \n  └─────┘  \n           "),
+	)
+	test_assertions.CheckAll(t, assertions, titledComponent)
+}
+
+func TestContentOffset(t *testing.T) {
+	xOffset, yOffset := GetContentOffset(lipgloss.NewStyle().Margin(1, 2).Border(lipgloss.NormalBorder()).Padding(0, 1))
+	require.Equal(t, 4, xOffset)
+	require.Equal(t, 2, yOffset)
+
+	// Only the top & left count
+	xOffset, yOffset = GetContentOffset(lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, true, true, false))
+	require.Equal(t, 0, xOffset)
+	require.Equal(t, 0, yOffset)
+}
+
+func TestStrictStyle(t *testing.T) {
+	component := New(text.New("hi"))
+
+	_, err := component.SetStyleStrict(lipgloss.NewStyle().Width(10).Align(lipgloss.Center).Inline(true))
+	require.EqualError(
+		t,
+		err,
+		"the style has properties that a stylebox doesn't support: width, horizontal alignment, inline",
+	)
+
+	strictComponent, err := component.SetStyleStrict(lipgloss.NewStyle().Margin(1).Padding(1).Bold(true))
+	require.NoError(t, err)
+	test_assertions.CheckAll(t, test_assertions.GetContentSizeAssertions(6, 6, 5, 5), strictComponent)
+}
+
+func TestStateStyles(t *testing.T) {
+	submit := button.New("Go")
+	component := New(submit).
+		SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder())).
+		SetStateStyle(Focused, lipgloss.NewStyle().Border(lipgloss.ThickBorder())).
+		SetStateStyle(Hovered, lipgloss.NewStyle().Border(lipgloss.DoubleBorder())).
+		SetStateStyle(Disabled, lipgloss.NewStyle().Border(lipgloss.RoundedBorder()))
+
+	require.Equal(t, "┌──────┐\n│[ Go ]│\n└──────┘", render(component, 8))
+
+	// The focus can be anywhere inside
+	component.SetFocus(true)
+	require.Equal(t, "┏━━━━━━┓\n┃[ Go ]┃\n┗━━━━━━┛", render(component, 8))
+
+	component.Update(tea.MouseMsg{X: 1, Y: 1, Type: tea.MouseMotion})
+	require.Equal(t, "╔══════╗\n║[ Go ]║\n╚══════╝", render(component, 8))
+	component.Update(tea.MouseMsg{X: 8, Y: 1, Type: tea.MouseMotion})
+	require.Equal(t, "┏━━━━━━┓\n┃[ Go ]┃\n┗━━━━━━┛", render(component, 8))
+
+	submit.SetDisabled(true)
+	component.Update(tea.MouseMsg{X: 1, Y: 1, Type: tea.MouseMotion})
+	require.Equal(t, "╭──────╮\n│[ Go ]│\n╰──────╯", render(component, 8))
+
+	// Only the direct child counts for being disabled, unlike the focus
+	wrapper := New(New(submit)).
+		SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder())).
+		SetStateStyle(Focused, lipgloss.NewStyle().Border(lipgloss.ThickBorder())).
+		SetStateStyle(Disabled, lipgloss.NewStyle().Border(lipgloss.RoundedBorder()))
+	require.Equal(t, "┌──────┐\n│[ Go ]│\n└──────┘", render(wrapper, 8))
+	submit.SetDisabled(false)
+	wrapper.SetFocus(true)
+	require.Equal(t, "┏━━━━━━┓\n┃[ Go ]┃\n┗━━━━━━┛", render(wrapper, 8))
+
+	// Setting a state style changes the box itself
+	original := New(submit)
+	original.SetStateStyle(Focused, lipgloss.NewStyle().Bold(true))
+	_, found := original.GetStateStyle(Focused)
+	require.True(t, found)
+}
+
+func TestFoundBoxUpdatesInPlace(t *testing.T) {
+	border := lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+	root := New(New(text.New("hi")).SetID("pods").SetStyle(border).SetTitle("Pods (1)"))
+	require.Equal(t, "┌─ Pods (1) ─┐\n│hi          │\n└────────────┘", render(root, 14))
+
+	found, ok := components.FindByID(root, "pods")
+	require.True(t, ok)
+	found.(Stylebox).SetTitle("Pods (12)")
+	require.Equal(t, "┌─ Pods (12) ─┐\n│hi           │\n└─────────────┘", render(root, 15))
+}
+
+func TestThemeColors(t *testing.T) {
+	defer theme.SetActive(theme.GetActive())
+
+	submit := button.New("Go")
+	component := New(submit).
+		SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder())).
+		SetThemeColors(theme.Colors{Foreground: theme.Text, BorderForeground: theme.Border}).
+		SetStateThemeColors(Focused, theme.Colors{BorderForeground: theme.FocusedBorder})
+
+	// The colors don't change the size
+	require.Equal(t, "┌──────┐\n│[ Go ]│\n└──────┘", render(component, 8))
+
+	// The tokens get resolved against whichever theme is active at the time
+	theme.SetActive(theme.Light())
+	activeStyle := component.(*styleboxImpl).getActiveStyle()
+	require.Equal(t, theme.Light().Color(theme.Text), activeStyle.GetForeground())
+	require.Equal(t, theme.Light().Color(theme.Border), activeStyle.GetBorderTopForeground())
+
+	theme.SetActive(theme.Dark())
+	activeStyle = component.(*styleboxImpl).getActiveStyle()
+	require.Equal(t, theme.Dark().Color(theme.Border), activeStyle.GetBorderTopForeground())
+
+	// A state's colors replace the colors while it applies
+	component.SetFocus(true)
+	activeStyle = component.(*styleboxImpl).getActiveStyle()
+	require.Equal(t, theme.Dark().Color(theme.FocusedBorder), activeStyle.GetBorderTopForeground())
+	require.Equal(t, lipgloss.NoColor{}, activeStyle.GetForeground())
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func render(component Stylebox, width int) string {
+	component.GetContentMinMax()
+	return component.View(width, component.GetContentHeightForGivenWidth(width))
+}
@@ -0,0 +1,35 @@
+package tabs
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles controls how each part of the tab strip gets styled
+type Styles struct {
+	Tab lipgloss.Style
+
+	ActiveTab lipgloss.Style
+
+	// Used for the active tab while the tab strip has the focus
+	FocusedActiveTab lipgloss.Style
+
+	// Used for the lines between the tabs
+	Separator lipgloss.Style
+
+	// Used for the scroll arrows shown when the tabs don't fit, depending on whether there's anything to scroll to
+	Arrow         lipgloss.Style
+	DisabledArrow lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Tab:              lipgloss.NewStyle(),
+		ActiveTab:        lipgloss.NewStyle().Bold(true).Underline(true),
+		FocusedActiveTab: lipgloss.NewStyle().Bold(true).Underline(true).Foreground(theme.Color(theme.Primary)),
+		Separator:        lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)),
+		Arrow:            lipgloss.NewStyle(),
+		DisabledArrow:    lipgloss.NewStyle().Foreground(theme.Color(theme.Disabled)),
+	}
+}
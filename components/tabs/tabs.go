@@ -0,0 +1,496 @@
+package tabs
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+const (
+	separator = "│"
+
+	leftArrow  = "‹"
+	rightArrow = "›"
+
+	// The tab strip is a single line above the panel
+	stripHeight = 1
+)
+
+// Tabs shows a strip of tab titles above the panel of the active tab
+// Only the active panel takes part in the layout (unless the size is reserved; see SetSizeReserved) and only it gets
+// rendered, so hidden panels cost nothing
+// When the titles don't fit they scroll to keep the active tab in view, with arrows at the ends of the strip
+// While the strip is focused, Left & Right switch tabs and Down moves the focus into the panel; Ctrl+PgUp & Ctrl+PgDown
+// switch tabs from anywhere inside
+type Tabs interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.OverlayProvider
+	components.Container
+
+	AddTab(title string, panel components.Component) Tabs
+	GetNumTabs() int
+	GetTitle(idx int) string
+	GetPanel(idx int) components.Component
+
+	// Setting the active tab programmatically doesn't send a ChangedMsg
+	GetActiveIndex() int
+	SetActiveIndex(idx int) Tabs
+
+	// When reserved, the size is big enough for the biggest panel, so that the layout doesn't jump when switching tabs
+	IsSizeReserved() bool
+	SetSizeReserved(isReserved bool) Tabs
+
+	GetStyles() Styles
+	SetStyles(styles Styles) Tabs
+}
+
+// ChangedMsg is sent when the user switches tabs
+type ChangedMsg struct {
+	Tabs        Tabs
+	ActiveIndex int
+}
+
+type tab struct {
+	title string
+	panel components.Component
+}
+
+type tabsImpl struct {
+	tabs []tab
+
+	activeIdx int
+
+	isSizeReserved bool
+
+	// Whether the strip has the focus (as opposed to something in the active panel)
+	isStripFocused bool
+
+	// The first tab shown in the strip when the tabs don't all fit
+	firstVisibleIdx int
+
+	// Nil for the default styles
+	styles *Styles
+
+	// Where each tab got drawn in the strip in the last View, for finding which was clicked
+	tabPositionsCache []tabPosition
+
+	// Whether the scroll arrows got shown in the last View, and how wide the strip was
+	hasArrowsCache bool
+	lastViewWidth  int
+}
+
+type tabPosition struct {
+	idx   int
+	x     int
+	width int
+}
+
+func New() Tabs {
+	return &tabsImpl{
+		tabs:              make([]tab, 0),
+		activeIdx:         0,
+		isSizeReserved:    false,
+		isStripFocused:    false,
+		firstVisibleIdx:   0,
+		styles:            nil,
+		tabPositionsCache: nil,
+		hasArrowsCache:    false,
+		lastViewWidth:     0,
+	}
+}
+
+func (t *tabsImpl) AddTab(title string, panel components.Component) Tabs {
+	t.tabs = append(t.tabs, tab{
+		title: strings.Join(strings.Fields(title), " "),
+		panel: panel,
+	})
+	return t
+}
+
+func (t tabsImpl) GetNumTabs() int {
+	return len(t.tabs)
+}
+
+func (t tabsImpl) GetTitle(idx int) string {
+	return t.tabs[idx].title
+}
+
+func (t tabsImpl) GetPanel(idx int) components.Component {
+	return t.tabs[idx].panel
+}
+
+// The panels of all the tabs, including the inactive ones
+func (t tabsImpl) GetChildComponents() []components.Component {
+	result := make([]components.Component, len(t.tabs))
+	for idx, tab := range t.tabs {
+		result[idx] = tab.panel
+	}
+	return result
+}
+
+func (t tabsImpl) GetActiveIndex() int {
+	return t.activeIdx
+}
+
+func (t *tabsImpl) SetActiveIndex(idx int) Tabs {
+	if len(t.tabs) == 0 {
+		return t
+	}
+	idx = utilities.Clamp(idx, 0, len(t.tabs)-1)
+	if idx == t.activeIdx {
+		return t
+	}
+
+	// The focus can't stay in a panel that's no longer shown
+	if components.IsChildFocused(t.tabs[t.activeIdx].panel) {
+		components.SetChildFocus(t.tabs[t.activeIdx].panel, false)
+		t.isStripFocused = true
+	}
+	t.activeIdx = idx
+	return t
+}
+
+func (t tabsImpl) IsSizeReserved() bool {
+	return t.isSizeReserved
+}
+
+func (t *tabsImpl) SetSizeReserved(isReserved bool) Tabs {
+	t.isSizeReserved = isReserved
+	return t
+}
+
+func (t tabsImpl) GetStyles() Styles {
+	if t.styles == nil {
+		return DefaultStyles()
+	}
+	return *t.styles
+}
+
+func (t *tabsImpl) SetStyles(styles Styles) Tabs {
+	t.styles = &styles
+	return t
+}
+
+func (t *tabsImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return t.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (t *tabsImpl) GetContentHeightForGivenWidth(width int) int {
+	return t.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (t *tabsImpl) View(width int, height int) string {
+	return t.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (t *tabsImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	if len(t.tabs) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	// At the narrowest, the strip shows one tab between the arrows
+	maxTabWidth := 0
+	for idx := range t.tabs {
+		maxTabWidth = utilities.GetMaxInt(maxTabWidth, t.getTabWidth(idx))
+	}
+	stripMaxWidth := t.getStripWidth()
+	stripMinWidth := stripMaxWidth
+	if len(t.tabs) > 1 {
+		stripMinWidth = utilities.GetMinInt(stripMaxWidth, lipgloss.Width(leftArrow)+maxTabWidth+lipgloss.Width(rightArrow))
+	}
+
+	panelMinWidth, panelMaxWidth, panelMinHeight, panelMaxHeight := 0, 0, 0, 0
+	for _, panel := range t.getLaidOutPanels() {
+		childMinWidth, childMaxWidth, childMinHeight, childMaxHeight := components.GetChildContentMinMax(panel, ctx)
+		panelMinWidth = utilities.GetMaxInt(panelMinWidth, childMinWidth)
+		panelMaxWidth = utilities.GetMaxInt(panelMaxWidth, childMaxWidth)
+		panelMinHeight = utilities.GetMaxInt(panelMinHeight, childMinHeight)
+		panelMaxHeight = utilities.GetMaxInt(panelMaxHeight, childMaxHeight)
+	}
+
+	minWidth = utilities.GetMaxInt(stripMinWidth, panelMinWidth)
+	maxWidth = utilities.GetMaxInt(stripMaxWidth, panelMaxWidth)
+	minHeight = stripHeight + panelMinHeight
+	maxHeight = stripHeight + panelMaxHeight
+	return
+}
+
+func (t *tabsImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 || len(t.tabs) == 0 {
+		return 0
+	}
+	panelHeight := 0
+	for _, panel := range t.getLaidOutPanels() {
+		panelHeight = utilities.GetMaxInt(panelHeight, components.GetChildContentHeightForGivenWidth(panel, ctx, width))
+	}
+	return stripHeight + panelHeight
+}
+
+func (t *tabsImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	t.lastViewWidth = width
+	if width == 0 || height == 0 || len(t.tabs) == 0 {
+		t.tabPositionsCache = nil
+		return ""
+	}
+
+	lines := []string{t.renderStrip(width)}
+	if height > stripHeight {
+		panelView := components.ViewChild(t.tabs[t.activeIdx].panel, ctx, width, height-stripHeight)
+		lines = append(lines, strings.Split(panelView, "\n")...)
+	}
+	for idx, line := range lines {
+		lines[idx] = control.FitToWidth(line, width)
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+func (t *tabsImpl) Update(msg tea.Msg) tea.Cmd {
+	if len(t.tabs) == 0 {
+		return nil
+	}
+	activePanel := t.tabs[t.activeIdx].panel
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !t.IsFocused() {
+			break
+		}
+		switch msg.Type {
+		case tea.KeyCtrlPgUp:
+			return t.switchTab(t.activeIdx - 1)
+		case tea.KeyCtrlPgDown:
+			return t.switchTab(t.activeIdx + 1)
+		}
+		if !t.isStripFocused {
+			break
+		}
+		switch msg.Type {
+		case tea.KeyLeft:
+			return t.switchTab(t.activeIdx - 1)
+		case tea.KeyRight:
+			return t.switchTab(t.activeIdx + 1)
+		case tea.KeyHome:
+			return t.switchTab(0)
+		case tea.KeyEnd:
+			return t.switchTab(len(t.tabs) - 1)
+		case tea.KeyDown:
+			if components.SetChildFocus(activePanel, true) {
+				t.isStripFocused = false
+			}
+			return nil
+		}
+	case tea.MouseMsg:
+		// Only the active panel is on the screen, so only it can get mouse messages (including the ones outside it, which
+		// e.g. close dropdowns)
+		return tea.Batch(t.handleStripMouse(msg), components.UpdateChild(activePanel, msg, 0, stripHeight))
+	}
+
+	if !components.IsBroadcastMsg(msg) {
+		return components.UpdateChild(activePanel, msg, 0, stripHeight)
+	}
+	cmds := make([]tea.Cmd, len(t.tabs))
+	for idx, tab := range t.tabs {
+		cmds[idx] = components.UpdateChild(tab.panel, msg, 0, stripHeight)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (t *tabsImpl) SetFocus(isFocused bool) {
+	t.isStripFocused = isFocused && len(t.tabs) > 0
+	if !isFocused {
+		for _, tab := range t.tabs {
+			components.SetChildFocus(tab.panel, false)
+		}
+	}
+}
+
+func (t tabsImpl) IsFocused() bool {
+	return t.isStripFocused || (len(t.tabs) > 0 && components.IsChildFocused(t.tabs[t.activeIdx].panel))
+}
+
+func (t *tabsImpl) GetOverlays() []components.Overlay {
+	if len(t.tabs) == 0 {
+		return nil
+	}
+	return components.GetChildOverlays(t.tabs[t.activeIdx].panel, 0, stripHeight)
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Gets the panels that take part in the layout
+func (t tabsImpl) getLaidOutPanels() []components.Component {
+	if !t.isSizeReserved {
+		return []components.Component{t.tabs[t.activeIdx].panel}
+	}
+	result := make([]components.Component, len(t.tabs))
+	for idx, tab := range t.tabs {
+		result[idx] = tab.panel
+	}
+	return result
+}
+
+func (t tabsImpl) getTabLabel(idx int) string {
+	return " " + t.tabs[idx].title + " "
+}
+
+func (t tabsImpl) getTabWidth(idx int) int {
+	return lipgloss.Width(t.getTabLabel(idx))
+}
+
+// Gets the width of the strip with all the tabs shown
+func (t tabsImpl) getStripWidth() int {
+	result := 0
+	for idx := range t.tabs {
+		if idx > 0 {
+			result += lipgloss.Width(separator)
+		}
+		result += t.getTabWidth(idx)
+	}
+	return result
+}
+
+// Works out which tabs fit in the strip, scrolling it so the active tab is shown, and caches where they went
+func (t *tabsImpl) layOutStrip(width int) {
+	t.hasArrowsCache = t.getStripWidth() > width
+	availableWidth := width
+	startX := 0
+	if t.hasArrowsCache {
+		availableWidth = utilities.GetMaxInt(0, width-lipgloss.Width(leftArrow)-lipgloss.Width(rightArrow))
+		startX = lipgloss.Width(leftArrow)
+	} else {
+		t.firstVisibleIdx = 0
+	}
+
+	t.firstVisibleIdx = utilities.Clamp(t.firstVisibleIdx, 0, len(t.tabs)-1)
+	if t.activeIdx < t.firstVisibleIdx {
+		t.firstVisibleIdx = t.activeIdx
+	}
+	for t.firstVisibleIdx < t.activeIdx && t.getVisibleTabsWidth(t.firstVisibleIdx, t.activeIdx) > availableWidth {
+		t.firstVisibleIdx++
+	}
+
+	t.tabPositionsCache = make([]tabPosition, 0)
+	x := startX
+	for idx := t.firstVisibleIdx; idx < len(t.tabs); idx++ {
+		tabWidth := t.getTabWidth(idx)
+		if idx > t.firstVisibleIdx {
+			x += lipgloss.Width(separator)
+		}
+
+		// The first tab is always shown, even if it has to be cut off
+		if x+tabWidth > startX+availableWidth && idx > t.firstVisibleIdx {
+			break
+		}
+		t.tabPositionsCache = append(t.tabPositionsCache, tabPosition{
+			idx:   idx,
+			x:     x,
+			width: utilities.GetMinInt(tabWidth, startX+availableWidth-x),
+		})
+		x += tabWidth
+	}
+}
+
+// Gets the width the tabs between the indexes (inclusive) take up in the strip
+func (t tabsImpl) getVisibleTabsWidth(firstIdx int, lastIdx int) int {
+	result := 0
+	for idx := firstIdx; idx <= lastIdx; idx++ {
+		if idx > firstIdx {
+			result += lipgloss.Width(separator)
+		}
+		result += t.getTabWidth(idx)
+	}
+	return result
+}
+
+func (t *tabsImpl) renderStrip(width int) string {
+	t.layOutStrip(width)
+
+	var result strings.Builder
+	if t.hasArrowsCache {
+		result.WriteString(t.getArrowStyle(t.firstVisibleIdx > 0).Render(leftArrow))
+	}
+	for positionIdx, position := range t.tabPositionsCache {
+		if positionIdx > 0 {
+			result.WriteString(t.GetStyles().Separator.Render(separator))
+		}
+		style := t.GetStyles().Tab
+		if position.idx == t.activeIdx {
+			style = t.GetStyles().ActiveTab
+			if t.isStripFocused {
+				style = t.GetStyles().FocusedActiveTab
+			}
+		}
+		result.WriteString(style.Render(control.FitToWidth(t.getTabLabel(position.idx), position.width)))
+	}
+	if !t.hasArrowsCache {
+		return result.String()
+	}
+
+	rightArrowX := width - lipgloss.Width(rightArrow)
+	strip := control.FitToWidth(result.String(), rightArrowX)
+	return strip + t.getArrowStyle(t.getLastVisibleIdx() < len(t.tabs)-1).Render(rightArrow)
+}
+
+func (t tabsImpl) getArrowStyle(isEnabled bool) lipgloss.Style {
+	if isEnabled {
+		return t.GetStyles().Arrow
+	}
+	return t.GetStyles().DisabledArrow
+}
+
+func (t tabsImpl) getLastVisibleIdx() int {
+	if len(t.tabPositionsCache) == 0 {
+		return t.firstVisibleIdx
+	}
+	return t.tabPositionsCache[len(t.tabPositionsCache)-1].idx
+}
+
+// Clicking a tab switches to it, and clicking an arrow switches to the tab on that side (scrolling the strip to it)
+func (t *tabsImpl) handleStripMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft || !components.IsMouseMsgInBounds(msg, t.lastViewWidth, stripHeight) {
+		return nil
+	}
+
+	if t.hasArrowsCache {
+		if msg.X < lipgloss.Width(leftArrow) {
+			return t.switchTab(t.activeIdx - 1)
+		}
+		if msg.X >= t.lastViewWidth-lipgloss.Width(rightArrow) {
+			return t.switchTab(t.activeIdx + 1)
+		}
+	}
+
+	for _, position := range t.tabPositionsCache {
+		if msg.X >= position.x && msg.X < position.x+position.width {
+			// The focus moves to the strip, even when the tab clicked is already the active one
+			components.SetChildFocus(t.tabs[t.activeIdx].panel, false)
+			t.SetFocus(true)
+			return t.switchTab(position.idx)
+		}
+	}
+	return nil
+}
+
+func (t *tabsImpl) switchTab(idx int) tea.Cmd {
+	previousIdx := t.activeIdx
+	t.SetActiveIndex(idx)
+	if t.activeIdx == previousIdx {
+		return nil
+	}
+	changedMsg := ChangedMsg{
+		Tabs:        t,
+		ActiveIndex: t.activeIdx,
+	}
+	return func() tea.Msg {
+		return changedMsg
+	}
+}
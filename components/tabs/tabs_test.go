@@ -0,0 +1,195 @@
+package tabs
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/button"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/components/textinput"
+	"github.com/mieubrisse/box-layout-test/theme"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const longText = "aa bb cc dd ee ff gg hh ii jj kk ll"
+
+func TestOnlyActivePanelIsLaidOut(t *testing.T) {
+	tabs := New().
+		AddTab("One", text.New("hello world")).
+		AddTab("Two", text.New(longText))
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 11, 2, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			7, 3,
+			11, 2,
+		),
+		test_assertions.GetRenderedContentAssertion(11, 2, " One │ Two \nhello world"),
+		// The strip scrolls when the tabs don't fit
+		test_assertions.GetRenderedContentAssertion(7, 3, "‹ One ›\nhello  \nworld  "),
+	)
+	test_assertions.CheckAll(t, assertions, tabs)
+
+	tabs.SetActiveIndex(1)
+	test_assertions.CheckAll(t, test_assertions.GetContentSizeAssertions(7, 35, 2, 13), tabs)
+	test_assertions.CheckAll(t, test_assertions.GetRenderedContentAssertion(7, 2, "‹ Two ›\naa bb  "), tabs)
+}
+
+func TestReservedSize(t *testing.T) {
+	tabs := New().
+		AddTab("One", text.New("hello world")).
+		AddTab("Two", text.New(longText)).
+		SetSizeReserved(true)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 35, 2, 13),
+		test_assertions.GetHeightAtWidthAssertions(
+			7, 7,
+			35, 2,
+		),
+		// The active panel is the only one shown, in the room for the biggest
+		test_assertions.GetRenderedContentAssertion(11, 4, " One │ Two \nhello world\n           \n           "),
+	)
+	test_assertions.CheckAll(t, assertions, tabs)
+}
+
+func TestKeyboardSwitching(t *testing.T) {
+	submit := button.New("Go")
+	tabs := New().
+		AddTab("One", text.New("hello")).
+		AddTab("Two", submit).
+		AddTab("Three", text.New("bye"))
+
+	// Keys do nothing until the tabs are focused
+	require.Nil(t, tabs.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	require.Equal(t, 0, tabs.GetActiveIndex())
+
+	tabs.SetFocus(true)
+	require.True(t, tabs.IsFocused())
+	require.Equal(t, []tea.Msg{ChangedMsg{Tabs: tabs, ActiveIndex: 1}}, test_assertions.GetMsgs(tabs.Update(tea.KeyMsg{Type: tea.KeyRight})))
+
+	// Down moves the focus into the panel, where the arrow keys are left to the panel
+	tabs.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, submit.IsFocused())
+	require.True(t, tabs.IsFocused())
+	require.Nil(t, tabs.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	require.Equal(t, 1, tabs.GetActiveIndex())
+
+	// Switching away from the panel moves the focus back to the strip
+	require.Equal(t, []tea.Msg{ChangedMsg{Tabs: tabs, ActiveIndex: 2}}, test_assertions.GetMsgs(tabs.Update(tea.KeyMsg{Type: tea.KeyCtrlPgDown})))
+	require.False(t, submit.IsFocused())
+	require.True(t, tabs.IsFocused())
+
+	// Switching past the ends does nothing
+	require.Nil(t, tabs.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	tabs.Update(tea.KeyMsg{Type: tea.KeyHome})
+	require.Equal(t, 0, tabs.GetActiveIndex())
+
+	// Panels that can't take the focus leave it on the strip
+	tabs.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, tabs.IsFocused())
+
+	tabs.SetFocus(false)
+	require.False(t, tabs.IsFocused())
+}
+
+func TestMouseSwitching(t *testing.T) {
+	tabs := New().
+		AddTab("One", text.New("hello")).
+		AddTab("Two", text.New("there")).
+		AddTab("Three", text.New("bye"))
+
+	require.Equal(t, " One │ Two │ Three \nhello              ", render(tabs, 19))
+	require.Equal(t, []tea.Msg{ChangedMsg{Tabs: tabs, ActiveIndex: 1}}, test_assertions.GetMsgs(tabs.Update(tea.MouseMsg{X: 8, Y: 0, Type: tea.MouseLeft})))
+	require.True(t, tabs.IsFocused())
+
+	// The arrows switch to the next tab on their side
+	require.Equal(t, "‹ Two  ›\nthere   ", render(tabs, 8))
+	tabs.Update(tea.MouseMsg{X: 7, Y: 0, Type: tea.MouseLeft})
+	require.Equal(t, 2, tabs.GetActiveIndex())
+	require.Equal(t, "‹ Three›\nbye     ", render(tabs, 8))
+	tabs.Update(tea.MouseMsg{X: 0, Y: 0, Type: tea.MouseLeft})
+	require.Equal(t, 1, tabs.GetActiveIndex())
+
+	// Clicks in the panel don't switch tabs
+	require.Nil(t, tabs.Update(tea.MouseMsg{X: 1, Y: 1, Type: tea.MouseLeft}))
+	require.Equal(t, 1, tabs.GetActiveIndex())
+
+	// Clicking the active tab takes the focus from its panel
+	input := textinput.New()
+	inputTabs := New().
+		AddTab("One", input).
+		AddTab("Two", text.New("there"))
+	render(inputTabs, 11)
+	inputTabs.SetFocus(true)
+	inputTabs.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, input.IsFocused())
+	require.Nil(t, inputTabs.Update(tea.MouseMsg{X: 1, Y: 0, Type: tea.MouseLeft}))
+	require.False(t, input.IsFocused())
+	require.True(t, inputTabs.IsFocused())
+	inputTabs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	require.Equal(t, "", input.GetValue())
+	inputTabs.Update(tea.KeyMsg{Type: tea.KeyRight})
+	require.Equal(t, 1, inputTabs.GetActiveIndex())
+}
+
+func TestHiddenPanelsOnlyGetBroadcasts(t *testing.T) {
+	hidden := &msgRecorder{}
+	tabs := New().
+		AddTab("One", text.New("hello")).
+		AddTab("Two", hidden)
+
+	type tickMsg struct{}
+	tabs.Update(tickMsg{})
+	tabs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	tabs.Update(tea.MouseMsg{X: 0, Y: 1, Type: tea.MouseLeft})
+	require.Equal(t, []tea.Msg{tickMsg{}}, hidden.msgs)
+}
+
+func TestContextReachesPanel(t *testing.T) {
+	tabs := New().AddTab("One", test_assertions.ThemeNameComponent{})
+
+	ctx := theme.WithTheme(components.NewLayoutContext(), theme.New("mine", nil))
+	tabs.GetContentMinMaxInContext(ctx)
+	require.Equal(t, " One \nmine ", tabs.ViewInContext(ctx, 5, tabs.GetContentHeightForGivenWidthInContext(ctx, 5)))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func render(tabs Tabs, width int) string {
+	tabs.GetContentMinMax()
+	return tabs.View(width, tabs.GetContentHeightForGivenWidth(width))
+}
+
+// Keeps the messages it gets
+type msgRecorder struct {
+	msgs []tea.Msg
+}
+
+func (r *msgRecorder) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return 0, 0, 0, 0
+}
+
+func (r *msgRecorder) GetContentHeightForGivenWidth(width int) int {
+	return 0
+}
+
+func (r *msgRecorder) View(width int, height int) string {
+	return ""
+}
+
+func (r *msgRecorder) Update(msg tea.Msg) tea.Cmd {
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+
+func (r *msgRecorder) SetFocus(isFocused bool) {}
+
+func (r *msgRecorder) IsFocused() bool {
+	return false
+}
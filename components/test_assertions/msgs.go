@@ -0,0 +1,23 @@
+package test_assertions
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// GetMsgs runs the command and gets the messages it produces, flattening batches, so tests can check what a component
+// sent without caring how the commands were combined
+func GetMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var result []tea.Msg
+	for _, batchedCmd := range batch {
+		result = append(result, GetMsgs(batchedCmd)...)
+	}
+	return result
+}
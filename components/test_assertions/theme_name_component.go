@@ -0,0 +1,36 @@
+package test_assertions
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// ThemeNameComponent shows the name of the theme in the layout context it gets, so tests can check that a container
+// passes the context on to its children
+type ThemeNameComponent struct{}
+
+func (c ThemeNameComponent) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return c.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (c ThemeNameComponent) GetContentHeightForGivenWidth(width int) int {
+	return c.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (c ThemeNameComponent) View(width int, height int) string {
+	return c.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (c ThemeNameComponent) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	nameWidth := lipgloss.Width(theme.FromContext(ctx).GetName())
+	return nameWidth, nameWidth, 1, 1
+}
+
+func (c ThemeNameComponent) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	return 1
+}
+
+func (c ThemeNameComponent) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	return theme.FromContext(ctx).GetName()
+}
@@ -0,0 +1,180 @@
+package text
+
+import (
+	"strings"
+)
+
+// Controls what happens to control characters & terminal escape sequences in the text
+// This is useful for displaying untrusted text (e.g. logs), which could otherwise corrupt the terminal and throw
+// off the width calculations
+type SanitizationPolicy int
+
+const (
+	// The text is used as-is
+	SanitizeNone SanitizationPolicy = iota
+
+	// All control characters, escape sequences, and zero-width characters are removed
+	SanitizeStripAll
+
+	// All control characters, escape sequences, and zero-width characters are removed, except for SGR sequences
+	// (colors & text attributes)
+	SanitizeKeepColors
+
+	// Control characters are rendered visibly in caret notation (e.g. ESC becomes "^["), so that the escape
+	// sequences they start show up as plain text, and zero-width characters are removed
+	SanitizeVisible
+)
+
+const (
+	escapeRune = '\x1b'
+	bellRune   = '\x07'
+	deleteRune = '\x7f'
+
+	// 8-bit equivalents of ESC [ and ESC ]
+	c1CsiRune = '\u009b'
+	c1OscRune = '\u009d'
+)
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Applies the sanitization policy to the raw text
+// Newlines and tabs are always kept, as they're the responsibility of the whitespace mode
+func applySanitizationPolicy(str string, policy SanitizationPolicy) string {
+	if policy == SanitizeNone {
+		return str
+	}
+
+	str = strings.ReplaceAll(str, "\r\n", "\n")
+	runes := []rune(str)
+
+	var builder strings.Builder
+	for idx := 0; idx < len(runes); idx++ {
+		r := runes[idx]
+		if isZeroWidthRune(r) {
+			continue
+		}
+		if !isControlRune(r) {
+			builder.WriteRune(r)
+			continue
+		}
+
+		if policy == SanitizeVisible {
+			builder.WriteString(getCaretNotation(r))
+			continue
+		}
+
+		sequenceEndIdx, isSgr := findEscapeSequenceEnd(runes, idx)
+		if isSgr && policy == SanitizeKeepColors {
+			// Normalize to the 7-bit form, which is the one terminals reliably understand
+			builder.WriteString("\x1b[")
+			builder.WriteString(string(runes[getCsiBodyStartIdx(runes, idx) : sequenceEndIdx+1]))
+		}
+		idx = sequenceEndIdx
+	}
+	return builder.String()
+}
+
+func isControlRune(r rune) bool {
+	if r == '\n' || r == '\t' {
+		return false
+	}
+	return r < ' ' || r == deleteRune || (r >= '\u0080' && r <= '\u009f')
+}
+
+// Zero-width characters can hide content, and are also what hyperlinks get marked with (see the link package), so
+// removing them stops untrusted text from smuggling in links
+func isZeroWidthRune(r rune) bool {
+	return (r >= '\u200B' && r <= '\u200D') || r == '\u2060' || r == '\uFEFF'
+}
+
+// Renders a control character the way "cat -v" does
+func getCaretNotation(r rune) string {
+	switch {
+	case r == deleteRune:
+		return "^?"
+	case r >= '\u0080':
+		return "M-" + getCaretNotation(r-0x80)
+	default:
+		return "^" + string(r+0x40)
+	}
+}
+
+// Given the index of a control character, finds the index of the last rune in the escape sequence it starts
+// (which will be the control character itself if it doesn't start an escape sequence), and whether the sequence
+// is an SGR sequence
+// Unterminated sequences run to the end of the text
+func findEscapeSequenceEnd(runes []rune, startIdx int) (endIdx int, isSgr bool) {
+	switch runes[startIdx] {
+	case c1CsiRune:
+		return findCsiSequenceEnd(runes, startIdx+1)
+	case c1OscRune:
+		return findStringSequenceEnd(runes, startIdx+1), false
+	case escapeRune:
+		// Handled below
+	default:
+		return startIdx, false
+	}
+
+	if startIdx == len(runes)-1 {
+		return startIdx, false
+	}
+	switch runes[startIdx+1] {
+	case '[':
+		return findCsiSequenceEnd(runes, startIdx+2)
+	case ']', 'P', 'X', '^', '_':
+		// OSC, DCS, SOS, PM, and APC all carry a string that runs until the terminator
+		return findStringSequenceEnd(runes, startIdx+2), false
+	}
+
+	// Everything else is ESC, any number of intermediate bytes, and a final byte
+	for idx := startIdx + 1; idx < len(runes); idx++ {
+		r := runes[idx]
+		if r >= 0x30 && r <= 0x7e {
+			return idx, false
+		}
+		if r < 0x20 || r > 0x2f {
+			// Not a valid sequence, so only the ESC gets consumed
+			return startIdx, false
+		}
+	}
+	return len(runes) - 1, false
+}
+
+// Gets the index of the first rune after the introducer of the CSI sequence starting at the given index
+func getCsiBodyStartIdx(runes []rune, startIdx int) int {
+	if runes[startIdx] == c1CsiRune {
+		return startIdx + 1
+	}
+	return startIdx + 2
+}
+
+// Finds the end of a CSI sequence (parameter bytes, intermediate bytes, then a final byte), given the index of the
+// first rune after the introducer
+func findCsiSequenceEnd(runes []rune, bodyStartIdx int) (endIdx int, isSgr bool) {
+	hasOnlySgrParameters := true
+	for idx := bodyStartIdx; idx < len(runes); idx++ {
+		r := runes[idx]
+		if r >= 0x40 && r <= 0x7e {
+			return idx, hasOnlySgrParameters && r == 'm'
+		}
+		if !(r >= '0' && r <= '9') && r != ';' && r != ':' {
+			hasOnlySgrParameters = false
+		}
+	}
+	return len(runes) - 1, false
+}
+
+// Finds the end of a string-carrying sequence (e.g. OSC), which is terminated by either BEL or ST (ESC \)
+func findStringSequenceEnd(runes []rune, bodyStartIdx int) int {
+	for idx := bodyStartIdx; idx < len(runes); idx++ {
+		switch {
+		case runes[idx] == bellRune:
+			return idx
+		case runes[idx] == escapeRune && idx+1 < len(runes) && runes[idx+1] == '\\':
+			return idx + 1
+		}
+	}
+	return len(runes) - 1
+}
@@ -25,19 +25,46 @@ type Text interface {
 
 	GetTextAlignment() TextAlignment
 	SetTextAlignment(alignment TextAlignment) Text
+
+	GetWhitespaceMode() WhitespaceMode
+	SetWhitespaceMode(mode WhitespaceMode) Text
+
+	// The number of columns between tab stops; tabs are expanded to the next tab stop
+	GetTabSize() int
+	SetTabSize(size int) Text
+
+	GetSanitizationPolicy() SanitizationPolicy
+	SetSanitizationPolicy(policy SanitizationPolicy) Text
 }
 
 type textImpl struct {
 	text string
 
 	alignment TextAlignment
+
+	whitespaceMode WhitespaceMode
+
+	tabSize int
+
+	sanitizationPolicy SanitizationPolicy
+
+	// The text after sanitization & whitespace handling has been applied, which is what all the layout phases operate on
+	// Recalculated whenever any of the inputs to it change
+	processedText string
 }
 
 func New(text string) Text {
-	return &textImpl{
+	result := &textImpl{
 		text:      text,
 		alignment: AlignLeft,
+		// Pre-wrap keeps the historical behaviour of preserving newlines & spaces while still wrapping
+		whitespaceMode:     WhitespacePreWrap,
+		tabSize:            defaultTabSize,
+		sanitizationPolicy: SanitizeNone,
+		processedText:      "",
 	}
+	result.updateProcessedText()
+	return result
 }
 
 func (t textImpl) GetContents() string {
@@ -46,6 +73,7 @@ func (t textImpl) GetContents() string {
 
 func (t *textImpl) SetContents(str string) Text {
 	t.text = str
+	t.updateProcessedText()
 	return t
 }
 
@@ -58,20 +86,59 @@ func (t *textImpl) SetTextAlignment(align TextAlignment) Text {
 	return t
 }
 
+func (t textImpl) GetWhitespaceMode() WhitespaceMode {
+	return t.whitespaceMode
+}
+
+func (t *textImpl) SetWhitespaceMode(mode WhitespaceMode) Text {
+	t.whitespaceMode = mode
+	t.updateProcessedText()
+	return t
+}
+
+func (t textImpl) GetTabSize() int {
+	return t.tabSize
+}
+
+func (t *textImpl) SetTabSize(size int) Text {
+	t.tabSize = size
+	t.updateProcessedText()
+	return t
+}
+
+func (t textImpl) GetSanitizationPolicy() SanitizationPolicy {
+	return t.sanitizationPolicy
+}
+
+func (t *textImpl) SetSanitizationPolicy(policy SanitizationPolicy) Text {
+	t.sanitizationPolicy = policy
+	t.updateProcessedText()
+	return t
+}
+
 func (t *textImpl) GetContentMinMax() (minWidth int, maxWidth int, minHeight int, maxHeight int) {
+	if !t.whitespaceMode.wrapsLines() {
+		// No wrapping means the text is the same size no matter how much width it gets
+		minWidth = lipgloss.Width(t.processedText)
+		maxWidth = minWidth
+		minHeight = lipgloss.Height(t.processedText)
+		maxHeight = minHeight
+		return
+	}
+
 	minWidth = 0
-	for _, field := range strings.Fields(t.text) {
+	for _, field := range strings.Fields(t.processedText) {
 		printableWidth := ansi.PrintableRuneWidth(field)
 		if printableWidth > minWidth {
 			minWidth = printableWidth
 		}
 	}
 
-	maxWidth = lipgloss.Width(t.text)
+	maxWidth = lipgloss.Width(t.processedText)
 
-	minHeight = lipgloss.Height(t.text)
+	minHeight = lipgloss.Height(t.processedText)
 
-	minWidthWrapped := wordwrap.String(t.text, minWidth)
+	minWidthWrapped := Wrap(t.processedText, minWidth)
 	maxHeight = lipgloss.Height(minWidthWrapped)
 
 	return
@@ -82,8 +149,12 @@ func (t textImpl) GetContentHeightForGivenWidth(width int) int {
 		return 0
 	}
 
+	if !t.whitespaceMode.wrapsLines() {
+		return lipgloss.Height(t.processedText)
+	}
+
 	// TODO cache this?
-	wrapped := wordwrap.String(t.text, width)
+	wrapped := Wrap(t.processedText, width)
 	return lipgloss.Height(wrapped)
 }
 
@@ -92,15 +163,34 @@ func (t textImpl) View(width int, height int) string {
 		return ""
 	}
 
-	wrapped := wordwrap.String(t.text, width)
+	var laidOut string
+	if t.whitespaceMode.wrapsLines() {
+		laidOut = Wrap(t.processedText, width)
+	} else {
+		// Truncate the lines up front, so that the block expansion below doesn't wrap them
+		laidOut = lipgloss.NewStyle().MaxWidth(width).Render(t.processedText)
+	}
+
 	return lipgloss.NewStyle().Align(lipgloss.Position(t.alignment)).
 		// Width to expand to a block
 		Width(width).
 		// Truncate (we can't support overrun or any other behaviours)
 		MaxHeight(height).
-		Render(wrapped)
+		Render(laidOut)
+}
+
+// Wrap lays the string out in the given width the same way Text does, breaking lines at spaces (so words wider than the
+// width stick out past it)
+func Wrap(str string, width int) string {
+	return wordwrap.String(str, width)
 }
 
 // ====================================================================================================
 //                                   Private Helper Functions
 // ====================================================================================================
+
+func (t *textImpl) updateProcessedText() {
+	// Sanitization has to come first, so that the whitespace handling doesn't see any control characters
+	sanitized := applySanitizationPolicy(t.text, t.sanitizationPolicy)
+	t.processedText = applyWhitespaceMode(sanitized, t.whitespaceMode, t.tabSize)
+}
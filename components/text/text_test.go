@@ -2,6 +2,7 @@ package text
 
 import (
 	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -84,3 +85,130 @@ func TestSmallWidths(t *testing.T) {
 
 	test_assertions.CheckAll(t, assertions, component)
 }
+
+func TestTabExpansion(t *testing.T) {
+	str := "a\tbc\td"
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(9, 9, 1, 1),
+		test_assertions.GetRenderedContentAssertion(9, 1, "a   bc  d"),
+	)
+
+	test_assertions.CheckAll(t, assertions, New(str).SetTabSize(4).SetWhitespaceMode(WhitespacePre))
+}
+
+func TestWhitespaceNormal(t *testing.T) {
+	str := "a\tbc\td\n  two   spaces"
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(6, 17, 1, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			6, 3,
+			17, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(6, 3, "a bc d\ntwo   \nspaces"),
+	)
+
+	test_assertions.CheckAll(t, assertions, New(str).SetWhitespaceMode(WhitespaceNormal))
+}
+
+func TestWhitespacePre(t *testing.T) {
+	str := "a\tbc\td\n  two   spaces"
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(14, 14, 2, 2),
+		// Width doesn't matter when there's no wrapping
+		test_assertions.GetHeightAtWidthAssertions(
+			1, 2,
+			5, 2,
+			100, 2,
+		),
+		test_assertions.GetRenderedContentAssertion(6, 3, "a   bc\n  two "),
+	)
+
+	test_assertions.CheckAll(t, assertions, New(str).SetTabSize(4).SetWhitespaceMode(WhitespacePre))
+}
+
+func TestWhitespacePreWrap(t *testing.T) {
+	str := "a\tbc\td\n  two   spaces"
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(6, 14, 2, 4),
+		test_assertions.GetHeightAtWidthAssertions(
+			5, 4,
+			14, 2,
+		),
+		test_assertions.GetRenderedContentAssertion(6, 3, "a   bc\nd     \n  two "),
+	)
+
+	test_assertions.CheckAll(t, assertions, New(str).SetTabSize(4).SetWhitespaceMode(WhitespacePreWrap))
+}
+
+func TestWhitespaceNoWrap(t *testing.T) {
+	str := "a\tbc\td\n  two   spaces"
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(17, 17, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(
+			5, 1,
+			100, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(6, 3, "a bc d"),
+	)
+
+	test_assertions.CheckAll(t, assertions, New(str).SetWhitespaceMode(WhitespaceNoWrap))
+}
+
+// Contains SGR colors, a screen clear, an OSC window title, a bell, and a carriage return
+const untrustedStr = "\x1b[31mred\x1b[0m\x1b[2J\x1b]0;title\x07 ok\a\r\nnext"
+
+func TestSanitizeNone(t *testing.T) {
+	component := New(untrustedStr)
+	require.Equal(t, untrustedStr, component.(*textImpl).processedText)
+}
+
+func TestSanitizeStripAll(t *testing.T) {
+	component := New(untrustedStr).SetSanitizationPolicy(SanitizeStripAll)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(4, 6, 2, 3),
+		test_assertions.GetRenderedContentAssertion(6, 2, "red ok\nnext  "),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestSanitizeKeepColors(t *testing.T) {
+	component := New(untrustedStr).SetSanitizationPolicy(SanitizeKeepColors)
+	require.Equal(t, "\x1b[31mred\x1b[0m ok\nnext", component.(*textImpl).processedText)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(4, 6, 2, 3),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestSanitizeVisible(t *testing.T) {
+	component := New("a\x1b[2Jb\x7f\u009bc\rd").SetSanitizationPolicy(SanitizeVisible)
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(17, 17, 1, 1),
+		test_assertions.GetRenderedContentAssertion(17, 1, "a^[[2Jb^?M-^[c^Md"),
+	)
+
+	test_assertions.CheckAll(t, assertions, component)
+}
+
+func TestSanitizeRemovesZeroWidthCharacters(t *testing.T) {
+	// Zero-width characters are what hyperlinks get marked with, so untrusted text mustn't be able to include them
+	component := New("zero\u200Bwidth\uFEFF").SetSanitizationPolicy(SanitizeStripAll)
+	require.Equal(t, "zerowidth", component.(*textImpl).processedText)
+}
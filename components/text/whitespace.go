@@ -0,0 +1,112 @@
+package text
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/ansi"
+	"strings"
+	"unicode"
+)
+
+// Controls how whitespace inside the text is handled
+// Analogous to the "white-space" property in CSS
+type WhitespaceMode int
+
+const (
+	// Runs of whitespace (including newlines) are collapsed into a single space, and lines wrap as necessary
+	// Corresponds to "white-space: normal"
+	WhitespaceNormal WhitespaceMode = iota
+
+	// Whitespace and newlines are preserved as-is, and lines are never wrapped (they get truncated instead)
+	// Corresponds to "white-space: pre"
+	WhitespacePre
+
+	// Whitespace and newlines are preserved as-is, and lines wrap as necessary
+	// Corresponds to "white-space: pre-wrap"
+	WhitespacePreWrap
+
+	// Runs of whitespace (including newlines) are collapsed into a single space, and lines are never wrapped
+	// Corresponds to "white-space: nowrap"
+	WhitespaceNoWrap
+)
+
+// The number of columns between tab stops, if not otherwise specified
+const defaultTabSize = 8
+
+// Whether the mode collapses runs of whitespace into a single space
+func (mode WhitespaceMode) collapsesWhitespace() bool {
+	return mode == WhitespaceNormal || mode == WhitespaceNoWrap
+}
+
+// Whether the mode allows lines to be wrapped when there isn't enough width
+func (mode WhitespaceMode) wrapsLines() bool {
+	return mode == WhitespaceNormal || mode == WhitespacePreWrap
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Applies the whitespace mode to the raw text, producing the text that all three layout phases operate on
+func applyWhitespaceMode(str string, mode WhitespaceMode, tabSize int) string {
+	if mode.collapsesWhitespace() {
+		return collapseWhitespace(str)
+	}
+	return ExpandTabs(str, tabSize)
+}
+
+// Collapses every run of whitespace (including tabs & newlines) into a single space, and trims the ends
+func collapseWhitespace(str string) string {
+	var builder strings.Builder
+	isInWhitespaceRun := false
+	for _, r := range strings.TrimSpace(str) {
+		if unicode.IsSpace(r) {
+			isInWhitespaceRun = true
+			continue
+		}
+		if isInWhitespaceRun {
+			builder.WriteRune(' ')
+			isInWhitespaceRun = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// ExpandTabs replaces each tab with enough spaces to reach the next tab stop
+// ANSI escape sequences don't take up any columns, so they're skipped when calculating the tab stops
+// A tab size of 0 or less removes tabs entirely
+func ExpandTabs(str string, tabSize int) string {
+	if !strings.ContainsRune(str, '\t') {
+		return str
+	}
+
+	var builder strings.Builder
+	column := 0
+	isInEscapeSequence := false
+	for _, r := range str {
+		switch {
+		case r == ansi.Marker:
+			isInEscapeSequence = true
+			builder.WriteRune(r)
+		case isInEscapeSequence:
+			if ansi.IsTerminator(r) {
+				isInEscapeSequence = false
+			}
+			builder.WriteRune(r)
+		case r == '\n':
+			column = 0
+			builder.WriteRune(r)
+		case r == '\t':
+			if tabSize <= 0 {
+				continue
+			}
+			numSpaces := tabSize - column%tabSize
+			builder.WriteString(strings.Repeat(" ", numSpaces))
+			column += numSpaces
+		default:
+			column += runewidth.RuneWidth(r)
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,87 @@
+package textarea
+
+// How many edits can be undone
+const maxUndoDepth = 100
+
+type editKind int
+
+const (
+	// Indicates that the next edit should start a new undo group
+	noEdit editKind = iota
+
+	// Typing a word and typing the whitespace after it are separate kinds, so words get undone one at a time
+	insertWordEdit
+	insertWhitespaceEdit
+
+	deleteEdit
+)
+
+// A saved state of the text area, which undo & redo move between
+type snapshot struct {
+	value  []rune
+	cursor int
+}
+
+// Keeps the states to undo & redo to, grouping runs of the same kind of edit (e.g. typing a word) into one undo step
+type editHistory struct {
+	undoStack []snapshot
+	redoStack []snapshot
+
+	lastEditKind editKind
+}
+
+func newEditHistory() *editHistory {
+	return &editHistory{
+		undoStack:    make([]snapshot, 0),
+		redoStack:    make([]snapshot, 0),
+		lastEditKind: noEdit,
+	}
+}
+
+// Records the state from just before an edit of the given kind
+func (h *editHistory) recordEdit(kind editKind, before snapshot) {
+	h.redoStack = h.redoStack[:0]
+	if kind == h.lastEditKind {
+		return
+	}
+	h.lastEditKind = kind
+	h.undoStack = append(h.undoStack, before)
+	if len(h.undoStack) > maxUndoDepth {
+		h.undoStack = h.undoStack[1:]
+	}
+}
+
+// Makes the next edit start a new undo group (e.g. because the cursor moved)
+func (h *editHistory) breakGroup() {
+	h.lastEditKind = noEdit
+}
+
+// Returns the state to restore, and false if there's nothing to undo
+func (h *editHistory) undo(current snapshot) (snapshot, bool) {
+	if len(h.undoStack) == 0 {
+		return current, false
+	}
+	result := h.undoStack[len(h.undoStack)-1]
+	h.undoStack = h.undoStack[:len(h.undoStack)-1]
+	h.redoStack = append(h.redoStack, current)
+	h.lastEditKind = noEdit
+	return result, true
+}
+
+// Returns the state to restore, and false if there's nothing to redo
+func (h *editHistory) redo(current snapshot) (snapshot, bool) {
+	if len(h.redoStack) == 0 {
+		return current, false
+	}
+	result := h.redoStack[len(h.redoStack)-1]
+	h.redoStack = h.redoStack[:len(h.redoStack)-1]
+	h.undoStack = append(h.undoStack, current)
+	h.lastEditKind = noEdit
+	return result, true
+}
+
+func (h *editHistory) clear() {
+	h.undoStack = h.undoStack[:0]
+	h.redoStack = h.redoStack[:0]
+	h.lastEditKind = noEdit
+}
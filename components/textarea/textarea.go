@@ -0,0 +1,554 @@
+package textarea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+	"unicode"
+)
+
+// Tabs get replaced with spaces, so that the width of every character is known when wrapping
+const tabReplacement = "    "
+
+// Indicates that there's no column the cursor is trying to stay in when moving up & down
+const noGoalColumn = -1
+
+// TextArea is a multi-line field that the user can type into, which soft-wraps the same way as text.Text
+// Analogous to the <textarea> tag in HTML
+// It wants to be as tall as its contents, so wrap it in a flexbox item with a max height to limit how far it grows;
+// when it gets less height than its contents need, it scrolls to keep the cursor visible
+type TextArea interface {
+	components.InteractiveComponent
+
+	GetValue() string
+	// Setting the value programmatically puts the cursor at the end, clears the undo history, and doesn't send a
+	// ChangedMsg
+	SetValue(value string) TextArea
+
+	// Shown when the value is empty
+	GetPlaceholder() string
+	SetPlaceholder(placeholder string) TextArea
+
+	// Positions are in characters, where 0 is before the first character
+	GetCursorPosition() int
+	SetCursorPosition(position int) TextArea
+
+	GetStyles() Styles
+	SetStyles(styles Styles) TextArea
+}
+
+// ChangedMsg is sent whenever the user changes the value of a text area
+type ChangedMsg struct {
+	Area  TextArea
+	Value string
+}
+
+type textAreaImpl struct {
+	value []rune
+
+	placeholder string
+
+	// Nil for the default styles
+	styles *Styles
+
+	isFocused bool
+
+	// The index of the character the cursor is in front of (len(value) when it's after the last character)
+	cursor int
+
+	// The column that moving up & down tries to keep the cursor in, so that moving through short lines doesn't lose
+	// the cursor's place
+	goalColumn int
+
+	// The index of the first visible row, which moves so that the cursor stays visible
+	scrollRow int
+
+	history *editHistory
+
+	// The size given to the last View, which the row-based movement & mouse clicks work off of
+	lastViewWidth  int
+	lastViewHeight int
+
+	// -------------------- Calculation Caching -----------------------
+	// The rows of the value wrapped at the width in rowsCacheWidth, invalidated whenever the value changes
+	rowsCache      []row
+	rowsCacheWidth int
+}
+
+func New() TextArea {
+	return &textAreaImpl{
+		value:          make([]rune, 0),
+		placeholder:    "",
+		styles:         nil,
+		isFocused:      false,
+		cursor:         0,
+		goalColumn:     noGoalColumn,
+		scrollRow:      0,
+		history:        newEditHistory(),
+		lastViewWidth:  0,
+		lastViewHeight: 0,
+		rowsCache:      nil,
+		rowsCacheWidth: 0,
+	}
+}
+
+func (t textAreaImpl) GetValue() string {
+	return string(t.value)
+}
+
+func (t *textAreaImpl) SetValue(value string) TextArea {
+	t.setValueRunes(sanitizeInput(value))
+	t.cursor = len(t.value)
+	t.goalColumn = noGoalColumn
+	t.history.clear()
+	return t
+}
+
+func (t textAreaImpl) GetPlaceholder() string {
+	return t.placeholder
+}
+
+func (t *textAreaImpl) SetPlaceholder(placeholder string) TextArea {
+	t.placeholder = placeholder
+	return t
+}
+
+func (t textAreaImpl) GetCursorPosition() int {
+	return t.cursor
+}
+
+func (t *textAreaImpl) SetCursorPosition(position int) TextArea {
+	t.moveTo(position)
+	return t
+}
+
+func (t textAreaImpl) GetStyles() Styles {
+	if t.styles == nil {
+		return DefaultStyles()
+	}
+	return *t.styles
+}
+
+func (t *textAreaImpl) SetStyles(styles Styles) TextArea {
+	t.styles = &styles
+	return t
+}
+
+func (t *textAreaImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	measured := t.getMeasuredRunes()
+
+	// Like text.Text, the min width is the longest word & the max width is the longest line (plus a cell for the cursor
+	// to sit in at the end)
+	minWidth = 1
+	for _, word := range strings.Fields(string(measured)) {
+		minWidth = utilities.GetMaxInt(minWidth, runewidth.StringWidth(word))
+	}
+	maxWidth = 0
+	for _, line := range strings.Split(string(measured), "\n") {
+		maxWidth = utilities.GetMaxInt(maxWidth, runewidth.StringWidth(line)+1)
+	}
+	maxWidth = utilities.GetMaxInt(minWidth, maxWidth)
+
+	minHeight = strings.Count(string(measured), "\n") + 1
+	maxHeight = len(getRows(measured, minWidth))
+	return
+}
+
+func (t *textAreaImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	if len(t.value) == 0 {
+		return len(getRows(t.getMeasuredRunes(), width))
+	}
+	return len(t.getRows(width))
+}
+
+func (t *textAreaImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	t.lastViewWidth = width
+	t.lastViewHeight = height
+
+	if len(t.value) == 0 && t.placeholder != "" {
+		return t.renderPlaceholder(width, height)
+	}
+
+	rows := t.getRows(width)
+	cursorRowIdx := getCursorRowIdx(rows, t.cursor)
+	t.updateScrollRow(cursorRowIdx, len(rows), height)
+
+	lastVisibleRowIdx := utilities.GetMinInt(t.scrollRow+height, len(rows))
+	lines := make([]string, 0, lastVisibleRowIdx-t.scrollRow)
+	for rowIdx := t.scrollRow; rowIdx < lastVisibleRowIdx; rowIdx++ {
+		lines = append(lines, t.renderRow(rows[rowIdx], width, t.isFocused && rowIdx == cursorRowIdx))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t *textAreaImpl) Update(msg tea.Msg) tea.Cmd {
+	if !t.isFocused {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return t.handleKey(msg)
+	case tea.MouseMsg:
+		if !components.IsMouseMsgInBounds(msg, t.lastViewWidth, t.lastViewHeight) {
+			return nil
+		}
+		switch msg.Type {
+		case tea.MouseLeft:
+			rows := t.getRows(t.lastViewWidth)
+			rowIdx := t.scrollRow + msg.Y
+			if rowIdx >= len(rows) {
+				t.moveTo(len(t.value))
+				break
+			}
+			t.moveTo(getIndexAtColumn(t.value, rows[rowIdx], msg.X))
+		case tea.MouseWheelUp:
+			t.moveVertically(-1)
+		case tea.MouseWheelDown:
+			t.moveVertically(1)
+		}
+	}
+	return nil
+}
+
+func (t *textAreaImpl) SetFocus(isFocused bool) {
+	t.isFocused = isFocused
+}
+
+func (t textAreaImpl) IsFocused() bool {
+	return t.isFocused
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (t *textAreaImpl) handleKey(msg tea.KeyMsg) tea.Cmd {
+	oldValue := string(t.value)
+
+	switch msg.Type {
+	case tea.KeyRunes:
+		if !msg.Alt {
+			t.insert(sanitizeInput(string(msg.Runes)))
+			break
+		}
+		// Readline-style word bindings
+		switch string(msg.Runes) {
+		case "b":
+			t.moveTo(t.getPreviousWordStart())
+		case "f":
+			t.moveTo(t.getNextWordEnd())
+		case "d":
+			t.deleteTo(t.getNextWordEnd())
+		}
+	case tea.KeySpace:
+		t.insert([]rune{' '})
+	case tea.KeyEnter:
+		t.insert([]rune{'\n'})
+	case tea.KeyBackspace, tea.KeyCtrlH:
+		if msg.Alt {
+			t.deleteTo(t.getPreviousWordStart())
+		} else {
+			t.deleteTo(t.cursor - 1)
+		}
+	case tea.KeyCtrlW:
+		t.deleteTo(t.getPreviousWordStart())
+	case tea.KeyDelete:
+		t.deleteTo(t.cursor + 1)
+	case tea.KeyCtrlU:
+		t.deleteTo(t.getLineStart())
+	case tea.KeyCtrlK:
+		// Like Emacs, killing at the end of a line joins it with the next one
+		if lineEnd := t.getLineEnd(); lineEnd != t.cursor {
+			t.deleteTo(lineEnd)
+		} else {
+			t.deleteTo(t.cursor + 1)
+		}
+	case tea.KeyLeft, tea.KeyCtrlB:
+		if msg.Alt {
+			t.moveTo(t.getPreviousWordStart())
+		} else {
+			t.moveTo(t.cursor - 1)
+		}
+	case tea.KeyRight, tea.KeyCtrlF:
+		if msg.Alt {
+			t.moveTo(t.getNextWordEnd())
+		} else {
+			t.moveTo(t.cursor + 1)
+		}
+	case tea.KeyCtrlLeft:
+		t.moveTo(t.getPreviousWordStart())
+	case tea.KeyCtrlRight:
+		t.moveTo(t.getNextWordEnd())
+	case tea.KeyUp, tea.KeyCtrlP:
+		t.moveVertically(-1)
+	case tea.KeyDown, tea.KeyCtrlN:
+		t.moveVertically(1)
+	case tea.KeyPgUp:
+		t.moveVertically(-utilities.GetMaxInt(1, t.lastViewHeight))
+	case tea.KeyPgDown:
+		t.moveVertically(utilities.GetMaxInt(1, t.lastViewHeight))
+	case tea.KeyHome, tea.KeyCtrlA:
+		rows := t.getRows(t.lastViewWidth)
+		t.moveTo(rows[getCursorRowIdx(rows, t.cursor)].start)
+	case tea.KeyEnd, tea.KeyCtrlE:
+		rows := t.getRows(t.lastViewWidth)
+		cursorRow := rows[getCursorRowIdx(rows, t.cursor)]
+		t.moveTo(getIndexAtColumn(t.value, cursorRow, getRunesWidth(t.value[cursorRow.start:cursorRow.end])))
+	case tea.KeyCtrlHome:
+		t.moveTo(0)
+	case tea.KeyCtrlEnd:
+		t.moveTo(len(t.value))
+	case tea.KeyCtrlZ:
+		t.restore(t.history.undo(t.getSnapshot()))
+	case tea.KeyCtrlY:
+		t.restore(t.history.redo(t.getSnapshot()))
+	}
+
+	if string(t.value) == oldValue {
+		return nil
+	}
+	return t.getChangedCmd()
+}
+
+func (t *textAreaImpl) moveTo(position int) {
+	t.cursor = utilities.Clamp(position, 0, len(t.value))
+	t.goalColumn = noGoalColumn
+	t.history.breakGroup()
+}
+
+// Moves the cursor up (negative) or down (positive) by the given number of rows, keeping it in the same column
+func (t *textAreaImpl) moveVertically(numRows int) {
+	rows := t.getRows(t.lastViewWidth)
+	cursorRowIdx := getCursorRowIdx(rows, t.cursor)
+	if t.goalColumn == noGoalColumn {
+		t.goalColumn = getRunesWidth(t.value[rows[cursorRowIdx].start:t.cursor])
+	}
+
+	targetRowIdx := cursorRowIdx + numRows
+	switch {
+	case targetRowIdx < 0:
+		t.cursor = 0
+	case targetRowIdx >= len(rows):
+		t.cursor = len(t.value)
+	default:
+		t.cursor = getIndexAtColumn(t.value, rows[targetRowIdx], t.goalColumn)
+	}
+	t.history.breakGroup()
+}
+
+func (t *textAreaImpl) insert(runes []rune) {
+	if len(runes) == 0 {
+		return
+	}
+
+	kind := insertWordEdit
+	if unicode.IsSpace(runes[0]) {
+		kind = insertWhitespaceEdit
+	}
+	t.history.recordEdit(kind, t.getSnapshot())
+
+	newValue := make([]rune, 0, len(t.value)+len(runes))
+	newValue = append(newValue, t.value[:t.cursor]...)
+	newValue = append(newValue, runes...)
+	newValue = append(newValue, t.value[t.cursor:]...)
+	t.setValueRunes(newValue)
+	t.cursor += len(runes)
+	t.goalColumn = noGoalColumn
+}
+
+// Deletes everything between the cursor and the position
+func (t *textAreaImpl) deleteTo(position int) {
+	position = utilities.Clamp(position, 0, len(t.value))
+	if position == t.cursor {
+		return
+	}
+	t.history.recordEdit(deleteEdit, t.getSnapshot())
+
+	start, end := utilities.GetMinInt(t.cursor, position), utilities.GetMaxInt(t.cursor, position)
+	newValue := make([]rune, 0, len(t.value)-(end-start))
+	newValue = append(newValue, t.value[:start]...)
+	newValue = append(newValue, t.value[end:]...)
+	t.setValueRunes(newValue)
+	t.cursor = start
+	t.goalColumn = noGoalColumn
+}
+
+func (t *textAreaImpl) setValueRunes(value []rune) {
+	t.value = value
+	t.rowsCache = nil
+}
+
+func (t textAreaImpl) getSnapshot() snapshot {
+	return snapshot{
+		value:  append([]rune(nil), t.value...),
+		cursor: t.cursor,
+	}
+}
+
+func (t *textAreaImpl) restore(state snapshot, isChanged bool) {
+	if !isChanged {
+		return
+	}
+	t.setValueRunes(state.value)
+	t.cursor = utilities.Clamp(state.cursor, 0, len(t.value))
+	t.goalColumn = noGoalColumn
+}
+
+func (t textAreaImpl) getLineStart() int {
+	idx := t.cursor
+	for idx > 0 && t.value[idx-1] != '\n' {
+		idx--
+	}
+	return idx
+}
+
+func (t textAreaImpl) getLineEnd() int {
+	idx := t.cursor
+	for idx < len(t.value) && t.value[idx] != '\n' {
+		idx++
+	}
+	return idx
+}
+
+func (t textAreaImpl) getPreviousWordStart() int {
+	idx := t.cursor
+	for idx > 0 && unicode.IsSpace(t.value[idx-1]) {
+		idx--
+	}
+	for idx > 0 && !unicode.IsSpace(t.value[idx-1]) {
+		idx--
+	}
+	return idx
+}
+
+func (t textAreaImpl) getNextWordEnd() int {
+	idx := t.cursor
+	for idx < len(t.value) && unicode.IsSpace(t.value[idx]) {
+		idx++
+	}
+	for idx < len(t.value) && !unicode.IsSpace(t.value[idx]) {
+		idx++
+	}
+	return idx
+}
+
+func (t *textAreaImpl) getChangedCmd() tea.Cmd {
+	msg := ChangedMsg{
+		Area:  t,
+		Value: string(t.value),
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Gets the rows of the value at the given width, which the height phase & View share
+func (t *textAreaImpl) getRows(width int) []row {
+	if t.rowsCache == nil || t.rowsCacheWidth != width {
+		t.rowsCache = getRows(t.value, width)
+		t.rowsCacheWidth = width
+	}
+	return t.rowsCache
+}
+
+// The placeholder takes up space when there's no value, so that the text area doesn't collapse
+func (t textAreaImpl) getMeasuredRunes() []rune {
+	if len(t.value) == 0 {
+		return sanitizeInput(t.placeholder)
+	}
+	return t.value
+}
+
+// Scrolls just enough that the cursor's row is visible, without leaving empty rows at the bottom while there are rows
+// hidden off the top
+func (t *textAreaImpl) updateScrollRow(cursorRowIdx int, numRows int, height int) {
+	if cursorRowIdx < t.scrollRow {
+		t.scrollRow = cursorRowIdx
+	}
+	if cursorRowIdx >= t.scrollRow+height {
+		t.scrollRow = cursorRowIdx - height + 1
+	}
+	t.scrollRow = utilities.Clamp(t.scrollRow, 0, utilities.GetMaxInt(0, numRows-height))
+}
+
+func (t textAreaImpl) renderRow(r row, width int, hasCursor bool) string {
+	runes := t.value[r.start:r.end]
+
+	// The cursor can end up among the spaces hanging off the end of a row, in which case we make room for it at the
+	// right edge
+	cursorIdx := t.cursor - r.start
+	availableWidth := width
+	if hasCursor && getRunesWidth(runes[:cursorIdx]) >= width {
+		availableWidth = width - 1
+		cursorIdx = len(runes)
+	}
+
+	numVisible, usedWidth := 0, 0
+	for numVisible < len(runes) && usedWidth+runewidth.RuneWidth(runes[numVisible]) <= availableWidth {
+		usedWidth += runewidth.RuneWidth(runes[numVisible])
+		numVisible++
+	}
+	visible := runes[:numVisible]
+
+	if !hasCursor {
+		return t.GetStyles().Text.Render(string(visible)) + strings.Repeat(" ", width-usedWidth)
+	}
+
+	before, cursorStr, after := string(visible), " ", ""
+	if cursorIdx < len(visible) {
+		before, cursorStr, after = string(visible[:cursorIdx]), string(visible[cursorIdx]), string(visible[cursorIdx+1:])
+	} else {
+		usedWidth++
+	}
+	return t.GetStyles().Text.Render(before) +
+		t.GetStyles().Cursor.Render(cursorStr) +
+		t.GetStyles().Text.Render(after) +
+		strings.Repeat(" ", utilities.GetMaxInt(0, width-usedWidth))
+}
+
+func (t textAreaImpl) renderPlaceholder(width int, height int) string {
+	lines := strings.Split(text.Wrap(t.placeholder, width), "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for idx, line := range lines {
+		line = runewidth.Truncate(line, width, "")
+		padding := strings.Repeat(" ", width-runewidth.StringWidth(line))
+		lineRunes := []rune(line)
+		if idx == 0 && t.isFocused && len(lineRunes) > 0 {
+			lines[idx] = t.GetStyles().Cursor.Render(string(lineRunes[:1])) +
+				t.GetStyles().Placeholder.Render(string(lineRunes[1:])) +
+				padding
+			continue
+		}
+		lines[idx] = t.GetStyles().Placeholder.Render(line) + padding
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// Tabs become spaces & line endings get normalized, while other control characters have no place in the value
+func sanitizeInput(str string) []rune {
+	str = strings.ReplaceAll(str, "\r\n", "\n")
+	str = strings.ReplaceAll(str, "\r", "\n")
+	str = strings.ReplaceAll(str, "\t", tabReplacement)
+
+	result := make([]rune, 0, len(str))
+	for _, r := range str {
+		if r != '\n' && unicode.IsControl(r) {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
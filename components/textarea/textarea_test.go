@@ -0,0 +1,194 @@
+package textarea
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const commitMessage = "Fix the flexbox\n\nThe column direction was taking the max height of its children instead of the sum"
+
+func TestSizeFromContents(t *testing.T) {
+	area := New().SetValue("hello there world\nsecond")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(6, 18, 2, 4),
+		test_assertions.GetHeightAtWidthAssertions(
+			6, 4,
+			11, 3,
+			18, 2,
+		),
+	)
+
+	test_assertions.CheckAll(t, assertions, area)
+}
+
+func TestWrapsLikeText(t *testing.T) {
+	values := []string{
+		commitMessage,
+		"   leading spaces and  doubled  spaces",
+		"key:\n  - first item\n  - second item",
+	}
+	for _, value := range values {
+		for _, width := range []int{10, 17, 40} {
+			area := New().SetValue(value)
+			textComponent := text.New(value)
+
+			height := textComponent.GetContentHeightForGivenWidth(width)
+			require.Equal(t, height, area.GetContentHeightForGivenWidth(width), "Height mismatch for '%v' at width %v", value, width)
+			require.Equal(t, textComponent.View(width, height), area.View(width, height), "View mismatch for '%v' at width %v", value, width)
+		}
+	}
+}
+
+func TestLongWordsAreBroken(t *testing.T) {
+	// Unlike Text, which lets them stick out past its edge, so that the cursor stays visible
+	area := New().SetValue("averylongword fits")
+	require.Equal(t, 4, area.GetContentHeightForGivenWidth(5))
+	require.Equal(t, "avery\nlongw\nord  \nfits ", area.View(5, 4))
+}
+
+func TestCursorMovesAcrossWrappedRows(t *testing.T) {
+	area := newFocusedArea().SetValue("one two three four")
+	area.GetContentHeightForGivenWidth(9)
+	require.Equal(t, "one two  \nthree    \nfour     ", area.View(9, 3))
+
+	// The cursor starts at the end, and moving up keeps it in the same column where possible
+	sendKeys(area, tea.KeyUp)
+	require.Equal(t, 12, area.GetCursorPosition())
+	sendKeys(area, tea.KeyUp)
+	require.Equal(t, 4, area.GetCursorPosition())
+
+	sendKeys(area, tea.KeyEnd)
+	require.Equal(t, 7, area.GetCursorPosition())
+	sendKeys(area, tea.KeyRight)
+	sendKeys(area, tea.KeyHome)
+	require.Equal(t, 8, area.GetCursorPosition())
+
+	// Going past the last row goes to the end
+	sendKeys(area, tea.KeyDown, tea.KeyDown, tea.KeyDown)
+	require.Equal(t, 18, area.GetCursorPosition())
+}
+
+func TestTypingNewlines(t *testing.T) {
+	area := newFocusedArea()
+	typeString(area, "key:")
+	sendKeys(area, tea.KeyEnter, tea.KeySpace, tea.KeySpace)
+	typeString(area, "value")
+	require.Equal(t, "key:\n  value", area.GetValue())
+
+	sendKeys(area, tea.KeyUp)
+	require.Equal(t, 4, area.GetCursorPosition())
+	sendKeys(area, tea.KeyCtrlK)
+	require.Equal(t, "key:  value", area.GetValue())
+}
+
+func TestVerticalScrolling(t *testing.T) {
+	area := newFocusedArea().SetValue("1\n2\n3\n4\n5")
+	area.GetContentHeightForGivenWidth(2)
+
+	// The cursor is at the end, so the bottom rows are shown
+	require.Equal(t, "3 \n4 \n5 ", area.View(2, 3))
+
+	sendKeys(area, tea.KeyUp, tea.KeyUp)
+	require.Equal(t, "3 \n4 \n5 ", area.View(2, 3))
+	sendKeys(area, tea.KeyUp)
+	require.Equal(t, "2 \n3 \n4 ", area.View(2, 3))
+	sendKeys(area, tea.KeyCtrlHome)
+	require.Equal(t, "1 \n2 \n3 ", area.View(2, 3))
+}
+
+func TestUndoRedo(t *testing.T) {
+	area := newFocusedArea()
+	typeString(area, "hello world")
+	sendKeys(area, tea.KeyBackspace, tea.KeyBackspace)
+	require.Equal(t, "hello wor", area.GetValue())
+
+	// Deletes, words, and the spaces between them are each undone in one go
+	sendKeys(area, tea.KeyCtrlZ)
+	require.Equal(t, "hello world", area.GetValue())
+	sendKeys(area, tea.KeyCtrlZ)
+	require.Equal(t, "hello ", area.GetValue())
+	sendKeys(area, tea.KeyCtrlZ)
+	require.Equal(t, "hello", area.GetValue())
+
+	sendKeys(area, tea.KeyCtrlY, tea.KeyCtrlY)
+	require.Equal(t, "hello world", area.GetValue())
+	require.Equal(t, 11, area.GetCursorPosition())
+
+	// A new edit drops what could have been redone
+	typeString(area, "!")
+	require.Nil(t, area.Update(tea.KeyMsg{Type: tea.KeyCtrlY}))
+	require.Equal(t, "hello world!", area.GetValue())
+}
+
+func TestChangedMsg(t *testing.T) {
+	area := newFocusedArea()
+	cmd := area.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a\tb")})
+	require.Equal(t, ChangedMsg{Area: area, Value: "a    b"}, cmd())
+
+	require.Nil(t, area.Update(tea.KeyMsg{Type: tea.KeyLeft}))
+}
+
+func TestGrowsInFlexboxUpToMax(t *testing.T) {
+	area := newFocusedArea().SetValue("1\n2")
+	box := flexbox.NewWithContents(
+		flexbox_item.New(area).
+			SetMinHeight(flexbox_item.FixedSize(1)).
+			SetMaxHeight(flexbox_item.FixedSize(4)),
+	).SetDirection(flexbox.Column)
+
+	box.GetContentMinMax()
+	require.Equal(t, 2, box.GetContentHeightForGivenWidth(3))
+
+	area.SetValue("1\n2\n3\n4\n5\n6")
+	box.GetContentMinMax()
+	require.Equal(t, 4, box.GetContentHeightForGivenWidth(3))
+
+	// The cursor is at the end, so the text area scrolls to the bottom within the 4 rows it gets
+	require.Equal(t, "3  \n4  \n5  \n6  \n   \n   ", box.View(3, 6))
+}
+
+func TestMouseClickMovesCursor(t *testing.T) {
+	area := newFocusedArea().SetValue("one two three four")
+	area.GetContentHeightForGivenWidth(9)
+	area.View(9, 3)
+
+	area.Update(tea.MouseMsg{X: 2, Y: 1, Type: tea.MouseLeft})
+	require.Equal(t, 10, area.GetCursorPosition())
+
+	// Past the end of a row puts the cursor at the end of it
+	area.Update(tea.MouseMsg{X: 8, Y: 2, Type: tea.MouseLeft})
+	require.Equal(t, 18, area.GetCursorPosition())
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func newFocusedArea() TextArea {
+	area := New()
+	area.SetFocus(true)
+	return area
+}
+
+func typeString(area TextArea, str string) {
+	for _, r := range str {
+		if r == ' ' {
+			area.Update(tea.KeyMsg{Type: tea.KeySpace})
+			continue
+		}
+		area.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func sendKeys(area TextArea, keyTypes ...tea.KeyType) {
+	for _, keyType := range keyTypes {
+		area.Update(tea.KeyMsg{Type: keyType})
+	}
+}
@@ -0,0 +1,124 @@
+package textarea
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/muesli/reflow/wrap"
+)
+
+// A row is one line of the text area as it appears on screen, after wrapping
+// The start & end are indexes into the value, and the newline ending a line isn't part of any row
+type row struct {
+	start int
+	end   int
+
+	// Whether the row is the last one of its (unwrapped) line, meaning the cursor can sit at its end
+	isLastInLine bool
+}
+
+// Wraps the value into rows using the same rules as text.Text, so that a text area & a text with the same contents
+// look the same
+// The one difference is that words wider than the text area get broken up rather than sticking out past its edge, so
+// that the cursor can always be seen
+func getRows(value []rune, width int) []row {
+	result := make([]row, 0)
+	lineStart := 0
+	for idx := 0; idx <= len(value); idx++ {
+		if idx < len(value) && value[idx] != '\n' {
+			continue
+		}
+		lineRows := getLineRows(value[lineStart:idx], width)
+		for _, lineRow := range lineRows {
+			result = append(result, row{
+				start:        lineStart + lineRow.start,
+				end:          lineStart + lineRow.end,
+				isLastInLine: false,
+			})
+		}
+		result[len(result)-1].isLastInLine = true
+		lineStart = idx + 1
+	}
+	return result
+}
+
+// Gets the rows of a single line, relative to the start of the line
+// The wrapping gets done on the string, and then the wrapped result is matched back up against the line to find out
+// where the breaks ended up
+func getLineRows(line []rune, width int) []row {
+	result := make([]row, 0)
+	wrapped := []rune(text.Wrap(string(line), width))
+	if width > 0 {
+		wrapped = []rune(wrap.String(string(wrapped), width))
+	}
+
+	rowStart, lineIdx := 0, 0
+	for wrappedIdx := 0; wrappedIdx < len(wrapped); wrappedIdx++ {
+		r := wrapped[wrappedIdx]
+		if r == '\n' {
+			// The spaces that the wrapping dropped at the break stay at the end of the row, where the cursor can
+			// still get to them
+			numDroppedSpaces := countLeadingSpaces(line[lineIdx:]) - countLeadingSpaces(wrapped[wrappedIdx+1:])
+			if numDroppedSpaces > 0 {
+				lineIdx += numDroppedSpaces
+			}
+			result = append(result, row{start: rowStart, end: lineIdx})
+			rowStart = lineIdx
+			continue
+		}
+
+		// Skip past anything else that the wrapping dropped
+		for lineIdx < len(line) && line[lineIdx] != r {
+			lineIdx++
+		}
+		lineIdx++
+	}
+	return append(result, row{start: rowStart, end: len(line)})
+}
+
+// Gets the index of the row that the cursor is displayed on
+func getCursorRowIdx(rows []row, cursor int) int {
+	for idx, r := range rows {
+		if cursor < r.start {
+			continue
+		}
+		// At a wrapped break, the cursor is displayed at the start of the next row
+		if cursor < r.end || (cursor == r.end && r.isLastInLine) {
+			return idx
+		}
+	}
+	return len(rows) - 1
+}
+
+// Gets the index in the value closest to the given column of the row
+func getIndexAtColumn(value []rune, r row, column int) int {
+	// At a wrapped break, the end of the row is really the start of the next row
+	maxIdx := r.end
+	if !r.isLastInLine && r.end > r.start {
+		maxIdx = r.end - 1
+	}
+
+	usedWidth := 0
+	for idx := r.start; idx < maxIdx; idx++ {
+		usedWidth += runewidth.RuneWidth(value[idx])
+		if usedWidth > column {
+			return idx
+		}
+	}
+	return maxIdx
+}
+
+func getRunesWidth(runes []rune) int {
+	result := 0
+	for _, r := range runes {
+		result += runewidth.RuneWidth(r)
+	}
+	return result
+}
+
+func countLeadingSpaces(runes []rune) int {
+	result := 0
+	for result < len(runes) && runes[result] == ' ' {
+		result++
+	}
+	return result
+}
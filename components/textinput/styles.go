@@ -0,0 +1,27 @@
+package textinput
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles controls how each part of the text input gets styled
+type Styles struct {
+	Text        lipgloss.Style
+	Placeholder lipgloss.Style
+
+	// Only shown when the input is focused
+	Cursor lipgloss.Style
+
+	Selection lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Text:        lipgloss.NewStyle(),
+		Placeholder: lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)),
+		Cursor:      lipgloss.NewStyle().Reverse(true),
+		Selection:   lipgloss.NewStyle().Background(theme.Color(theme.Selection)),
+	}
+}
@@ -0,0 +1,610 @@
+package textinput
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+	"unicode"
+)
+
+// TextInput is a single-line field that the user can type into
+// Analogous to the <input type="text"> tag in HTML
+// Keys are only acted on while the input is focused, and the value scrolls horizontally to keep the cursor visible
+// when it's wider than the input
+type TextInput interface {
+	components.InteractiveComponent
+
+	GetValue() string
+	// Setting the value programmatically puts the cursor at the end, and doesn't send a ChangedMsg
+	SetValue(value string) TextInput
+
+	GetPlaceholder() string
+	SetPlaceholder(placeholder string) TextInput
+
+	// The width the input asks for during layout (its min & max content width)
+	// If 0, the input asks for enough space to show the placeholder
+	GetWidth() int
+	SetWidth(width int) TextInput
+
+	// The maximum number of characters the value can have, or 0 for no maximum
+	GetMaxLength() int
+	SetMaxLength(maxLength int) TextInput
+
+	// The character shown in place of each character of the value (e.g. '*' for passwords), or 0 to show the value
+	GetMask() rune
+	SetMask(mask rune) TextInput
+
+	// The validator gets run on every change to the value
+	GetValidator() Validator
+	SetValidator(validator Validator) TextInput
+	// The result of running the validator on the current value (nil if it's valid or there's no validator)
+	GetValidationError() error
+
+	// Positions are in characters, where 0 is before the first character
+	GetCursorPosition() int
+	SetCursorPosition(position int) TextInput
+
+	// The selection runs from start (inclusive) to end (exclusive), with start == end when nothing is selected
+	GetSelection() (start int, end int)
+	// The cursor ends up at the end position, so start can be greater than end to select backwards
+	SetSelection(start int, end int) TextInput
+	GetSelectedText() string
+
+	GetStyles() Styles
+	SetStyles(styles Styles) TextInput
+}
+
+// Validator checks a value, returning an error describing what's wrong with it (or nil if it's valid)
+type Validator func(value string) error
+
+// ChangedMsg is sent whenever the user changes the value of a text input
+type ChangedMsg struct {
+	Input TextInput
+	Value string
+
+	// The result of running the input's validator on the new value
+	ValidationError error
+}
+
+// SubmittedMsg is sent when the user presses Enter in a text input
+type SubmittedMsg struct {
+	Input TextInput
+	Value string
+}
+
+// Indicates that the selection anchor isn't set
+const noSelection = -1
+
+type textInputImpl struct {
+	value []rune
+
+	placeholder string
+
+	width int
+
+	maxLength int
+
+	mask rune
+
+	validator       Validator
+	validationError error
+
+	// Nil for the default styles
+	styles *Styles
+
+	isFocused bool
+
+	// The index of the character the cursor is in front of (len(value) when it's after the last character)
+	cursor int
+
+	// Where the selection started, with the selection running between here and the cursor
+	selectionAnchor int
+
+	// The index of the first visible character, which moves so that the cursor stays visible
+	scrollOffset int
+
+	// The width given to the last View, used to tell whether mouse clicks land on the input
+	lastViewWidth int
+}
+
+func New() TextInput {
+	return &textInputImpl{
+		value:           make([]rune, 0),
+		placeholder:     "",
+		width:           0,
+		maxLength:       0,
+		mask:            0,
+		validator:       nil,
+		validationError: nil,
+		styles:          nil,
+		isFocused:       false,
+		cursor:          0,
+		selectionAnchor: noSelection,
+		scrollOffset:    0,
+		lastViewWidth:   0,
+	}
+}
+
+func (t textInputImpl) GetValue() string {
+	return string(t.value)
+}
+
+func (t *textInputImpl) SetValue(value string) TextInput {
+	t.value = t.truncateToMaxLength(sanitizeInput([]rune(value)))
+	t.cursor = len(t.value)
+	t.selectionAnchor = noSelection
+	t.validate()
+	return t
+}
+
+func (t textInputImpl) GetPlaceholder() string {
+	return t.placeholder
+}
+
+func (t *textInputImpl) SetPlaceholder(placeholder string) TextInput {
+	t.placeholder = placeholder
+	return t
+}
+
+func (t textInputImpl) GetWidth() int {
+	return t.width
+}
+
+func (t *textInputImpl) SetWidth(width int) TextInput {
+	t.width = utilities.GetMaxInt(0, width)
+	return t
+}
+
+func (t textInputImpl) GetMaxLength() int {
+	return t.maxLength
+}
+
+func (t *textInputImpl) SetMaxLength(maxLength int) TextInput {
+	t.maxLength = utilities.GetMaxInt(0, maxLength)
+	if truncated := t.truncateToMaxLength(t.value); len(truncated) != len(t.value) {
+		t.value = truncated
+		t.cursor = utilities.GetMinInt(t.cursor, len(t.value))
+		t.selectionAnchor = noSelection
+		t.validate()
+	}
+	return t
+}
+
+func (t textInputImpl) GetMask() rune {
+	return t.mask
+}
+
+func (t *textInputImpl) SetMask(mask rune) TextInput {
+	t.mask = mask
+	return t
+}
+
+func (t textInputImpl) GetValidator() Validator {
+	return t.validator
+}
+
+func (t *textInputImpl) SetValidator(validator Validator) TextInput {
+	t.validator = validator
+	t.validate()
+	return t
+}
+
+func (t textInputImpl) GetValidationError() error {
+	return t.validationError
+}
+
+func (t textInputImpl) GetCursorPosition() int {
+	return t.cursor
+}
+
+func (t *textInputImpl) SetCursorPosition(position int) TextInput {
+	t.cursor = utilities.Clamp(position, 0, len(t.value))
+	t.selectionAnchor = noSelection
+	return t
+}
+
+func (t textInputImpl) GetSelection() (start int, end int) {
+	if t.selectionAnchor == noSelection {
+		return t.cursor, t.cursor
+	}
+	return utilities.GetMinInt(t.selectionAnchor, t.cursor), utilities.GetMaxInt(t.selectionAnchor, t.cursor)
+}
+
+func (t *textInputImpl) SetSelection(start int, end int) TextInput {
+	t.selectionAnchor = utilities.Clamp(start, 0, len(t.value))
+	t.cursor = utilities.Clamp(end, 0, len(t.value))
+	return t
+}
+
+func (t textInputImpl) GetSelectedText() string {
+	start, end := t.GetSelection()
+	return string(t.value[start:end])
+}
+
+func (t textInputImpl) GetStyles() Styles {
+	if t.styles == nil {
+		return DefaultStyles()
+	}
+	return *t.styles
+}
+
+func (t *textInputImpl) SetStyles(styles Styles) TextInput {
+	t.styles = &styles
+	return t
+}
+
+func (t *textInputImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	width := t.width
+	if width == 0 {
+		// The extra cell is for the cursor
+		width = lipgloss.Width(t.placeholder) + 1
+	}
+	return width, width, 1, 1
+}
+
+func (t *textInputImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (t *textInputImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	t.lastViewWidth = width
+
+	var line string
+	if len(t.value) == 0 && t.placeholder != "" {
+		line = t.renderPlaceholder(width)
+	} else {
+		line = t.renderValue(width)
+	}
+	return lipgloss.NewStyle().Width(width).Render(line)
+}
+
+func (t *textInputImpl) Update(msg tea.Msg) tea.Cmd {
+	if !t.isFocused {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return t.handleKey(msg)
+	case tea.MouseMsg:
+		if msg.Type == tea.MouseLeft && components.IsMouseMsgInBounds(msg, t.lastViewWidth, 1) {
+			t.moveTo(t.getIndexAtColumn(msg.X), false)
+		}
+	}
+	return nil
+}
+
+func (t *textInputImpl) SetFocus(isFocused bool) {
+	t.isFocused = isFocused
+}
+
+func (t textInputImpl) IsFocused() bool {
+	return t.isFocused
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (t *textInputImpl) handleKey(msg tea.KeyMsg) tea.Cmd {
+	oldValue := string(t.value)
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		return t.getSubmittedCmd()
+	case tea.KeyRunes:
+		if !msg.Alt {
+			t.insert(msg.Runes)
+			break
+		}
+		// Readline-style word bindings
+		switch string(msg.Runes) {
+		case "b":
+			t.moveTo(t.getPreviousWordStart(), false)
+		case "f":
+			t.moveTo(t.getNextWordEnd(), false)
+		case "d":
+			t.deleteTo(t.getNextWordEnd())
+		}
+	case tea.KeySpace:
+		t.insert([]rune{' '})
+	case tea.KeyBackspace, tea.KeyCtrlH:
+		if msg.Alt {
+			t.deleteTo(t.getPreviousWordStart())
+		} else {
+			t.deleteTo(t.cursor - 1)
+		}
+	case tea.KeyCtrlW:
+		t.deleteTo(t.getPreviousWordStart())
+	case tea.KeyDelete:
+		t.deleteTo(t.cursor + 1)
+	case tea.KeyCtrlU:
+		t.deleteTo(0)
+	case tea.KeyCtrlK:
+		t.deleteTo(len(t.value))
+	case tea.KeyLeft, tea.KeyCtrlB:
+		if msg.Alt {
+			t.moveTo(t.getPreviousWordStart(), false)
+		} else if start, end := t.GetSelection(); start != end {
+			// Like other editors, moving without extending the selection collapses it
+			t.moveTo(start, false)
+		} else {
+			t.moveTo(t.cursor-1, false)
+		}
+	case tea.KeyRight, tea.KeyCtrlF:
+		if msg.Alt {
+			t.moveTo(t.getNextWordEnd(), false)
+		} else if start, end := t.GetSelection(); start != end {
+			t.moveTo(end, false)
+		} else {
+			t.moveTo(t.cursor+1, false)
+		}
+	case tea.KeyCtrlLeft:
+		t.moveTo(t.getPreviousWordStart(), false)
+	case tea.KeyCtrlRight:
+		t.moveTo(t.getNextWordEnd(), false)
+	case tea.KeyHome, tea.KeyCtrlA:
+		t.moveTo(0, false)
+	case tea.KeyEnd, tea.KeyCtrlE:
+		t.moveTo(len(t.value), false)
+	case tea.KeyShiftLeft:
+		t.moveTo(t.cursor-1, true)
+	case tea.KeyShiftRight:
+		t.moveTo(t.cursor+1, true)
+	case tea.KeyCtrlShiftLeft:
+		t.moveTo(t.getPreviousWordStart(), true)
+	case tea.KeyCtrlShiftRight:
+		t.moveTo(t.getNextWordEnd(), true)
+	case tea.KeyShiftHome:
+		t.moveTo(0, true)
+	case tea.KeyShiftEnd:
+		t.moveTo(len(t.value), true)
+	}
+
+	if string(t.value) == oldValue {
+		return nil
+	}
+	t.validate()
+	return t.getChangedCmd()
+}
+
+// Moves the cursor, either extending the selection or clearing it
+func (t *textInputImpl) moveTo(position int, shouldExtendSelection bool) {
+	if !shouldExtendSelection {
+		t.selectionAnchor = noSelection
+	} else if t.selectionAnchor == noSelection {
+		t.selectionAnchor = t.cursor
+	}
+	t.cursor = utilities.Clamp(position, 0, len(t.value))
+}
+
+// Replaces the selection (if any) with the runes
+func (t *textInputImpl) insert(runes []rune) {
+	t.deleteSelection()
+
+	runes = sanitizeInput(runes)
+	if t.maxLength > 0 {
+		room := utilities.GetMaxInt(0, t.maxLength-len(t.value))
+		runes = runes[:utilities.GetMinInt(room, len(runes))]
+	}
+
+	newValue := make([]rune, 0, len(t.value)+len(runes))
+	newValue = append(newValue, t.value[:t.cursor]...)
+	newValue = append(newValue, runes...)
+	newValue = append(newValue, t.value[t.cursor:]...)
+	t.value = newValue
+	t.cursor += len(runes)
+}
+
+// Deletes the selection if there is one, and otherwise everything between the cursor and the position
+func (t *textInputImpl) deleteTo(position int) {
+	if t.deleteSelection() {
+		return
+	}
+	position = utilities.Clamp(position, 0, len(t.value))
+	start, end := utilities.GetMinInt(t.cursor, position), utilities.GetMaxInt(t.cursor, position)
+	t.value = append(t.value[:start:start], t.value[end:]...)
+	t.cursor = start
+}
+
+// Returns true if there was a selection to delete
+func (t *textInputImpl) deleteSelection() bool {
+	start, end := t.GetSelection()
+	t.selectionAnchor = noSelection
+	if start == end {
+		return false
+	}
+	t.value = append(t.value[:start:start], t.value[end:]...)
+	t.cursor = start
+	return true
+}
+
+func (t textInputImpl) getPreviousWordStart() int {
+	// Jumping by word would reveal where the spaces in a masked value are
+	if t.mask != 0 {
+		return 0
+	}
+	idx := t.cursor
+	for idx > 0 && unicode.IsSpace(t.value[idx-1]) {
+		idx--
+	}
+	for idx > 0 && !unicode.IsSpace(t.value[idx-1]) {
+		idx--
+	}
+	return idx
+}
+
+func (t textInputImpl) getNextWordEnd() int {
+	if t.mask != 0 {
+		return len(t.value)
+	}
+	idx := t.cursor
+	for idx < len(t.value) && unicode.IsSpace(t.value[idx]) {
+		idx++
+	}
+	for idx < len(t.value) && !unicode.IsSpace(t.value[idx]) {
+		idx++
+	}
+	return idx
+}
+
+func (t *textInputImpl) validate() {
+	if t.validator == nil {
+		t.validationError = nil
+		return
+	}
+	t.validationError = t.validator(string(t.value))
+}
+
+func (t *textInputImpl) getChangedCmd() tea.Cmd {
+	msg := ChangedMsg{
+		Input:           t,
+		Value:           string(t.value),
+		ValidationError: t.validationError,
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func (t *textInputImpl) getSubmittedCmd() tea.Cmd {
+	msg := SubmittedMsg{
+		Input: t,
+		Value: string(t.value),
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func (t textInputImpl) truncateToMaxLength(runes []rune) []rune {
+	if t.maxLength == 0 || len(runes) <= t.maxLength {
+		return runes
+	}
+	return runes[:t.maxLength]
+}
+
+// The runes that get shown for the value (which differ from the value when it's masked)
+func (t textInputImpl) getDisplayedRunes() []rune {
+	if t.mask == 0 {
+		return t.value
+	}
+	return []rune(strings.Repeat(string(t.mask), len(t.value)))
+}
+
+func (t textInputImpl) renderPlaceholder(width int) string {
+	placeholder := []rune(runewidth.Truncate(t.placeholder, width, ""))
+	if !t.isFocused || len(placeholder) == 0 {
+		return t.GetStyles().Placeholder.Render(string(placeholder))
+	}
+	return t.GetStyles().Cursor.Render(string(placeholder[:1])) + t.GetStyles().Placeholder.Render(string(placeholder[1:]))
+}
+
+func (t *textInputImpl) renderValue(width int) string {
+	displayed := t.getDisplayedRunes()
+	t.updateScrollOffset(displayed, width)
+	selectionStart, selectionEnd := t.GetSelection()
+
+	// Runs of characters with the same style get rendered together
+	styles := t.GetStyles()
+	var builder strings.Builder
+	var runStyle *lipgloss.Style
+	run := make([]rune, 0)
+	flushRun := func() {
+		if len(run) > 0 {
+			builder.WriteString(runStyle.Render(string(run)))
+		}
+		run = run[:0]
+	}
+
+	usedWidth := 0
+	for idx := t.scrollOffset; idx < len(displayed); idx++ {
+		charWidth := runewidth.RuneWidth(displayed[idx])
+		if usedWidth+charWidth > width {
+			break
+		}
+		style := &styles.Text
+		if idx >= selectionStart && idx < selectionEnd {
+			style = &styles.Selection
+		}
+		if t.isFocused && idx == t.cursor {
+			style = &styles.Cursor
+		}
+		if style != runStyle {
+			flushRun()
+			runStyle = style
+		}
+		run = append(run, displayed[idx])
+		usedWidth += charWidth
+	}
+	flushRun()
+
+	if t.isFocused && t.cursor == len(displayed) && usedWidth < width {
+		builder.WriteString(styles.Cursor.Render(" "))
+	}
+	return builder.String()
+}
+
+// Scrolls just enough that the cursor is visible, without leaving empty space at the end while there's text hidden
+// off to the left
+func (t *textInputImpl) updateScrollOffset(displayed []rune, width int) {
+	// When the cursor is past the last character, it needs a cell of its own
+	cursorWidth := 1
+	if t.cursor < len(displayed) {
+		cursorWidth = runewidth.RuneWidth(displayed[t.cursor])
+	}
+
+	t.scrollOffset = utilities.Clamp(t.scrollOffset, 0, t.cursor)
+	for t.scrollOffset < t.cursor && getRunesWidth(displayed[t.scrollOffset:t.cursor])+cursorWidth > width {
+		t.scrollOffset++
+	}
+	for t.scrollOffset > 0 && getRunesWidth(displayed[t.scrollOffset-1:])+1 <= width {
+		t.scrollOffset--
+	}
+}
+
+// Gets the index of the character at the given column of the last View
+func (t textInputImpl) getIndexAtColumn(column int) int {
+	displayed := t.getDisplayedRunes()
+	usedWidth := 0
+	for idx := t.scrollOffset; idx < len(displayed); idx++ {
+		usedWidth += runewidth.RuneWidth(displayed[idx])
+		if usedWidth > column {
+			return idx
+		}
+	}
+	return len(displayed)
+}
+
+func getRunesWidth(runes []rune) int {
+	result := 0
+	for _, r := range runes {
+		result += runewidth.RuneWidth(r)
+	}
+	return result
+}
+
+// Newlines, tabs, etc. have no place in a single line of input
+func sanitizeInput(runes []rune) []rune {
+	result := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		switch {
+		case r == '\t':
+			result = append(result, ' ')
+		case unicode.IsControl(r):
+			continue
+		default:
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package textinput
+
+import (
+	"errors"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components/flexbox"
+	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
+	"github.com/mieubrisse/box-layout-test/components/stylebox"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSizeFromPlaceholder(t *testing.T) {
+	input := New().SetPlaceholder("Your name")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(10, 10, 1, 1),
+		test_assertions.GetHeightAtWidthAssertions(
+			1, 1,
+			10, 1,
+			30, 1,
+		),
+		test_assertions.GetRenderedContentAssertion(12, 1, "Your name   "),
+		test_assertions.GetRenderedContentAssertion(4, 1, "Your"),
+	)
+
+	test_assertions.CheckAll(t, assertions, input)
+}
+
+func TestSizeFromConfiguredWidth(t *testing.T) {
+	input := New().SetPlaceholder("Your name").SetWidth(20).SetValue("this is longer than the placeholder")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(20, 20, 1, 1),
+	)
+
+	test_assertions.CheckAll(t, assertions, input)
+}
+
+func TestTyping(t *testing.T) {
+	input := newFocusedInput()
+	typeString(input, "hello world")
+	require.Equal(t, "hello world", input.GetValue())
+	require.Equal(t, 11, input.GetCursorPosition())
+
+	sendKeys(input, tea.KeyLeft, tea.KeyLeft, tea.KeyBackspace, tea.KeyDelete)
+	require.Equal(t, "hello wod", input.GetValue())
+	require.Equal(t, 8, input.GetCursorPosition())
+
+	sendKeys(input, tea.KeyHome)
+	typeString(input, ">")
+	sendKeys(input, tea.KeyEnd)
+	typeString(input, "<")
+	require.Equal(t, ">hello wod<", input.GetValue())
+}
+
+func TestUnfocusedInputIgnoresKeys(t *testing.T) {
+	input := New()
+	typeString(input, "ignored")
+	require.Equal(t, "", input.GetValue())
+}
+
+func TestWordJumps(t *testing.T) {
+	input := newFocusedInput().SetValue("one two  three")
+
+	sendKeys(input, tea.KeyCtrlLeft)
+	require.Equal(t, 9, input.GetCursorPosition())
+	sendKeys(input, tea.KeyCtrlLeft)
+	require.Equal(t, 4, input.GetCursorPosition())
+	sendKeys(input, tea.KeyCtrlRight)
+	require.Equal(t, 7, input.GetCursorPosition())
+
+	sendKeys(input, tea.KeyCtrlW)
+	require.Equal(t, "one   three", input.GetValue())
+	require.Equal(t, 4, input.GetCursorPosition())
+}
+
+func TestSelection(t *testing.T) {
+	input := newFocusedInput().SetValue("one two three")
+
+	sendKeys(input, tea.KeyCtrlShiftLeft, tea.KeyShiftLeft)
+	start, end := input.GetSelection()
+	require.Equal(t, 7, start)
+	require.Equal(t, 13, end)
+	require.Equal(t, " three", input.GetSelectedText())
+
+	typeString(input, "!")
+	require.Equal(t, "one two!", input.GetValue())
+
+	input.SetSelection(0, 3)
+	sendKeys(input, tea.KeyBackspace)
+	require.Equal(t, " two!", input.GetValue())
+
+	// Moving without shift collapses the selection to its edge
+	input.SetSelection(1, 4)
+	sendKeys(input, tea.KeyLeft)
+	start, end = input.GetSelection()
+	require.Equal(t, 1, start)
+	require.Equal(t, 1, end)
+}
+
+func TestMaxLength(t *testing.T) {
+	input := newFocusedInput().SetMaxLength(5)
+	typeString(input, "abcdefgh")
+	require.Equal(t, "abcde", input.GetValue())
+
+	input.SetValue("0123456789")
+	require.Equal(t, "01234", input.GetValue())
+
+	input.SetMaxLength(3)
+	require.Equal(t, "012", input.GetValue())
+}
+
+func TestMask(t *testing.T) {
+	input := New().SetMask('*').SetValue("secret words")
+	require.Equal(t, "secret words", input.GetValue())
+	require.Equal(t, "************ ", input.View(13, 1))
+
+	// Word jumps would give away where the spaces are
+	input.SetFocus(true)
+	sendKeys(input, tea.KeyCtrlLeft)
+	require.Equal(t, 0, input.GetCursorPosition())
+}
+
+func TestValidation(t *testing.T) {
+	input := newFocusedInput().SetValidator(func(value string) error {
+		if len(value) < 3 {
+			return errors.New("too short")
+		}
+		return nil
+	})
+	require.EqualError(t, input.GetValidationError(), "too short")
+
+	cmd := input.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("abc")})
+	require.NoError(t, input.GetValidationError())
+	require.Equal(t, ChangedMsg{Input: input, Value: "abc", ValidationError: nil}, cmd())
+
+	cmd = input.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	changedMsg := cmd().(ChangedMsg)
+	require.EqualError(t, changedMsg.ValidationError, "too short")
+
+	// Cursor movement doesn't change the value
+	require.Nil(t, input.Update(tea.KeyMsg{Type: tea.KeyLeft}))
+
+	cmd = input.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, SubmittedMsg{Input: input, Value: "ab"}, cmd())
+}
+
+func TestHorizontalScrolling(t *testing.T) {
+	input := newFocusedInput().SetValue("abcdefghij")
+	input.GetContentMinMax()
+	input.GetContentHeightForGivenWidth(5)
+
+	// The cursor is past the end, so it takes up a cell of its own
+	require.Equal(t, "ghij ", input.View(5, 1))
+
+	sendKeys(input, tea.KeyHome)
+	require.Equal(t, "abcde", input.View(5, 1))
+
+	// The view only scrolls once the cursor would leave it
+	sendKeys(input, tea.KeyRight, tea.KeyRight, tea.KeyRight, tea.KeyRight)
+	require.Equal(t, "abcde", input.View(5, 1))
+	sendKeys(input, tea.KeyRight)
+	require.Equal(t, "bcdef", input.View(5, 1))
+
+	// Deleting scrolls back rather than leaving empty space
+	sendKeys(input, tea.KeyCtrlK)
+	require.Equal(t, "abcde", input.GetValue())
+	require.Equal(t, "bcde ", input.View(5, 1))
+	sendKeys(input, tea.KeyBackspace)
+	require.Equal(t, "abcd ", input.View(5, 1))
+}
+
+func TestRoutingThroughContainers(t *testing.T) {
+	input := New().SetWidth(10).SetValue("abcdefgh")
+	box := flexbox.NewWithContents(
+		flexbox_item.New(text.New("Name:")),
+		flexbox_item.New(
+			stylebox.New(input).SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder()).PaddingLeft(1)),
+		),
+	)
+
+	box.SetFocus(true)
+	require.True(t, input.IsFocused())
+	require.True(t, box.IsFocused())
+
+	box.GetContentMinMax()
+	box.GetContentHeightForGivenWidth(30)
+	box.View(30, 3)
+
+	// The input starts after the 5-wide text, a 1-wide border, and 1 cell of padding
+	box.Update(tea.MouseMsg{X: 10, Y: 1, Type: tea.MouseLeft})
+	require.Equal(t, 3, input.GetCursorPosition())
+
+	box.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	require.Equal(t, "abc-defgh", input.GetValue())
+
+	box.SetFocus(false)
+	require.False(t, input.IsFocused())
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func newFocusedInput() TextInput {
+	input := New()
+	input.SetFocus(true)
+	return input
+}
+
+func typeString(input TextInput, str string) {
+	for _, r := range str {
+		input.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func sendKeys(input TextInput, keyTypes ...tea.KeyType) {
+	for _, keyType := range keyTypes {
+		input.Update(tea.KeyMsg{Type: keyType})
+	}
+}
@@ -0,0 +1,185 @@
+package toggle
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"strings"
+)
+
+// Toggle is a labelled on/off switch that flips when activated, by Enter or Space while focused or by clicking it
+// It's the same as a checkbox underneath, but reads as a setting that takes effect immediately
+type Toggle interface {
+	components.InteractiveComponent
+
+	GetLabel() string
+	SetLabel(label string) Toggle
+
+	// Setting the state programmatically doesn't send a ChangedMsg
+	IsOn() bool
+	SetOn(isOn bool) Toggle
+
+	// The text shown inside the switch for each state
+	GetStateLabels() (onLabel string, offLabel string)
+	SetStateLabels(onLabel string, offLabel string) Toggle
+
+	IsDisabled() bool
+	SetDisabled(isDisabled bool) Toggle
+
+	IsPressed() bool
+
+	GetStyles() control.Styles
+	SetStyles(styles control.Styles) Toggle
+}
+
+// ChangedMsg is sent when the user flips a toggle
+type ChangedMsg struct {
+	Toggle Toggle
+	IsOn   bool
+}
+
+type toggleImpl struct {
+	label string
+
+	isOn bool
+
+	onLabel  string
+	offLabel string
+
+	// Nil for the default styles
+	styles *control.Styles
+
+	state *control.State
+}
+
+func New(label string) Toggle {
+	return &toggleImpl{
+		label:    sanitizeLabel(label),
+		isOn:     false,
+		onLabel:  "ON",
+		offLabel: "OFF",
+		styles:   nil,
+		state:    control.NewState(),
+	}
+}
+
+func (t toggleImpl) GetLabel() string {
+	return t.label
+}
+
+func (t *toggleImpl) SetLabel(label string) Toggle {
+	t.label = sanitizeLabel(label)
+	return t
+}
+
+func (t toggleImpl) IsOn() bool {
+	return t.isOn
+}
+
+func (t *toggleImpl) SetOn(isOn bool) Toggle {
+	t.isOn = isOn
+	return t
+}
+
+func (t toggleImpl) GetStateLabels() (onLabel string, offLabel string) {
+	return t.onLabel, t.offLabel
+}
+
+func (t *toggleImpl) SetStateLabels(onLabel string, offLabel string) Toggle {
+	t.onLabel = sanitizeLabel(onLabel)
+	t.offLabel = sanitizeLabel(offLabel)
+	return t
+}
+
+func (t toggleImpl) IsDisabled() bool {
+	return t.state.IsDisabled()
+}
+
+func (t *toggleImpl) SetDisabled(isDisabled bool) Toggle {
+	t.state.SetDisabled(isDisabled)
+	return t
+}
+
+func (t toggleImpl) IsPressed() bool {
+	return t.state.IsPressed()
+}
+
+func (t toggleImpl) GetStyles() control.Styles {
+	if t.styles == nil {
+		return control.DefaultStyles()
+	}
+	return *t.styles
+}
+
+func (t *toggleImpl) SetStyles(styles control.Styles) Toggle {
+	t.styles = &styles
+	return t
+}
+
+func (t *toggleImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	// The switch is padded to fit the longer state label, so flipping doesn't change the size
+	width := lipgloss.Width(t.getContent())
+	return width, width, 1, 1
+}
+
+func (t *toggleImpl) GetContentHeightForGivenWidth(width int) int {
+	if width == 0 {
+		return 0
+	}
+	return 1
+}
+
+func (t *toggleImpl) View(width int, height int) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	t.state.RecordViewSize(width, 1)
+	return control.FitToWidth(t.GetStyles().Get(t.state).Render(t.getContent()), width)
+}
+
+func (t *toggleImpl) Update(msg tea.Msg) tea.Cmd {
+	activation, cmd := t.state.Update(msg)
+	if !activation.IsActivated {
+		return cmd
+	}
+	t.isOn = !t.isOn
+	changedMsg := ChangedMsg{Toggle: t, IsOn: t.isOn}
+	return tea.Batch(cmd, func() tea.Msg {
+		return changedMsg
+	})
+}
+
+func (t *toggleImpl) SetFocus(isFocused bool) {
+	t.state.SetFocus(isFocused)
+}
+
+func (t toggleImpl) IsFocused() bool {
+	return t.state.IsFocused()
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (t toggleImpl) getContent() string {
+	stateLabel := t.offLabel
+	if t.isOn {
+		stateLabel = t.onLabel
+	}
+	switchWidth := lipgloss.Width(t.onLabel)
+	if offWidth := lipgloss.Width(t.offLabel); offWidth > switchWidth {
+		switchWidth = offWidth
+	}
+	switchStr := "[" + stateLabel + strings.Repeat(" ", switchWidth-lipgloss.Width(stateLabel)) + "]"
+
+	if t.label == "" {
+		return switchStr
+	}
+	return switchStr + " " + t.label
+}
+
+// Toggles are a single line
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
@@ -0,0 +1,51 @@
+package toggle
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSizeIsExact(t *testing.T) {
+	toggle := New("Dark mode")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(15, 15, 1, 1),
+		test_assertions.GetRenderedContentAssertion(15, 1, "[OFF] Dark mode"),
+	)
+	test_assertions.CheckAll(t, assertions, toggle)
+
+	// The switch is padded to the longer label, so flipping doesn't change the size
+	toggle.SetOn(true)
+	assertions = test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(15, 15, 1, 1),
+		test_assertions.GetRenderedContentAssertion(15, 1, "[ON ] Dark mode"),
+	)
+	test_assertions.CheckAll(t, assertions, toggle)
+}
+
+func TestCustomStateLabels(t *testing.T) {
+	toggle := New("").SetStateLabels("enabled", "off")
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetContentSizeAssertions(9, 9, 1, 1),
+		test_assertions.GetRenderedContentAssertion(9, 1, "[off    ]"),
+	)
+	test_assertions.CheckAll(t, assertions, toggle)
+}
+
+func TestActivationFlips(t *testing.T) {
+	toggle := New("Dark mode")
+	toggle.SetFocus(true)
+
+	require.Contains(t, test_assertions.GetMsgs(toggle.Update(tea.KeyMsg{Type: tea.KeyEnter})), ChangedMsg{Toggle: toggle, IsOn: true})
+	require.True(t, toggle.IsOn())
+	require.True(t, toggle.IsPressed())
+
+	toggle.SetDisabled(true)
+	require.False(t, toggle.IsPressed())
+	require.Nil(t, toggle.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.True(t, toggle.IsOn())
+}
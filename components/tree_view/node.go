@@ -0,0 +1,151 @@
+package tree_view
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+)
+
+// Loader starts loading the children of a node, returning the command that will send the LoadedMsg with them
+type Loader func(node Node) tea.Cmd
+
+// LoadedMsg delivers the children of a node that were loaded by its Loader
+type LoadedMsg struct {
+	Node     Node
+	Children []Node
+
+	// If set, the node collapses again (and expanding it will retry the load)
+	Err error
+}
+
+// Node is a node in a tree view, whose label can be any component
+type Node interface {
+	GetLabel() components.Component
+
+	GetChildren() []Node
+	// Marks the children as loaded
+	SetChildren(children []Node) Node
+	AddChild(child Node) Node
+
+	IsExpanded() bool
+	SetExpanded(isExpanded bool) Node
+
+	// When set, the children get loaded the first time the node gets expanded in the tree view
+	GetLoader() Loader
+	SetLoader(loader Loader) Node
+
+	IsLoading() bool
+
+	// The error from the last load, if it failed
+	GetLoadError() error
+
+	// Whether the node can be expanded, which is true if it has children or still has them to load
+	IsExpandable() bool
+
+	// Starts loading the children if they haven't been loaded yet, returning nil if there's nothing to load
+	startLoad() tea.Cmd
+	finishLoad(msg LoadedMsg)
+}
+
+type nodeImpl struct {
+	label components.Component
+
+	children []Node
+
+	isExpanded bool
+
+	loader Loader
+
+	isLoaded bool
+
+	isLoading bool
+
+	loadErr error
+}
+
+func NewNode(label components.Component, children ...Node) Node {
+	return &nodeImpl{
+		label:      label,
+		children:   children,
+		isExpanded: false,
+		loader:     nil,
+		isLoaded:   false,
+		isLoading:  false,
+		loadErr:    nil,
+	}
+}
+
+func (n nodeImpl) GetLabel() components.Component {
+	return n.label
+}
+
+func (n nodeImpl) GetChildren() []Node {
+	return n.children
+}
+
+func (n *nodeImpl) SetChildren(children []Node) Node {
+	n.children = children
+	n.isLoaded = true
+	return n
+}
+
+func (n *nodeImpl) AddChild(child Node) Node {
+	n.children = append(n.children, child)
+	return n
+}
+
+func (n nodeImpl) IsExpanded() bool {
+	return n.isExpanded
+}
+
+func (n *nodeImpl) SetExpanded(isExpanded bool) Node {
+	n.isExpanded = isExpanded
+	return n
+}
+
+func (n nodeImpl) GetLoader() Loader {
+	return n.loader
+}
+
+func (n *nodeImpl) SetLoader(loader Loader) Node {
+	n.loader = loader
+	return n
+}
+
+func (n nodeImpl) IsLoading() bool {
+	return n.isLoading
+}
+
+func (n nodeImpl) GetLoadError() error {
+	return n.loadErr
+}
+
+func (n nodeImpl) IsExpandable() bool {
+	return len(n.children) > 0 || n.needsLoad()
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+func (n nodeImpl) needsLoad() bool {
+	return n.loader != nil && !n.isLoaded
+}
+
+func (n *nodeImpl) startLoad() tea.Cmd {
+	if !n.needsLoad() || n.isLoading {
+		return nil
+	}
+	n.isLoading = true
+	n.loadErr = nil
+	return n.loader(n)
+}
+
+func (n *nodeImpl) finishLoad(msg LoadedMsg) {
+	n.isLoading = false
+	if msg.Err != nil {
+		n.loadErr = msg.Err
+		n.isExpanded = false
+		return
+	}
+	n.SetChildren(msg.Children)
+}
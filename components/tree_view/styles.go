@@ -0,0 +1,29 @@
+package tree_view
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/theme"
+)
+
+// Styles controls how the parts of the tree around the labels get styled
+type Styles struct {
+	// Used for the lines connecting the nodes
+	Guide lipgloss.Style
+
+	// Used for the expand/collapse markers
+	Marker lipgloss.Style
+
+	// Applied over the selected node's marker & label, depending on whether the tree view has the focus
+	Selected        lipgloss.Style
+	FocusedSelected lipgloss.Style
+}
+
+// DefaultStyles are built from the active theme's colors
+func DefaultStyles() Styles {
+	return Styles{
+		Guide:           lipgloss.NewStyle().Foreground(theme.Color(theme.Muted)),
+		Marker:          lipgloss.NewStyle(),
+		Selected:        lipgloss.NewStyle().Bold(true),
+		FocusedSelected: lipgloss.NewStyle().Bold(true).Foreground(theme.Color(theme.Primary)),
+	}
+}
@@ -0,0 +1,542 @@
+package tree_view
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/control"
+	"github.com/mieubrisse/box-layout-test/utilities"
+	"strings"
+)
+
+const (
+	branchGuide     = "├── "
+	lastBranchGuide = "└── "
+	trunkGuide      = "│   "
+	emptyGuide      = "    "
+
+	expandedMarker  = "▾ "
+	collapsedMarker = "▸ "
+	loadingMarker   = "⋯ "
+	leafMarker      = "  "
+)
+
+// SelectHandler gets called when the user selects a node, returning the command to run as a result (if any)
+type SelectHandler func(node Node) tea.Cmd
+
+// TreeView shows a tree of nodes, indented under their parents with guide lines connecting them
+// While it's focused, Up & Down move the selection, Right expands the selected node (or moves to its first child),
+// Left collapses it (or moves to its parent), and Enter or Space toggles it; clicking a node selects it, and clicking
+// its marker toggles it
+// Nodes with a Loader get their children loaded the first time they're expanded
+// When the tree is taller than the height it's given, it scrolls to keep the selected node in view
+// The labels get the layout context, and broadcast messages (see components.IsBroadcastMsg) reach every node's label,
+// including the ones in collapsed subtrees
+type TreeView interface {
+	components.InteractiveComponent
+	components.ContextualComponent
+	components.Container
+
+	GetRoots() []Node
+	SetRoots(roots []Node) TreeView
+	AddRoot(root Node) TreeView
+
+	// Returns nil if the tree is empty
+	GetSelected() Node
+	// Setting the selection programmatically doesn't call the select handler
+	SetSelected(node Node) TreeView
+
+	GetSelectHandler() SelectHandler
+	SetSelectHandler(handler SelectHandler) TreeView
+
+	// Returns the command that loads the node's children, if they need loading
+	Expand(node Node) tea.Cmd
+	Collapse(node Node) TreeView
+
+	GetStyles() Styles
+	SetStyles(styles Styles) TreeView
+}
+
+type treeViewImpl struct {
+	roots []Node
+
+	// Tracked by node rather than position, so that the selection stays put as nodes above it expand & collapse
+	selected Node
+
+	selectHandler SelectHandler
+
+	// Nil for the default styles
+	styles *Styles
+
+	isFocused bool
+
+	// The line of the tree shown at the top when the tree is taller than its view
+	scrollOffset int
+
+	// The visible rows, as of the last GetContentMinMax
+	rowsCache []row
+
+	// The width the rows' heights were calculated for
+	rowHeightsWidthCache int
+
+	lastViewWidth  int
+	lastViewHeight int
+}
+
+// A visible node & where it goes
+type row struct {
+	node Node
+
+	// -1 for roots
+	parentRowIdx int
+
+	// The guide lines before the node's first line, and before the rest of its lines
+	guide             string
+	continuationGuide string
+
+	height int
+
+	// The line of the tree the row starts on
+	y int
+}
+
+func New(roots ...Node) TreeView {
+	return &treeViewImpl{
+		roots:                roots,
+		selected:             nil,
+		selectHandler:        nil,
+		styles:               nil,
+		isFocused:            false,
+		scrollOffset:         0,
+		rowsCache:            nil,
+		rowHeightsWidthCache: -1,
+		lastViewWidth:        0,
+		lastViewHeight:       0,
+	}
+}
+
+func (t treeViewImpl) GetRoots() []Node {
+	return t.roots
+}
+
+func (t *treeViewImpl) SetRoots(roots []Node) TreeView {
+	t.roots = roots
+	t.selected = nil
+	return t
+}
+
+func (t *treeViewImpl) AddRoot(root Node) TreeView {
+	t.roots = append(t.roots, root)
+	return t
+}
+
+func (t treeViewImpl) GetSelected() Node {
+	rows := t.getRows()
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows[t.getSelectedRowIdx(rows)].node
+}
+
+func (t *treeViewImpl) SetSelected(node Node) TreeView {
+	t.selected = node
+	return t
+}
+
+func (t treeViewImpl) GetSelectHandler() SelectHandler {
+	return t.selectHandler
+}
+
+func (t *treeViewImpl) SetSelectHandler(handler SelectHandler) TreeView {
+	t.selectHandler = handler
+	return t
+}
+
+func (t *treeViewImpl) Expand(node Node) tea.Cmd {
+	if !node.IsExpandable() {
+		return nil
+	}
+	node.SetExpanded(true)
+	return node.startLoad()
+}
+
+func (t *treeViewImpl) Collapse(node Node) TreeView {
+	// The selection can't stay on a node that's being hidden
+	if t.selected != nil && t.selected != node && isDescendant(node, t.selected) {
+		t.selected = node
+	}
+	node.SetExpanded(false)
+	return t
+}
+
+func (t treeViewImpl) GetStyles() Styles {
+	if t.styles == nil {
+		return DefaultStyles()
+	}
+	return *t.styles
+}
+
+func (t *treeViewImpl) SetStyles(styles Styles) TreeView {
+	t.styles = &styles
+	return t
+}
+
+func (t *treeViewImpl) GetContentMinMax() (minWidth, maxWidth, minHeight, maxHeight int) {
+	return t.GetContentMinMaxInContext(components.NewLayoutContext())
+}
+
+func (t *treeViewImpl) GetContentHeightForGivenWidth(width int) int {
+	return t.GetContentHeightForGivenWidthInContext(components.NewLayoutContext(), width)
+}
+
+func (t *treeViewImpl) View(width int, height int) string {
+	return t.ViewInContext(components.NewLayoutContext(), width, height)
+}
+
+func (t *treeViewImpl) GetContentMinMaxInContext(ctx components.LayoutContext) (minWidth, maxWidth, minHeight, maxHeight int) {
+	t.rowsCache = t.getRows()
+	t.rowHeightsWidthCache = -1
+	for _, row := range t.rowsCache {
+		labelMinWidth, labelMaxWidth, labelMinHeight, labelMaxHeight := components.GetChildContentMinMax(row.node.GetLabel(), ctx)
+		prefixWidth := row.getPrefixWidth()
+		minWidth = utilities.GetMaxInt(minWidth, prefixWidth+labelMinWidth)
+		maxWidth = utilities.GetMaxInt(maxWidth, prefixWidth+labelMaxWidth)
+
+		// Every node gets at least a line, for its marker
+		minHeight += utilities.GetMaxInt(1, labelMinHeight)
+		maxHeight += utilities.GetMaxInt(1, labelMaxHeight)
+	}
+	return
+}
+
+func (t *treeViewImpl) GetContentHeightForGivenWidthInContext(ctx components.LayoutContext, width int) int {
+	if width == 0 {
+		return 0
+	}
+	t.calculateRowHeights(ctx, width)
+	return t.getTotalHeight()
+}
+
+func (t *treeViewImpl) ViewInContext(ctx components.LayoutContext, width int, height int) string {
+	t.lastViewWidth, t.lastViewHeight = width, height
+	if width == 0 || height == 0 {
+		return ""
+	}
+	if t.rowHeightsWidthCache != width {
+		t.calculateRowHeights(ctx, width)
+	}
+
+	lines := make([]string, 0, height)
+	if len(t.rowsCache) > 0 {
+		t.scrollToRow(t.getSelectedRowIdx(t.rowsCache), height)
+	}
+	for rowIdx, row := range t.rowsCache {
+		if row.y+row.height <= t.scrollOffset {
+			continue
+		}
+		if row.y >= t.scrollOffset+height {
+			break
+		}
+		rowLines := t.renderRow(ctx, rowIdx, width)
+		for lineIdx, line := range rowLines {
+			y := row.y + lineIdx
+			if y >= t.scrollOffset && y < t.scrollOffset+height {
+				lines = append(lines, line)
+			}
+		}
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t *treeViewImpl) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case LoadedMsg:
+		if msg.Node != nil && msg.Node.IsLoading() {
+			msg.Node.finishLoad(msg)
+		}
+		return nil
+	case tea.KeyMsg:
+		if !t.isFocused {
+			return nil
+		}
+		return t.handleKey(msg)
+	case tea.MouseMsg:
+		return t.handleMouse(msg)
+	}
+
+	if !components.IsBroadcastMsg(msg) {
+		return nil
+	}
+	labels := t.GetChildComponents()
+	cmds := make([]tea.Cmd, len(labels))
+	for idx, label := range labels {
+		cmds[idx] = components.UpdateChild(label, msg, 0, 0)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (t *treeViewImpl) SetFocus(isFocused bool) {
+	t.isFocused = isFocused && len(t.roots) > 0
+}
+
+func (t treeViewImpl) IsFocused() bool {
+	return t.isFocused
+}
+
+// Gets the labels of all the nodes, whether they're visible or not
+func (t treeViewImpl) GetChildComponents() []components.Component {
+	result := make([]components.Component, 0)
+	var addLabels func(nodes []Node)
+	addLabels = func(nodes []Node) {
+		for _, node := range nodes {
+			result = append(result, node.GetLabel())
+			addLabels(node.GetChildren())
+		}
+	}
+	addLabels(t.roots)
+	return result
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// Gets the rows of the nodes that are visible (i.e. all their ancestors are expanded)
+func (t treeViewImpl) getRows() []row {
+	result := make([]row, 0)
+	var addRows func(nodes []Node, parentRowIdx int, ancestorsGuide string, isRoot bool)
+	addRows = func(nodes []Node, parentRowIdx int, ancestorsGuide string, isRoot bool) {
+		for idx, node := range nodes {
+			isLast := idx == len(nodes)-1
+			guide, continuationGuide, childrenGuide := "", "", ""
+			if !isRoot {
+				guide, continuationGuide, childrenGuide = ancestorsGuide+branchGuide, ancestorsGuide+trunkGuide, ancestorsGuide+trunkGuide
+				if isLast {
+					guide, continuationGuide, childrenGuide = ancestorsGuide+lastBranchGuide, ancestorsGuide+emptyGuide, ancestorsGuide+emptyGuide
+				}
+			}
+			result = append(result, row{
+				node:              node,
+				parentRowIdx:      parentRowIdx,
+				guide:             guide,
+				continuationGuide: continuationGuide,
+				height:            1,
+				y:                 0,
+			})
+			if node.IsExpanded() {
+				addRows(node.GetChildren(), len(result)-1, childrenGuide, false)
+			}
+		}
+	}
+	addRows(t.roots, -1, "", true)
+	return result
+}
+
+func (r row) getPrefixWidth() int {
+	return lipgloss.Width(r.guide) + lipgloss.Width(leafMarker)
+}
+
+func (t *treeViewImpl) calculateRowHeights(ctx components.LayoutContext, width int) {
+	y := 0
+	for idx := range t.rowsCache {
+		row := &t.rowsCache[idx]
+		labelWidth := utilities.GetMaxInt(0, width-row.getPrefixWidth())
+		labelHeight := 0
+		if labelWidth > 0 {
+			labelHeight = components.GetChildContentHeightForGivenWidth(row.node.GetLabel(), ctx, labelWidth)
+		}
+		row.height = utilities.GetMaxInt(1, labelHeight)
+		row.y = y
+		y += row.height
+	}
+	t.rowHeightsWidthCache = width
+}
+
+func (t treeViewImpl) getTotalHeight() int {
+	if len(t.rowsCache) == 0 {
+		return 0
+	}
+	lastRow := t.rowsCache[len(t.rowsCache)-1]
+	return lastRow.y + lastRow.height
+}
+
+// Falls back to the first row if the selected node isn't visible
+func (t treeViewImpl) getSelectedRowIdx(rows []row) int {
+	for idx, row := range rows {
+		if row.node == t.selected {
+			return idx
+		}
+	}
+	return 0
+}
+
+func (t *treeViewImpl) scrollToRow(rowIdx int, height int) {
+	row := t.rowsCache[rowIdx]
+	if row.y < t.scrollOffset {
+		t.scrollOffset = row.y
+	}
+	if row.y+row.height > t.scrollOffset+height {
+		// Tall rows show from their top
+		t.scrollOffset = utilities.GetMinInt(row.y, row.y+row.height-height)
+	}
+	t.scrollOffset = utilities.Clamp(t.scrollOffset, 0, utilities.GetMaxInt(0, t.getTotalHeight()-height))
+}
+
+func (t treeViewImpl) renderRow(ctx components.LayoutContext, rowIdx int, width int) []string {
+	row := t.rowsCache[rowIdx]
+	labelWidth := utilities.GetMaxInt(0, width-row.getPrefixWidth())
+	labelLines := make([]string, 0)
+	if labelWidth > 0 {
+		labelLines = strings.Split(components.ViewChild(row.node.GetLabel(), ctx, labelWidth, row.height), "\n")
+	}
+
+	marker := leafMarker
+	switch {
+	case row.node.IsLoading():
+		marker = loadingMarker
+	case row.node.IsExpanded() && row.node.IsExpandable():
+		marker = expandedMarker
+	case row.node.IsExpandable():
+		marker = collapsedMarker
+	}
+
+	markerStyle := t.GetStyles().Marker
+	var labelStyle *lipgloss.Style
+	if rowIdx == t.getSelectedRowIdx(t.rowsCache) {
+		selectedStyle := t.GetStyles().Selected
+		if t.isFocused {
+			selectedStyle = t.GetStyles().FocusedSelected
+		}
+		markerStyle = selectedStyle
+		labelStyle = &selectedStyle
+	}
+
+	result := make([]string, row.height)
+	for lineIdx := range result {
+		labelLine := ""
+		if lineIdx < len(labelLines) {
+			labelLine = labelLines[lineIdx]
+		}
+		if labelStyle != nil {
+			labelLine = labelStyle.Render(labelLine)
+		}
+
+		line := t.GetStyles().Guide.Render(row.continuationGuide) + leafMarker + labelLine
+		if lineIdx == 0 {
+			line = t.GetStyles().Guide.Render(row.guide) + markerStyle.Render(marker) + labelLine
+		}
+		result[lineIdx] = control.FitToWidth(line, width)
+	}
+	return result
+}
+
+func (t *treeViewImpl) handleKey(msg tea.KeyMsg) tea.Cmd {
+	rows := t.getRows()
+	if len(rows) == 0 {
+		return nil
+	}
+	selectedIdx := t.getSelectedRowIdx(rows)
+	selectedNode := rows[selectedIdx].node
+
+	switch msg.Type {
+	case tea.KeyUp:
+		return t.selectRow(rows, selectedIdx-1)
+	case tea.KeyDown:
+		return t.selectRow(rows, selectedIdx+1)
+	case tea.KeyHome:
+		return t.selectRow(rows, 0)
+	case tea.KeyEnd:
+		return t.selectRow(rows, len(rows)-1)
+	case tea.KeyPgUp:
+		return t.selectRow(rows, selectedIdx-utilities.GetMaxInt(1, t.lastViewHeight))
+	case tea.KeyPgDown:
+		return t.selectRow(rows, selectedIdx+utilities.GetMaxInt(1, t.lastViewHeight))
+	case tea.KeyRight:
+		if !selectedNode.IsExpandable() {
+			return nil
+		}
+		if !selectedNode.IsExpanded() {
+			return t.Expand(selectedNode)
+		}
+		if len(selectedNode.GetChildren()) > 0 {
+			return t.selectRow(rows, selectedIdx+1)
+		}
+	case tea.KeyLeft:
+		if selectedNode.IsExpanded() && selectedNode.IsExpandable() {
+			t.Collapse(selectedNode)
+			return nil
+		}
+		if parentRowIdx := rows[selectedIdx].parentRowIdx; parentRowIdx != -1 {
+			return t.selectRow(rows, parentRowIdx)
+		}
+	case tea.KeyEnter, tea.KeySpace:
+		return t.toggle(selectedNode)
+	}
+	return nil
+}
+
+func (t *treeViewImpl) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if !components.IsMouseMsgInBounds(msg, t.lastViewWidth, t.lastViewHeight) {
+		return nil
+	}
+	rows := t.rowsCache
+
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		return t.selectRow(rows, t.getSelectedRowIdx(rows)-1)
+	case tea.MouseWheelDown:
+		return t.selectRow(rows, t.getSelectedRowIdx(rows)+1)
+	case tea.MouseLeft:
+		y := t.scrollOffset + msg.Y
+		for rowIdx, row := range rows {
+			if y < row.y || y >= row.y+row.height {
+				continue
+			}
+			markerX := lipgloss.Width(row.guide)
+			isOnMarker := y == row.y && msg.X >= markerX && msg.X < markerX+lipgloss.Width(leafMarker)
+
+			cmd := t.selectRow(rows, rowIdx)
+			if isOnMarker {
+				return tea.Batch(cmd, t.toggle(row.node))
+			}
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (t *treeViewImpl) toggle(node Node) tea.Cmd {
+	if node.IsExpanded() {
+		t.Collapse(node)
+		return nil
+	}
+	return t.Expand(node)
+}
+
+// Selects the row, calling the select handler if the selection changed
+func (t *treeViewImpl) selectRow(rows []row, rowIdx int) tea.Cmd {
+	if len(rows) == 0 {
+		return nil
+	}
+	node := rows[utilities.Clamp(rowIdx, 0, len(rows)-1)].node
+	if node == rows[t.getSelectedRowIdx(rows)].node && t.selected != nil {
+		return nil
+	}
+	t.selected = node
+	if t.selectHandler == nil {
+		return nil
+	}
+	return t.selectHandler(node)
+}
+
+func isDescendant(ancestor Node, node Node) bool {
+	for _, child := range ancestor.GetChildren() {
+		if child == node || isDescendant(child, node) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package tree_view
+
+import (
+	"errors"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/mieubrisse/box-layout-test/components/spinner"
+	"github.com/mieubrisse/box-layout-test/components/stylebox"
+	"github.com/mieubrisse/box-layout-test/components/test_assertions"
+	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/theme"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRendering(t *testing.T) {
+	tree, _ := getTestTree()
+
+	assertions := test_assertions.FlattenAssertionGroups(
+		test_assertions.GetDefaultAssertions(),
+		test_assertions.GetContentSizeAssertions(7, 7, 3, 3),
+		test_assertions.GetHeightAtWidthAssertions(
+			7, 3,
+			20, 3,
+		),
+		test_assertions.GetRenderedContentAssertion(7, 3, "▾ a    \n├──   b\n└── ▸ c"),
+		// Only the part of the tree around the selection shows when it's too short
+		test_assertions.GetRenderedContentAssertion(7, 2, "▾ a    \n├──   b"),
+	)
+
+	test_assertions.CheckAll(t, assertions, tree)
+}
+
+func TestMultiLineLabels(t *testing.T) {
+	tree := New(NewNode(text.New("root")).SetExpanded(true).AddChild(NewNode(text.New("hello world"))).AddChild(NewNode(text.New("x"))))
+
+	require.Equal(t, "▾ root      \n├──   hello \n│     world \n└──   x     ", render(tree, 12))
+}
+
+func TestKeyboardNavigation(t *testing.T) {
+	tree, nodes := getTestTree()
+	var selected []Node
+	tree.SetSelectHandler(func(node Node) tea.Cmd {
+		selected = append(selected, node)
+		return nil
+	})
+	render(tree, 11)
+
+	// Keys do nothing until the tree view is focused
+	tree.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.Equal(t, nodes["a"], tree.GetSelected())
+
+	tree.SetFocus(true)
+	tree.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	require.Equal(t, nodes["c"], tree.GetSelected())
+
+	// Right expands, then moves into the children
+	tree.Update(tea.KeyMsg{Type: tea.KeyRight})
+	require.True(t, nodes["c"].IsExpanded())
+	tree.Update(tea.KeyMsg{Type: tea.KeyRight})
+	require.Equal(t, nodes["d"], tree.GetSelected())
+	require.Equal(t, "▾ a        \n├──   b    \n└── ▾ c    \n    └──   d", render(tree, 11))
+
+	// Left moves to the parent, then collapses
+	tree.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	require.Equal(t, nodes["c"], tree.GetSelected())
+	tree.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	require.False(t, nodes["c"].IsExpanded())
+
+	// Collapsing a node hides its descendants, so the selection moves up to it
+	tree.Update(tea.KeyMsg{Type: tea.KeyUp})
+	tree.Update(tea.KeyMsg{Type: tea.KeyUp})
+	require.Equal(t, nodes["a"], tree.GetSelected())
+	tree.SetSelected(nodes["b"])
+	tree.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, nodes["b"], tree.GetSelected())
+	tree.SetSelected(nodes["a"])
+	tree.Update(tea.KeyMsg{Type: tea.KeySpace})
+	require.False(t, nodes["a"].IsExpanded())
+	require.Equal(t, "▸ a", render(tree, 3))
+
+	require.Equal(t, []Node{nodes["c"], nodes["d"], nodes["c"], nodes["b"], nodes["a"]}, selected)
+}
+
+func TestLazyLoading(t *testing.T) {
+	loadErr := errors.New("no connection")
+	var loadCount int
+	node := NewNode(text.New("remote")).SetLoader(func(node Node) tea.Cmd {
+		loadCount++
+		return func() tea.Msg {
+			if loadCount == 1 {
+				return LoadedMsg{Node: node, Children: nil, Err: loadErr}
+			}
+			return LoadedMsg{Node: node, Children: []Node{NewNode(text.New("child"))}, Err: nil}
+		}
+	})
+	tree := New(node)
+	tree.SetFocus(true)
+	require.True(t, node.IsExpandable())
+
+	msgs := test_assertions.GetMsgs(tree.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	require.True(t, node.IsLoading())
+	require.Equal(t, "⋯ remote", render(tree, 8))
+
+	// Expanding again while loading doesn't start a second load
+	tree.Update(tea.KeyMsg{Type: tea.KeySpace})
+	tree.Update(tea.KeyMsg{Type: tea.KeySpace})
+	require.Equal(t, 1, loadCount)
+
+	// A failed load collapses the node again
+	require.Len(t, msgs, 1)
+	tree.Update(msgs[0])
+	require.False(t, node.IsLoading())
+	require.False(t, node.IsExpanded())
+	require.Equal(t, loadErr, node.GetLoadError())
+	require.Equal(t, "▸ remote", render(tree, 8))
+
+	// ...and expanding it retries
+	msgs = test_assertions.GetMsgs(tree.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	require.Len(t, msgs, 1)
+	tree.Update(msgs[0])
+	require.Nil(t, node.GetLoadError())
+	require.Equal(t, "▾ remote     \n└──   child  ", render(tree, 13))
+
+	// Loaded children don't get loaded again
+	tree.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	require.Nil(t, tree.Update(tea.KeyMsg{Type: tea.KeyRight}))
+	require.Equal(t, 2, loadCount)
+}
+
+func TestMouse(t *testing.T) {
+	tree, nodes := getTestTree()
+	tree.SetSelectHandler(func(node Node) tea.Cmd {
+		return func() tea.Msg {
+			return node
+		}
+	})
+	render(tree, 7)
+
+	require.Equal(t, []tea.Msg{nodes["b"]}, test_assertions.GetMsgs(tree.Update(tea.MouseMsg{X: 6, Y: 1, Type: tea.MouseLeft})))
+	require.Equal(t, nodes["b"], tree.GetSelected())
+
+	// Clicking the marker toggles the node too
+	require.Equal(t, []tea.Msg{nodes["c"]}, test_assertions.GetMsgs(tree.Update(tea.MouseMsg{X: 4, Y: 2, Type: tea.MouseLeft})))
+	require.True(t, nodes["c"].IsExpanded())
+	render(tree, 11)
+
+	require.Equal(t, []tea.Msg{nodes["d"]}, test_assertions.GetMsgs(tree.Update(tea.MouseMsg{X: 0, Y: 0, Type: tea.MouseWheelDown})))
+
+	// Clicks outside the tree view are ignored
+	require.Nil(t, tree.Update(tea.MouseMsg{X: 3, Y: 4, Type: tea.MouseLeft}))
+	require.Equal(t, nodes["d"], tree.GetSelected())
+}
+
+func TestLabelsAreChildren(t *testing.T) {
+	loading := spinner.New().SetFrameSet(spinner.FrameSet{Frames: []string{"a", "b"}, Interval: time.Millisecond})
+	pods := stylebox.New(text.New("pods")).SetID("pods")
+	root := NewNode(text.New("root")).AddChild(NewNode(loading)).AddChild(NewNode(pods))
+	tree := New(root)
+
+	// Labels in collapsed subtrees still get broadcast messages, so they're up to date when they're shown
+	tickMsg := loading.Start()()
+	require.NotNil(t, tree.Update(tickMsg))
+	require.Equal(t, "b", loading.View(1, 1))
+
+	found, isFound := components.FindByID(tree, "pods")
+	require.True(t, isFound)
+	require.Equal(t, pods, found)
+
+	// The labels get the layout context
+	themed := New(NewNode(test_assertions.ThemeNameComponent{}))
+	ctx := theme.WithTheme(components.NewLayoutContext(), theme.New("mine", nil))
+	themed.GetContentMinMaxInContext(ctx)
+	require.Equal(t, "  mine", themed.ViewInContext(ctx, 6, themed.GetContentHeightForGivenWidthInContext(ctx, 6)))
+}
+
+// ====================================================================================================
+//                                   Private Helper Functions
+// ====================================================================================================
+
+// An expanded root "a" with children "b" & "c", where the collapsed "c" has child "d"
+func getTestTree() (TreeView, map[string]Node) {
+	nodes := map[string]Node{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		nodes[name] = NewNode(text.New(name))
+	}
+	nodes["c"].AddChild(nodes["d"])
+	nodes["a"].AddChild(nodes["b"]).AddChild(nodes["c"]).SetExpanded(true)
+	return New(nodes["a"]), nodes
+}
+
+func render(tree TreeView, width int) string {
+	tree.GetContentMinMax()
+	return tree.View(width, tree.GetContentHeightForGivenWidth(width))
+}
@@ -9,26 +9,24 @@ import (
 	"github.com/mieubrisse/box-layout-test/components/flexbox_item"
 	"github.com/mieubrisse/box-layout-test/components/stylebox"
 	"github.com/mieubrisse/box-layout-test/components/text"
+	"github.com/mieubrisse/box-layout-test/theme"
 	"os"
 )
 
-var red = lipgloss.Color("#FF0000")
-var blue = lipgloss.Color("#0000FF")
-var green = lipgloss.Color("#00FF00")
-var lightGray = lipgloss.Color("#333333")
-
-var text1Style = lipgloss.NewStyle().Foreground(red).Background(lightGray)
-var text2Style = lipgloss.NewStyle().Foreground(green).Border(lipgloss.NormalBorder())
-var text3Style = lipgloss.NewStyle().Foreground(blue).Background(lightGray)
+var text1Colors = theme.Colors{Foreground: theme.Danger, Background: theme.Selection}
+var text2Colors = theme.Colors{Foreground: theme.Success, BorderForeground: theme.Border}
+var text3Colors = theme.Colors{Foreground: theme.Primary, Background: theme.Selection}
 
 func main() {
-	text1 := stylebox.New(text.New("This is text 1")).SetStyle(text1Style)
-	text2 := stylebox.New(text.New("This is text 2")).SetStyle(text2Style)
+	text1 := stylebox.New(text.New("This is text 1")).SetThemeColors(text1Colors)
+	text2 := stylebox.New(text.New("This is text 2")).
+		SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder())).
+		SetThemeColors(text2Colors)
 	text3 := stylebox.New(
 		text.New("Four score and seven years ago our fathers brought forth on this continent, " +
 			"a new nation, conceived in Liberty, and dedicated to the proposition that all men " +
 			"are created equal.").
-			SetTextAlignment(text.AlignCenter)).SetStyle(text3Style)
+			SetTextAlignment(text.AlignCenter)).SetThemeColors(text3Colors)
 
 	yourBox := flexbox.NewWithContents(
 		flexbox_item.New(text1),
@@ -37,7 +35,9 @@ func main() {
 	).SetHorizontalAlignment(flexbox.AlignCenter).
 		SetVerticalAlignment(flexbox.AlignCenter).SetDirection(flexbox.Column)
 
-	appBox := stylebox.New(yourBox).SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder()))
+	appBox := stylebox.New(yourBox).
+		SetStyle(lipgloss.NewStyle().Border(lipgloss.NormalBorder())).
+		SetThemeColors(theme.Colors{BorderForeground: theme.Border})
 
 	if _, err := bubblebath.RunBubbleBathProgram(
 		appBox,
@@ -0,0 +1,35 @@
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+// Colors picks the token for each color property of a style, so that the style's colors follow the active theme
+// Properties left empty keep whatever the style already had
+type Colors struct {
+	Foreground Token
+	Background Token
+
+	BorderForeground Token
+	BorderBackground Token
+}
+
+// Apply gets a copy of the style with the colors from the theme
+func (c Colors) Apply(style lipgloss.Style, theme Theme) lipgloss.Style {
+	result := style.Copy()
+	if c.Foreground != "" {
+		result = result.Foreground(theme.Color(c.Foreground))
+	}
+	if c.Background != "" {
+		result = result.Background(theme.Color(c.Background))
+	}
+	if c.BorderForeground != "" {
+		result = result.BorderForeground(theme.Color(c.BorderForeground))
+	}
+	if c.BorderBackground != "" {
+		result = result.BorderBackground(theme.Color(c.BorderBackground))
+	}
+	return result
+}
+
+func (c Colors) IsEmpty() bool {
+	return c == Colors{}
+}
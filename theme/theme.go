@@ -0,0 +1,187 @@
+package theme
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"sync"
+)
+
+// Token is the semantic name of a color in a theme, which components reference rather than a specific color
+type Token string
+
+const (
+	// The color of plain text, and what's behind it
+	Text       Token = "text"
+	Background Token = "background"
+
+	// Used to draw attention, e.g. for headings and the focused control
+	Primary   Token = "primary"
+	Secondary Token = "secondary"
+
+	// Used for secondary information & decoration, e.g. placeholders and guide lines
+	Muted Token = "muted"
+
+	Success Token = "success"
+	Warning Token = "warning"
+	Danger  Token = "danger"
+
+	Border        Token = "border"
+	FocusedBorder Token = "focused-border"
+
+	Selection Token = "selection"
+	Disabled  Token = "disabled"
+)
+
+// Theme maps the tokens to colors
+type Theme struct {
+	name string
+
+	colors map[Token]lipgloss.TerminalColor
+}
+
+// ChangedMsg is sent to the app when the active theme changes (see bubblebath.SetTheme), so that components that cache
+// styles can rebuild them
+type ChangedMsg struct {
+	Theme Theme
+}
+
+func New(name string, colors map[Token]lipgloss.TerminalColor) Theme {
+	// Copied so that the theme can't be changed out from under the components using it
+	copiedColors := make(map[Token]lipgloss.TerminalColor, len(colors))
+	for token, color := range colors {
+		copiedColors[token] = color
+	}
+	return Theme{
+		name:   name,
+		colors: copiedColors,
+	}
+}
+
+func (t Theme) GetName() string {
+	return t.name
+}
+
+// Color gets the token's color, which is no color at all (i.e. the terminal's default) if the theme doesn't have one
+func (t Theme) Color(token Token) lipgloss.TerminalColor {
+	color, found := t.colors[token]
+	if !found {
+		return lipgloss.NoColor{}
+	}
+	return color
+}
+
+// With returns a copy of the theme with the token's color replaced, for tweaking the built-in themes
+func (t Theme) With(token Token, color lipgloss.TerminalColor) Theme {
+	result := New(t.name, t.colors)
+	result.colors[token] = color
+	return result
+}
+
+var lightColors = map[Token]lipgloss.Color{
+	Text:          "#1C1C1C",
+	Background:    "#FFFFFF",
+	Primary:       "#005FAF",
+	Secondary:     "#AF00AF",
+	Muted:         "#8A8A8A",
+	Success:       "#5F8700",
+	Warning:       "#AF5F00",
+	Danger:        "#D70000",
+	Border:        "#A8A8A8",
+	FocusedBorder: "#005FAF",
+	Selection:     "#D0D0D0",
+	Disabled:      "#A8A8A8",
+}
+
+var darkColors = map[Token]lipgloss.Color{
+	Text:          "#E4E4E4",
+	Background:    "#1C1C1C",
+	Primary:       "#5FAFFF",
+	Secondary:     "#D787D7",
+	Muted:         "#767676",
+	Success:       "#AFD75F",
+	Warning:       "#FFAF5F",
+	Danger:        "#FF5F5F",
+	Border:        "#585858",
+	FocusedBorder: "#5FAFFF",
+	Selection:     "#444444",
+	Disabled:      "#626262",
+}
+
+// Light is the built-in theme for terminals with light backgrounds
+func Light() Theme {
+	colors := make(map[Token]lipgloss.TerminalColor, len(lightColors))
+	for token, color := range lightColors {
+		colors[token] = color
+	}
+	return New("light", colors)
+}
+
+// Dark is the built-in theme for terminals with dark backgrounds
+func Dark() Theme {
+	colors := make(map[Token]lipgloss.TerminalColor, len(darkColors))
+	for token, color := range darkColors {
+		colors[token] = color
+	}
+	return New("dark", colors)
+}
+
+// Adaptive is the built-in theme that uses the light or dark theme's colors depending on the terminal's detected
+// background, and is the theme that's active to begin with
+func Adaptive() Theme {
+	colors := make(map[Token]lipgloss.TerminalColor, len(lightColors))
+	for token, lightColor := range lightColors {
+		colors[token] = lipgloss.AdaptiveColor{
+			Light: string(lightColor),
+			Dark:  string(darkColors[token]),
+		}
+	}
+	return New("adaptive", colors)
+}
+
+var activeTheme = Adaptive()
+var activeThemeMutex sync.RWMutex
+
+// GetActive gets the theme that components should resolve their tokens against when rendering
+func GetActive() Theme {
+	activeThemeMutex.RLock()
+	defer activeThemeMutex.RUnlock()
+	return activeTheme
+}
+
+// SetActive changes the active theme, which takes effect the next time the components render
+// Apps running in bubblebath should use bubblebath.SetTheme instead, so the components get told about the change
+func SetActive(theme Theme) {
+	activeThemeMutex.Lock()
+	defer activeThemeMutex.Unlock()
+	activeTheme = theme
+}
+
+// Color gets the token's color in the active theme
+func Color(token Token) lipgloss.TerminalColor {
+	return GetActive().Color(token)
+}
+
+// The key the theme gets stored under in the layout context
+type contextKey struct{}
+
+// ContextKey is the layout context key for the theme, for setting it without a context at hand (e.g. with
+// Stylebox.SetInheritedValue)
+var ContextKey = contextKey{}
+
+// WithTheme gets a copy of the layout context where the subtree uses the theme rather than the active one
+func WithTheme(ctx components.LayoutContext, theme Theme) components.LayoutContext {
+	return ctx.With(ContextKey, theme)
+}
+
+// FromContext gets the theme the nearest ancestor set in the layout context, or the active theme if none did
+func FromContext(ctx components.LayoutContext) Theme {
+	value, found := ctx.Get(ContextKey)
+	if !found {
+		return GetActive()
+	}
+	theme, ok := value.(Theme)
+	if !ok {
+		return GetActive()
+	}
+	return theme
+}
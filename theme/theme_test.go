@@ -0,0 +1,75 @@
+package theme
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mieubrisse/box-layout-test/components"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	custom := New("custom", map[Token]lipgloss.TerminalColor{
+		Primary: lipgloss.Color("#FF0000"),
+	})
+	require.Equal(t, "custom", custom.GetName())
+	require.Equal(t, lipgloss.Color("#FF0000"), custom.Color(Primary))
+
+	// Tokens the theme doesn't have fall back to the terminal's default
+	require.Equal(t, lipgloss.NoColor{}, custom.Color(Danger))
+
+	// Tweaking a theme leaves the original alone
+	tweaked := custom.With(Primary, lipgloss.Color("#00FF00"))
+	require.Equal(t, lipgloss.Color("#00FF00"), tweaked.Color(Primary))
+	require.Equal(t, lipgloss.Color("#FF0000"), custom.Color(Primary))
+}
+
+func TestBuiltInThemes(t *testing.T) {
+	tokens := []Token{
+		Text, Background, Primary, Secondary, Muted, Success, Warning, Danger, Border, FocusedBorder, Selection, Disabled,
+	}
+	for _, token := range tokens {
+		light, isLightColor := Light().Color(token).(lipgloss.Color)
+		require.True(t, isLightColor, "The light theme is missing token '%v'", token)
+		dark, isDarkColor := Dark().Color(token).(lipgloss.Color)
+		require.True(t, isDarkColor, "The dark theme is missing token '%v'", token)
+
+		require.Equal(
+			t,
+			lipgloss.AdaptiveColor{Light: string(light), Dark: string(dark)},
+			Adaptive().Color(token),
+		)
+	}
+}
+
+func TestActiveTheme(t *testing.T) {
+	defer SetActive(GetActive())
+
+	require.Equal(t, "adaptive", GetActive().GetName())
+
+	SetActive(Dark())
+	require.Equal(t, "dark", GetActive().GetName())
+	require.Equal(t, Dark().Color(Primary), Color(Primary))
+}
+
+func TestThemeFromContext(t *testing.T) {
+	ctx := components.NewLayoutContext()
+	require.Equal(t, GetActive().GetName(), FromContext(ctx).GetName())
+
+	ctx = WithTheme(ctx, Light())
+	require.Equal(t, "light", FromContext(ctx).GetName())
+}
+
+func TestApplyColors(t *testing.T) {
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color("#123456")).Border(lipgloss.NormalBorder())
+	colors := Colors{Background: Selection, BorderForeground: FocusedBorder}
+
+	styled := colors.Apply(base, Light())
+	require.Equal(t, Light().Color(Selection), styled.GetBackground())
+	require.Equal(t, Light().Color(FocusedBorder), styled.GetBorderTopForeground())
+
+	// The properties without a token keep the style's colors
+	require.Equal(t, lipgloss.Color("#123456"), styled.GetForeground())
+
+	require.True(t, Colors{}.IsEmpty())
+	require.False(t, colors.IsEmpty())
+}